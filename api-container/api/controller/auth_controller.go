@@ -1,6 +1,233 @@
 package controller
 
-func GetLoginUserId() (uint64, error) {
-	// TODO セッションから取得
-	return 1, nil
-}
\ No newline at end of file
+import (
+	"api/model"
+	"api/usecase"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ログイン中のUserのidをecho.Contextに格納する際のキー
+const loginUserIdKey = "loginUserId"
+
+// Sessionのトークンを格納するCookieの名前
+const sessionCookieName = "session_id"
+
+type IAuthController interface {
+	SignUp(c echo.Context) error
+	LogIn(c echo.Context) error
+	LogOut(c echo.Context) error
+	GetLoginUser(c echo.Context) error
+	IssueToken(c echo.Context) error
+	RevokeToken(c echo.Context) error
+	RequireLogin(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type AuthController struct {
+	atu *usecase.AuthUsecase
+}
+
+func NewAuthController(atu *usecase.AuthUsecase) IAuthController {
+	return &AuthController{atu}
+}
+
+func GetLoginUserId(c echo.Context) (uint64, error) {
+	// RequireLoginでecho.Contextに格納されたUserのidを取得
+	loginUserId, ok := c.Get(loginUserIdKey).(uint64)
+	if !ok || loginUserId == 0 {
+		return 0, usecase.ErrUnauthenticated
+	}
+
+	return loginUserId, nil
+}
+
+func SetLoginUserId(c echo.Context, loginUserId uint64) {
+	c.Set(loginUserIdKey, loginUserId)
+}
+
+func (ac *AuthController) SignUp(c echo.Context) error {
+	var req model.UserSignUpRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userSignUp := model.UserSignUp{
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	user, err := ac.atu.SignUp(userSignUp)
+	if err != nil {
+		return err
+	}
+
+	userRes := model.UserResponse{
+		Id:    user.Id,
+		Email: user.Email,
+	}
+
+	return c.JSON(http.StatusCreated, userRes)
+}
+
+func (ac *AuthController) LogIn(c echo.Context) error {
+	var req model.UserLogInRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userLogIn := model.UserLogIn{
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	issuedSession, err := ac.atu.LogIn(userLogIn)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(newSessionCookie(issuedSession.Token, issuedSession.ExpiresAt))
+
+	user, err := ac.atu.GetUserById(issuedSession.UserId)
+	if err != nil {
+		return err
+	}
+
+	userRes := model.UserResponse{
+		Id:    user.Id,
+		Email: user.Email,
+	}
+
+	return c.JSON(http.StatusOK, userRes)
+}
+
+func (ac *AuthController) LogOut(c echo.Context) error {
+	cookie, err := c.Cookie(sessionCookieName)
+	if err == nil {
+		err = ac.atu.LogOut(cookie.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	// ログインしていない場合もCookieを削除して正常終了とする
+	c.SetCookie(newSessionCookie("", time.Unix(0, 0)))
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ac *AuthController) GetLoginUser(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := ac.atu.GetUserById(loginUserId)
+	if err != nil {
+		return err
+	}
+
+	userRes := model.UserResponse{
+		Id:    user.Id,
+		Email: user.Email,
+	}
+
+	return c.JSON(http.StatusOK, userRes)
+}
+
+func (ac *AuthController) IssueToken(c echo.Context) error {
+	var req model.TokenRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	issuedTokens, err := ac.atu.IssueTokens(req)
+	if err != nil {
+		return err
+	}
+
+	tokenRes := model.TokenResponse{
+		AccessToken:  issuedTokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    uint64(usecase.AccessTokenLifetime.Seconds()),
+		RefreshToken: issuedTokens.RefreshToken,
+	}
+
+	return c.JSON(http.StatusOK, tokenRes)
+}
+
+func (ac *AuthController) RevokeToken(c echo.Context) error {
+	var req model.TokenRevocationRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	err := ac.atu.RevokeRefreshToken(req.RefreshToken)
+	if err != nil && !errors.Is(err, usecase.ErrInvalidToken) {
+		return err
+	}
+
+	// 存在しないトークンが指定された場合も正常終了とする
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ac *AuthController) RequireLogin(next echo.HandlerFunc) echo.HandlerFunc {
+	// 有効なSessionまたはアクセストークンが無いリクエストに401を返すミドルウェア
+	// 有効な場合、ログイン中のUserのidをecho.Contextに格納する
+	return func(c echo.Context) error {
+		var loginUserId uint64
+		var err error
+
+		authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+		if authorization != "" {
+			// Authorizationヘッダがある場合はCookieを参照せず、アクセストークンのみで認証する
+			accessToken, ok := parseBearerToken(authorization)
+			if !ok {
+				return usecase.ErrInvalidToken
+			}
+
+			loginUserId, err = ac.atu.GetLoginUserIdByAccessToken(accessToken)
+		} else {
+			cookie, cookieErr := c.Cookie(sessionCookieName)
+			if cookieErr != nil || cookie.Value == "" {
+				return usecase.ErrUnauthenticated
+			}
+
+			loginUserId, err = ac.atu.GetLoginUserIdBySessionToken(cookie.Value)
+		}
+		if err != nil {
+			return err
+		}
+
+		SetLoginUserId(c, loginUserId)
+
+		return next(c)
+	}
+}
+
+func parseBearerToken(authorization string) (string, bool) {
+	// "Bearer <token>" からトークンを取り出す
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+func newSessionCookie(token string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		// HTTPSで配信する環境ではCOOKIE_SECURE=trueを設定する
+		Secure:   os.Getenv("COOKIE_SECURE") == "true",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
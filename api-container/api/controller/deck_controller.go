@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IDeckController interface {
+	GetAllDecks(c echo.Context) error
+	GetDeckById(c echo.Context) error
+	CreateDeck(c echo.Context) error
+	UpdateDeck(c echo.Context) error
+	DeleteDeck(c echo.Context) error
+	AddWordToDeck(c echo.Context) error
+	RemoveWordFromDeck(c echo.Context) error
+	AddSentenceToDeck(c echo.Context) error
+	RemoveSentenceFromDeck(c echo.Context) error
+}
+
+type DeckController struct {
+	du *usecase.DeckUsecase
+}
+
+func NewDeckController(du *usecase.DeckUsecase) IDeckController {
+	return &DeckController{du}
+}
+
+func (dc *DeckController) GetAllDecks(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	decks, err := dc.du.GetAllDecks(loginUserId)
+	if err != nil {
+		return err
+	}
+
+	// デッキが1件も無い場合も、nullではなく[]を返す
+	// 階層構造は、各デッキのparent_idからクライアントで組み立てる
+	deckResponses := []model.DeckResponse{}
+	for _, deck := range decks {
+		deckResponses = append(deckResponses, toDeckResponse(deck))
+	}
+
+	return c.JSON(http.StatusOK, deckResponses)
+}
+
+func (dc *DeckController) GetDeckById(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	deckId, err := parseIdParam(c, "deckId")
+	if err != nil {
+		return err
+	}
+
+	deck, err := dc.du.GetDeckById(loginUserId, deckId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toDeckResponse(deck))
+}
+
+func (dc *DeckController) CreateDeck(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.DeckRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	deckCreation := model.DeckCreation{
+		Name:             req.Name,
+		ParentId:         req.ParentId,
+		ScopeAssociation: req.ScopeAssociation,
+		LoginUserId:      loginUserId,
+	}
+
+	deck, err := dc.du.CreateDeck(deckCreation)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toDeckResponse(deck))
+}
+
+func (dc *DeckController) UpdateDeck(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.DeckRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	deckId, err := parseIdParam(c, "deckId")
+	if err != nil {
+		return err
+	}
+
+	deckUpdate := model.DeckUpdate{
+		Id:               deckId,
+		Name:             req.Name,
+		ParentId:         req.ParentId,
+		ScopeAssociation: req.ScopeAssociation,
+		LoginUserId:      loginUserId,
+	}
+
+	deck, err := dc.du.UpdateDeck(deckUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toDeckResponse(deck))
+}
+
+func (dc *DeckController) DeleteDeck(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	deckId, err := parseIdParam(c, "deckId")
+	if err != nil {
+		return err
+	}
+
+	deck, err := dc.du.DeleteDeck(loginUserId, deckId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toDeckResponse(deck))
+}
+
+func (dc *DeckController) AddWordToDeck(c echo.Context) error {
+	return dc.changeDeckItem(c, "wordId", dc.du.AddWordToDeck)
+}
+
+func (dc *DeckController) RemoveWordFromDeck(c echo.Context) error {
+	return dc.changeDeckItem(c, "wordId", dc.du.RemoveWordFromDeck)
+}
+
+func (dc *DeckController) AddSentenceToDeck(c echo.Context) error {
+	return dc.changeDeckItem(c, "sentenceId", dc.du.AddSentenceToDeck)
+}
+
+func (dc *DeckController) RemoveSentenceFromDeck(c echo.Context) error {
+	return dc.changeDeckItem(c, "sentenceId", dc.du.RemoveSentenceFromDeck)
+}
+
+func (dc *DeckController) changeDeckItem(c echo.Context, itemParam string, change func(loginUserId, deckId, itemId uint64) (model.DeckItem, error)) error {
+	// デッキへのWordまたはSentenceの追加、削除
+	// itemParamは、追加または削除するもののIdを表すパスパラメータ名
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	deckId, err := parseIdParam(c, "deckId")
+	if err != nil {
+		return err
+	}
+
+	itemId, err := parseIdParam(c, itemParam)
+	if err != nil {
+		return err
+	}
+
+	deckItem, err := change(loginUserId, deckId, itemId)
+	if err != nil {
+		return err
+	}
+
+	deckItemRes := model.DeckItemResponse{
+		DeckId:             deckItem.DeckId,
+		WordId:             deckItem.WordId,
+		SentenceId:         deckItem.SentenceId,
+		AssociationPending: deckItem.AssociationJobId != 0,
+		AssociationJobId:   deckItem.AssociationJobId,
+	}
+
+	return c.JSON(http.StatusAccepted, deckItemRes)
+}
+
+func toDeckResponse(deck model.Deck) model.DeckResponse {
+	return model.DeckResponse{
+		Id:                 deck.Id,
+		Name:               deck.Name,
+		UserId:             deck.UserId,
+		ParentId:           deck.ParentId,
+		ScopeAssociation:   deck.ScopeAssociation,
+		AssociationPending: deck.AssociationJobId != 0,
+		AssociationJobId:   deck.AssociationJobId,
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// usecase.ErrorKindごとのHTTPステータス
+var errorKindStatuses = map[usecase.ErrorKind]int{
+	usecase.ErrorKindNotFound:        http.StatusNotFound,
+	usecase.ErrorKindForbidden:       http.StatusForbidden,
+	usecase.ErrorKindValidation:      http.StatusUnprocessableEntity,
+	usecase.ErrorKindConflict:        http.StatusConflict,
+	usecase.ErrorKindUnauthenticated: http.StatusUnauthorized,
+}
+
+func HTTPErrorHandler(err error, c echo.Context) {
+	// Controllerやミドルウェアが返したエラーを、共通の形式のレスポンスにする
+	if c.Response().Committed {
+		return
+	}
+
+	status, errRes := toErrorResponse(err)
+	if status >= http.StatusInternalServerError {
+		// 内部のエラーはクライアントに返さず、ログにのみ出力する
+		c.Logger().Error(err)
+	}
+	errRes.RequestId = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, errRes)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+func toErrorResponse(err error) (int, model.ErrorResponse) {
+	var usecaseErr *usecase.Error
+	if errors.As(err, &usecaseErr) {
+		status, ok := errorKindStatuses[usecaseErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+
+		return status, model.ErrorResponse{
+			Code:    string(usecaseErr.Kind),
+			Message: usecaseErr.Message,
+			Details: usecaseErr.Details,
+		}
+	}
+
+	// リクエストボディの解析やルーティングなど、Echoが返したエラー
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		message, ok := httpErr.Message.(string)
+		if !ok {
+			message = http.StatusText(httpErr.Code)
+		}
+
+		return httpErr.Code, model.ErrorResponse{
+			Code:    toErrorCode(httpErr.Code),
+			Message: message,
+		}
+	}
+
+	return http.StatusInternalServerError, model.ErrorResponse{
+		Code:    "internal_error",
+		Message: "internal server error",
+	}
+}
+
+func toErrorCode(status int) string {
+	// "Bad Request" -> "bad_request" のように、HTTPステータスの説明からcodeを作る
+	if status == http.StatusUnauthorized {
+		return string(usecase.ErrorKindUnauthenticated)
+	}
+
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func parseIdParam(c echo.Context, name string) (uint64, error) {
+	// パスパラメータのidを取得
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be a positive integer", name))
+	}
+
+	return id, nil
+}
+
+func parseListFilterParams(c echo.Context) (model.ListFilter, error) {
+	// クエリパラメータ ?tag_id=1&deck_id=2 で一覧を絞り込む
+	// 指定されない項目では絞り込まない
+	var filter model.ListFilter
+	for _, param := range []struct {
+		name string
+		dest *uint64
+	}{
+		{"tag_id", &filter.TagId},
+		{"deck_id", &filter.DeckId},
+	} {
+		value := c.QueryParam(param.name)
+		if value == "" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			return model.ListFilter{}, usecase.NewValidationError(fmt.Sprintf("invalid %s: %s", param.name, value), nil)
+		}
+		*param.dest = id
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IJobController interface {
+	GetJobById(c echo.Context) error
+}
+
+type JobController struct {
+	ju *usecase.JobUsecase
+}
+
+func NewJobController(ju *usecase.JobUsecase) IJobController {
+	return &JobController{ju}
+}
+
+func (jc *JobController) GetJobById(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	jobId, err := parseIdParam(c, "jobId")
+	if err != nil {
+		return err
+	}
+
+	job, err := jc.ju.GetJobById(loginUserId, jobId)
+	if err != nil {
+		return err
+	}
+
+	// 対象が無い場合も、nullではなく[]を返す
+	targetIds := job.TargetIds
+	if targetIds == nil {
+		targetIds = []uint64{}
+	}
+
+	jobRes := model.JobResponse{
+		Id:          job.Id,
+		Kind:        job.Kind,
+		TargetIds:   targetIds,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		LastError:   job.LastError,
+		RunAt:       job.RunAt,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+
+	return c.JSON(http.StatusOK, jobRes)
+}
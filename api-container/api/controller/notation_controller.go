@@ -3,9 +3,8 @@ package controller
 import (
 	"api/model"
 	"api/usecase"
-	"database/sql"
+	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -26,19 +25,31 @@ func NewNotationController(wu *usecase.WordUsecase) INotationController {
 }
 
 func (nc *NotationController) GetAllNotations(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	notations, err := nc.wu.GetAllNotations(loginUserId, wordId)
+	// クエリパラメータ ?kind=manual などが指定された場合、
+	// 指定された作成元のNotationのみ返す
+	var notations []model.Notation
+	kind := c.QueryParam("kind")
+	if kind != "" {
+		if !model.IsValidNotationKind(kind) {
+			return usecase.NewValidationError(fmt.Sprintf("invalid kind: %s", kind), nil)
+		}
+
+		notations, err = nc.wu.GetNotationsByKind(loginUserId, wordId, kind)
+	} else {
+		notations, err = nc.wu.GetAllNotations(loginUserId, wordId)
+	}
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var notationResponses []model.NotationResponse
@@ -47,6 +58,7 @@ func (nc *NotationController) GetAllNotations(c echo.Context) error {
 			Id: notation.Id,
 			WordId: notation.WordId,
 			Notation: notation.Notation,
+			Kind: notation.Kind,
 		}
 		notationResponses = append(notationResponses, notationRes)
 	}
@@ -55,67 +67,59 @@ func (nc *NotationController) GetAllNotations(c echo.Context) error {
 }
 
 func (nc *NotationController) CreateNotation(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.NotationCreationRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 	
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	notationCreation := model.NotationCreation{
 		WordId: wordId,
 		Notation: req.Notation,
+		Kind: model.NotationKindManual,
 		LoginUserId: loginUserId,
 	}
 
 	notation, err := nc.wu.CreateNotation(notationCreation)
 	if err != nil {
-		// TODO：Notationが既に存在する意外の要因でErrNoRowsが発生する可能性も否定できないため、
-		// 独自エラーを発生させるなどし、重複の場合にだけStatusConflictを返すよう修正
-		if err == sql.ErrNoRows {
-			return c.JSON(http.StatusConflict, make(map[string]interface{}))
-		}
-
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if(notation == model.Notation{}) {
-		// usecaseで更新した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
 	notationRes := model.NotationResponse{
 		Id: notation.Id,
 		WordId: notation.WordId,
 		Notation: notation.Notation,
+		Kind: notation.Kind,
+		AssociationPending: notation.AssociationJobId != 0,
+		AssociationJobId: notation.AssociationJobId,
 	}
 	
 	return c.JSON(http.StatusCreated, notationRes)
 }
 
 func (nc *NotationController) UpdateNotation(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.NotationUpdateRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
-	notationId, err := strconv.ParseUint(c.Param("notationId"), 10, 32)
+	notationId, err := parseIdParam(c, "notationId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	
 	notationUpdate := model.NotationUpdate{
@@ -126,50 +130,44 @@ func (nc *NotationController) UpdateNotation(c echo.Context) error {
 
 	notation, err := nc.wu.UpdateNotation(notationUpdate)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if(notation == model.Notation{}) {
-		// usecaseで更新した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
 	notationRes := model.NotationResponse{
 		Id: notation.Id,
 		WordId: notation.WordId,
 		Notation: notation.Notation,
+		Kind: notation.Kind,
+		AssociationPending: notation.AssociationJobId != 0,
+		AssociationJobId: notation.AssociationJobId,
 	}
 	
 	return c.JSON(http.StatusAccepted, notationRes)
 }
 
 func (nc *NotationController) DeleteNotation(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	notationId, err := strconv.ParseUint(c.Param("notationId"), 10, 32)
+	notationId, err := parseIdParam(c, "notationId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	notation, err := nc.wu.DeleteNotation(loginUserId, notationId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if(notation == model.Notation{}) {
-		// usecaseで削除した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
 	notationRes := model.NotationResponse{
 		Id: notation.Id,
 		WordId: notation.WordId,
 		Notation: notation.Notation,
+		Kind: notation.Kind,
+		AssociationPending: notation.AssociationJobId != 0,
+		AssociationJobId: notation.AssociationJobId,
 	}
 	
 	return c.JSON(http.StatusAccepted, notationRes)
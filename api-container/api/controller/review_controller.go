@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IReviewController interface {
+	GetDueReviews(c echo.Context) error
+	ReviewWord(c echo.Context) error
+}
+
+type ReviewController struct {
+	rvu *usecase.ReviewUsecase
+}
+
+func NewReviewController(rvu *usecase.ReviewUsecase) IReviewController {
+	return &ReviewController{rvu}
+}
+
+func (rvc *ReviewController) GetDueReviews(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	limitParam := c.QueryParam("limit")
+	limit, err := strconv.ParseUint(limitParam, 10, 64)
+	if err != nil {
+		limit = 0
+	}
+
+	dueReviews, err := rvc.rvu.GetDueReviews(loginUserId, limit)
+	if err != nil {
+		return err
+	}
+
+	// 復習するWordが無い場合も、nullではなく[]を返す
+	dueReviewResponses := []model.DueReviewResponse{}
+	for _, dueReview := range dueReviews {
+		word := dueReview.Word
+
+		var reviewRes *model.ReviewResponse
+		if dueReview.Review != nil {
+			res := toReviewResponse(*dueReview.Review)
+			reviewRes = &res
+		}
+
+		sentenceResponses := []model.ReviewSentenceResponse{}
+		for _, reviewSentence := range dueReview.Sentences {
+			sentenceRes := model.ReviewSentenceResponse{
+				Id:       reviewSentence.Sentence.Id,
+				Sentence: reviewSentence.Sentence.Sentence,
+				Cloze:    reviewSentence.Cloze,
+				UserId:   reviewSentence.Sentence.UserId,
+			}
+			sentenceResponses = append(sentenceResponses, sentenceRes)
+		}
+
+		dueReviewRes := model.DueReviewResponse{
+			Word: model.WordResponse{
+				Id:     word.Id,
+				Word:   word.Word,
+				Memo:   word.Memo,
+				UserId: word.UserId,
+			},
+			Review:    reviewRes,
+			Sentences: sentenceResponses,
+		}
+		dueReviewResponses = append(dueReviewResponses, dueReviewRes)
+	}
+
+	return c.JSON(http.StatusOK, dueReviewResponses)
+}
+
+func (rvc *ReviewController) ReviewWord(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.ReviewRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	reviewCreation := model.ReviewCreation{
+		WordId:      wordId,
+		Grade:       *req.Grade,
+		LoginUserId: loginUserId,
+	}
+
+	review, err := rvc.rvu.ReviewWord(reviewCreation)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toReviewResponse(review))
+}
+
+func toReviewResponse(review model.Review) model.ReviewResponse {
+	return model.ReviewResponse{
+		WordId:         review.WordId,
+		EaseFactor:     review.EaseFactor,
+		IntervalDays:   review.IntervalDays,
+		Repetitions:    review.Repetitions,
+		DueAt:          review.DueAt,
+		LastGrade:      review.LastGrade,
+		LastReviewedAt: review.LastReviewedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ISearchController interface {
+	Search(c echo.Context) error
+}
+
+type SearchController struct {
+	seu *usecase.SearchUsecase
+}
+
+func NewSearchController(seu *usecase.SearchUsecase) ISearchController {
+	return &SearchController{seu}
+}
+
+func (sec *SearchController) Search(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	limitParam := c.QueryParam("limit")
+	limit, err := strconv.ParseUint(limitParam, 10, 64)
+	if err != nil {
+		limit = 0
+	}
+
+	offsetParam := c.QueryParam("offset")
+	offset, err := strconv.ParseUint(offsetParam, 10, 64)
+	if err != nil {
+		offset = 0
+	}
+
+	query := strings.TrimSpace(c.QueryParam("q"))
+
+	searchQuery := model.SearchQuery{
+		LoginUserId: loginUserId,
+		Query:       query,
+		Limit:       limit,
+		Offset:      offset,
+	}
+
+	searchResult, err := sec.seu.Search(searchQuery)
+	if err != nil {
+		return err
+	}
+
+	// 一致するものが無い場合も、nullではなく[]を返す
+	wordSearchHitResponses := []model.WordSearchHitResponse{}
+	for _, wordSearchHit := range searchResult.Words {
+		word := wordSearchHit.Word
+
+		matchedNotations := []string{}
+		for _, notation := range wordSearchHit.MatchedNotations {
+			matchedNotations = append(matchedNotations, usecase.HighlightMatches(notation.Notation, query))
+		}
+
+		wordSearchHitRes := model.WordSearchHitResponse{
+			Id:               word.Id,
+			Word:             word.Word,
+			Memo:             word.Memo,
+			UserId:           word.UserId,
+			Score:            wordSearchHit.Score,
+			HighlightedWord:  usecase.HighlightMatches(word.Word, query),
+			HighlightedMemo:  usecase.HighlightMatches(word.Memo, query),
+			MatchedNotations: matchedNotations,
+		}
+		wordSearchHitResponses = append(wordSearchHitResponses, wordSearchHitRes)
+	}
+
+	// クエリパラメータ ?with-html=true の場合、
+	// リンクをaタグにしたHTMLもレスポンスに含める
+	withHTML := c.QueryParam("with-html") == "true"
+
+	sentenceSearchHitResponses := []model.SentenceSearchHitResponse{}
+	for _, sentenceSearchHit := range searchResult.Sentences {
+		sentence := sentenceSearchHit.Sentence
+
+		sentenceSearchHitRes := model.SentenceSearchHitResponse{
+			Id:                  sentence.Id,
+			Sentence:            sentence.Sentence,
+			Annotations:         toLinkAnnotationResponses(sentenceSearchHit.Annotations),
+			HighlightedSentence: usecase.HighlightMatches(sentence.Sentence, query),
+			UserId:              sentence.UserId,
+			Score:               sentenceSearchHit.Score,
+		}
+		if withHTML {
+			sentenceSearchHitRes.SentenceWithLink = usecase.RenderSentenceWithLink(
+				sentence.Sentence,
+				sentenceSearchHit.Annotations,
+			)
+		}
+		sentenceSearchHitResponses = append(sentenceSearchHitResponses, sentenceSearchHitRes)
+	}
+
+	searchRes := model.SearchResponse{
+		Words:               wordSearchHitResponses,
+		WordsTotalCount:     searchResult.WordsTotalCount,
+		Sentences:           sentenceSearchHitResponses,
+		SentencesTotalCount: searchResult.SentencesTotalCount,
+	}
+
+	return c.JSON(http.StatusOK, searchRes)
+}
@@ -18,6 +18,9 @@ type ISentenceController interface {
 	DeleteSentence(c echo.Context) error
 	GetAssociatedWords(c echo.Context) error
 	GetSentencesCount(c echo.Context) error
+	GetAssociationOverrides(c echo.Context) error
+	SetAssociationOverride(c echo.Context) error
+	DeleteAssociationOverride(c echo.Context) error
 }
 
 type SentenceController struct {
@@ -33,9 +36,9 @@ func NewSentenceController(
 }
 
 func (sc *SentenceController) GetAllSentences(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	limitParam := c.QueryParam("limit")
@@ -50,19 +53,23 @@ func (sc *SentenceController) GetAllSentences(c echo.Context) error {
 		offset = 0
 	}
 
-	sentencesWithLink, err := sc.au.GetAllSentencesWithLink(loginUserId, limit, offset)
+	filter, err := parseSentenceListFilterParams(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
+	sentencesWithLink, err := sc.au.GetAllSentencesWithLink(loginUserId, filter, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	// クエリパラメータ ?with-html=true の場合、
+	// リンクをaタグにしたHTMLもレスポンスに含める
+	withHTML := c.QueryParam("with-html") == "true"
+
 	var sentenceWithLinkResponses []model.SentenceWithLinkResponse
 	for _, sentenceWithLink := range sentencesWithLink {
-		res := model.SentenceWithLinkResponse{
-			Id:       sentenceWithLink.Id,
-			Sentence: sentenceWithLink.Sentence,
-			SentenceWithLink: sentenceWithLink.SentenceWithLink,
-			UserId:   sentenceWithLink.UserId,
-		}
+		res := toSentenceWithLinkResponse(sentenceWithLink, withHTML)
 		sentenceWithLinkResponses = append(sentenceWithLinkResponses, res)
 	}
 
@@ -70,98 +77,113 @@ func (sc *SentenceController) GetAllSentences(c echo.Context) error {
 }
 
 func (sc *SentenceController) GetSentenceById(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	sentenceId, err := strconv.ParseUint(c.Param("sentenceId"), 10, 32)
+	sentenceId, err := parseIdParam(c, "sentenceId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentence, err := sc.su.GetSentenceById(loginUserId, sentenceId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if (sentence == model.Sentence{}) {
-		// usecaseで取得した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusOK, make(map[string]interface{}))
+		return err
 	}
 
 	sentenceRes := model.SentenceResponse{
-		Id:       sentence.Id,
-		Sentence: sentence.Sentence,
-		UserId:   sentence.UserId,
+		Id:                 sentence.Id,
+		Sentence:           sentence.Sentence,
+		Translations:       toSentenceTranslationResponses(sentence.Translations),
+		Source:             toSentenceSourceResponse(sentence.Source),
+		Notes:              sentence.Notes,
+		UserId:             sentence.UserId,
+		AssociationPending: sentence.AssociationJobId != 0,
+		AssociationJobId:   sentence.AssociationJobId,
 	}
 	return c.JSON(http.StatusOK, sentenceRes)
 }
 
 func (sc *SentenceController) CreateSentence(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.SentenceCreationRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
 	sentenceCreation := model.SentenceCreation{
-		Sentence: req.Sentence,
-		LoginUserId:   loginUserId,
+		Sentence:     req.Sentence,
+		Translations: toSentenceTranslations(req.Translations),
+		Source:       toSentenceSource(req.Source),
+		Notes:        req.Notes,
+		LoginUserId:  loginUserId,
 	}
 
 	sentence, err := sc.su.CreateSentence(sentenceCreation)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentenceRes := model.SentenceResponse{
-		Id:       sentence.Id,
-		Sentence: sentence.Sentence,
-		UserId:   sentence.UserId,
+		Id:                 sentence.Id,
+		Sentence:           sentence.Sentence,
+		Translations:       toSentenceTranslationResponses(sentence.Translations),
+		Source:             toSentenceSourceResponse(sentence.Source),
+		Notes:              sentence.Notes,
+		UserId:             sentence.UserId,
+		AssociationPending: sentence.AssociationJobId != 0,
+		AssociationJobId:   sentence.AssociationJobId,
 	}
 
 	return c.JSON(http.StatusCreated, sentenceRes)
 }
 
 func (sc *SentenceController) CreateMultipleSentences(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	// TODO: words[]の中に不適切な形式のデータが入っていた場合、
-	// すべてを登録失敗とするのではなく、不適切なデータのみを弾く実装にする
+	// sentences[]の中に不適切な形式のデータが1件でも入っていた場合、すべてを登録失敗とする
 	var req model.MultipleSentencesCreationRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
 	var sentenceCreations []model.SentenceCreation
 	for _, sentenceCreationReq := range req.Sentences {
 		sentenceCreation := model.SentenceCreation{
-			Sentence: sentenceCreationReq.Sentence,
-			LoginUserId:   loginUserId,
+			Sentence:     sentenceCreationReq.Sentence,
+			Translations: toSentenceTranslations(sentenceCreationReq.Translations),
+			Source:       toSentenceSource(sentenceCreationReq.Source),
+			Notes:        sentenceCreationReq.Notes,
+			LoginUserId:  loginUserId,
 		}
 		sentenceCreations = append(sentenceCreations, sentenceCreation)
 	} 
 
 	sentences, err := sc.su.CreateMultipleSentences(sentenceCreations)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		// 不正な項目があった場合、どれも作成されず項目ごとのエラーがdetailsとして返る
+		return err
 	}
 
 	var sentenceResponses []model.SentenceResponse
 	for _, sentence := range sentences {
 		sentenceRes := model.SentenceResponse{
-			Id:       sentence.Id,
-			Sentence: sentence.Sentence,
-			UserId:   sentence.UserId,
+			Id:                 sentence.Id,
+			Sentence:           sentence.Sentence,
+			Translations:       toSentenceTranslationResponses(sentence.Translations),
+			Source:             toSentenceSourceResponse(sentence.Source),
+			Notes:              sentence.Notes,
+			UserId:             sentence.UserId,
+			AssociationPending: sentence.AssociationJobId != 0,
+			AssociationJobId:   sentence.AssociationJobId,
 		}
 		sentenceResponses = append(sentenceResponses, sentenceRes)
 	}
@@ -170,36 +192,33 @@ func (sc *SentenceController) CreateMultipleSentences(c echo.Context) error {
 }
 
 func (sc *SentenceController) UpdateSentence(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.SentenceUpdateRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
-	sentenceId, err := strconv.ParseUint(c.Param("sentenceId"), 10, 32)
+	sentenceId, err := parseIdParam(c, "sentenceId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentenceUpdate := model.SentenceUpdate{
-		Id:       sentenceId,
-		Sentence: req.Sentence,
-		LoginUserId:   loginUserId,
+		Id:           sentenceId,
+		Sentence:     req.Sentence,
+		Translations: toSentenceTranslations(req.Translations),
+		Source:       toSentenceSource(req.Source),
+		Notes:        req.Notes,
+		LoginUserId:  loginUserId,
 	}
 
 	sentence, err := sc.su.UpdateSentence(sentenceUpdate)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if (sentence == model.Sentence{}) {
-		// usecaseで更新した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
 	// クエリパラメータ ?with-link=true の場合、
@@ -207,22 +226,22 @@ func (sc *SentenceController) UpdateSentence(c echo.Context) error {
 	if(c.QueryParam("with-link") == "true") {
 		sentenceWithLink, err := sc.au.GetSentenceWithLinkById(loginUserId, sentenceId)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, err.Error())
+			return err
 		}
 
-		sentenceWithLinkRes := model.SentenceWithLinkResponse{
-			Id: sentenceWithLink.Id,
-			Sentence: sentenceWithLink.Sentence,
-			SentenceWithLink: sentenceWithLink.SentenceWithLink,
-			UserId: sentenceWithLink.UserId,
-		}
+		sentenceWithLinkRes := toSentenceWithLinkResponse(sentenceWithLink, c.QueryParam("with-html") == "true")
 
 		return c.JSON(http.StatusAccepted, sentenceWithLinkRes)
 	} else {
 		sentenceRes := model.SentenceResponse{
-			Id:       sentence.Id,
-			Sentence: sentence.Sentence,
-			UserId:   sentence.UserId,
+			Id:                 sentence.Id,
+			Sentence:           sentence.Sentence,
+			Translations:       toSentenceTranslationResponses(sentence.Translations),
+			Source:             toSentenceSourceResponse(sentence.Source),
+			Notes:              sentence.Notes,
+			UserId:             sentence.UserId,
+			AssociationPending: sentence.AssociationJobId != 0,
+			AssociationJobId:   sentence.AssociationJobId,
 		}
 
 		return c.JSON(http.StatusAccepted, sentenceRes)
@@ -230,30 +249,27 @@ func (sc *SentenceController) UpdateSentence(c echo.Context) error {
 }
 
 func (sc *SentenceController) DeleteSentence(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	sentenceId, err := strconv.ParseUint(c.Param("sentenceId"), 10, 32)
+	sentenceId, err := parseIdParam(c, "sentenceId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentence, err := sc.su.DeleteSentence(loginUserId, sentenceId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if (sentence == model.Sentence{}) {
-		// usecaseで更新した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
+	// 訳文はSentenceとともに削除されるため返さない
 	sentenceRes := model.SentenceResponse{
 		Id:       sentence.Id,
 		Sentence: sentence.Sentence,
+		Source:   toSentenceSourceResponse(sentence.Source),
+		Notes:    sentence.Notes,
 		UserId:   sentence.UserId,
 	}
 
@@ -261,28 +277,31 @@ func (sc *SentenceController) DeleteSentence(c echo.Context) error {
 }
 
 func (sc *SentenceController) GetAssociatedWords(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	sentenceId, err := strconv.ParseUint(c.Param("sentenceId"), 10, 32)
+	sentenceId, err := parseIdParam(c, "sentenceId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	words, err := sc.su.GetAssociatedWordsBySentenceId(loginUserId, sentenceId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var wordResponses []model.WordResponse
 	for _, word := range words {
 		wordRes := model.WordResponse{
-			Id:     word.Id,
-			Word:   word.Word,
-			Memo:   word.Memo,
-			UserId: word.UserId,
+			Id:           word.Id,
+			Word:         word.Word,
+			Memo:         word.Memo,
+			Reading:      word.Reading,
+			PartOfSpeech: word.PartOfSpeech,
+			PitchAccent:  word.PitchAccent,
+			UserId:       word.UserId,
 		}
 		wordResponses = append(wordResponses, wordRes)
 	}
@@ -291,14 +310,20 @@ func (sc *SentenceController) GetAssociatedWords(c echo.Context) error {
 }
 
 func (sc *SentenceController) GetSentencesCount(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	// 一覧と同じ条件で絞り込んだ件数を返す
+	filter, err := parseSentenceListFilterParams(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	count, err := sc.su.GetSentencesCount(loginUserId)
+	count, err := sc.su.GetSentencesCount(loginUserId, filter)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentenceCountRes := model.SentencesCountResponse{
@@ -306,4 +331,213 @@ func (sc *SentenceController) GetSentencesCount(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, sentenceCountRes)
-}
\ No newline at end of file
+}
+
+func (sc *SentenceController) GetAssociationOverrides(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	overrides, err := sc.su.GetAssociationOverrides(loginUserId, sentenceId)
+	if err != nil {
+		return err
+	}
+
+	// 指定が無い場合も、nullではなく[]を返す
+	overrideResponses := []model.AssociationOverrideResponse{}
+	for _, override := range overrides {
+		overrideResponses = append(overrideResponses, toAssociationOverrideResponse(override))
+	}
+
+	return c.JSON(http.StatusOK, overrideResponses)
+}
+
+func (sc *SentenceController) SetAssociationOverride(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.AssociationOverrideRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	overrideUpsert := model.AssociationOverrideUpsert{
+		SentenceId:  sentenceId,
+		WordId:      wordId,
+		Kind:        req.Kind,
+		LoginUserId: loginUserId,
+	}
+
+	override, err := sc.su.SetAssociationOverride(overrideUpsert)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toAssociationOverrideResponse(override))
+}
+
+func (sc *SentenceController) DeleteAssociationOverride(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	override, err := sc.su.DeleteAssociationOverride(loginUserId, sentenceId, wordId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toAssociationOverrideResponse(override))
+}
+
+func toAssociationOverrideResponse(override model.AssociationOverride) model.AssociationOverrideResponse {
+	return model.AssociationOverrideResponse{
+		SentenceId:         override.SentenceId,
+		WordId:             override.WordId,
+		Kind:               override.Kind,
+		AssociationPending: override.AssociationJobId != 0,
+		AssociationJobId:   override.AssociationJobId,
+	}
+}
+
+func toSentenceWithLinkResponse(sentenceWithLink model.SentenceWithLink, withHTML bool) model.SentenceWithLinkResponse {
+	sentenceWithLinkRes := model.SentenceWithLinkResponse{
+		Id:           sentenceWithLink.Id,
+		Sentence:     sentenceWithLink.Sentence,
+		Annotations:  toLinkAnnotationResponses(sentenceWithLink.Annotations),
+		Translations: toSentenceTranslationResponses(sentenceWithLink.Translations),
+		Source:       toSentenceSourceResponse(sentenceWithLink.Source),
+		Notes:        sentenceWithLink.Notes,
+		UserId:       sentenceWithLink.UserId,
+	}
+
+	for _, discarded := range sentenceWithLink.DiscardedAnnotations {
+		discardedRes := model.DiscardedLinkAnnotationResponse{
+			Start:       discarded.Start,
+			End:         discarded.End,
+			WordId:      discarded.WordId,
+			MatchedText: discarded.MatchedText,
+			Reason:      discarded.Reason,
+		}
+		sentenceWithLinkRes.DiscardedAnnotations = append(sentenceWithLinkRes.DiscardedAnnotations, discardedRes)
+	}
+
+	if withHTML {
+		sentenceWithLinkRes.SentenceWithLink = usecase.RenderSentenceWithLink(
+			sentenceWithLink.Sentence,
+			sentenceWithLink.Annotations,
+		)
+	}
+
+	return sentenceWithLinkRes
+}
+
+func toLinkAnnotationResponses(annotations []model.LinkAnnotation) []model.LinkAnnotationResponse {
+	// リンクが無い場合も、nullではなく[]を返す
+	linkAnnotationResponses := []model.LinkAnnotationResponse{}
+	for _, annotation := range annotations {
+		linkAnnotationRes := model.LinkAnnotationResponse{
+			Start:       annotation.Start,
+			End:         annotation.End,
+			WordId:      annotation.WordId,
+			NotationId:  annotation.NotationId,
+			MatchedText: annotation.MatchedText,
+		}
+		linkAnnotationResponses = append(linkAnnotationResponses, linkAnnotationRes)
+	}
+
+	return linkAnnotationResponses
+}
+
+func parseSentenceListFilterParams(c echo.Context) (model.ListFilter, error) {
+	// タグ、デッキに加え、クエリパラメータ ?source=... で出典の名前またはURLが一致するものに絞り込む
+	filter, err := parseListFilterParams(c)
+	if err != nil {
+		return model.ListFilter{}, err
+	}
+
+	filter.Source = c.QueryParam("source")
+
+	return filter, nil
+}
+
+func toSentenceTranslations(translationRequests []model.SentenceTranslationRequest) []model.SentenceTranslation {
+	var translations []model.SentenceTranslation
+	for _, translationReq := range translationRequests {
+		translations = append(translations, model.SentenceTranslation{
+			Language:    translationReq.Language,
+			Translation: translationReq.Translation,
+		})
+	}
+
+	return translations
+}
+
+func toSentenceSource(sourceReq *model.SentenceSourceRequest) model.SentenceSource {
+	// 省略された場合は、出典無しとする
+	if sourceReq == nil {
+		return model.SentenceSource{}
+	}
+
+	return model.SentenceSource{
+		Title:            sourceReq.Title,
+		Url:              sourceReq.Url,
+		Episode:          sourceReq.Episode,
+		TimestampSeconds: sourceReq.TimestampSeconds,
+	}
+}
+
+func toSentenceTranslationResponses(translations []model.SentenceTranslation) []model.SentenceTranslationResponse {
+	// 訳文が無い場合はnilを返し、レスポンスでは省略する
+	var translationResponses []model.SentenceTranslationResponse
+	for _, translation := range translations {
+		translationResponses = append(translationResponses, model.SentenceTranslationResponse{
+			Language:    translation.Language,
+			Translation: translation.Translation,
+		})
+	}
+
+	return translationResponses
+}
+
+func toSentenceSourceResponse(source model.SentenceSource) *model.SentenceSourceResponse {
+	// 出典が無い場合はnilを返し、レスポンスでは省略する
+	if source.IsEmpty() {
+		return nil
+	}
+
+	return &model.SentenceSourceResponse{
+		Title:            source.Title,
+		Url:              source.Url,
+		Episode:          source.Episode,
+		TimestampSeconds: source.TimestampSeconds,
+	}
+}
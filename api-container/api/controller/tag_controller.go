@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ITagController interface {
+	GetAllTags(c echo.Context) error
+	CreateTag(c echo.Context) error
+	UpdateTag(c echo.Context) error
+	DeleteTag(c echo.Context) error
+	GetWordTags(c echo.Context) error
+	AddTagToWord(c echo.Context) error
+	RemoveTagFromWord(c echo.Context) error
+	GetSentenceTags(c echo.Context) error
+	AddTagToSentence(c echo.Context) error
+	RemoveTagFromSentence(c echo.Context) error
+}
+
+type TagController struct {
+	tu *usecase.TagUsecase
+}
+
+func NewTagController(tu *usecase.TagUsecase) ITagController {
+	return &TagController{tu}
+}
+
+func (tc *TagController) GetAllTags(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	tags, err := tc.tu.GetAllTags(loginUserId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toTagResponses(tags))
+}
+
+func (tc *TagController) CreateTag(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.TagRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	tagCreation := model.TagCreation{
+		Name:        req.Name,
+		LoginUserId: loginUserId,
+	}
+
+	tag, err := tc.tu.CreateTag(tagCreation)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toTagResponse(tag))
+}
+
+func (tc *TagController) UpdateTag(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.TagRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	tagId, err := parseIdParam(c, "tagId")
+	if err != nil {
+		return err
+	}
+
+	tagUpdate := model.TagUpdate{
+		Id:          tagId,
+		Name:        req.Name,
+		LoginUserId: loginUserId,
+	}
+
+	tag, err := tc.tu.UpdateTag(tagUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toTagResponse(tag))
+}
+
+func (tc *TagController) DeleteTag(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	tagId, err := parseIdParam(c, "tagId")
+	if err != nil {
+		return err
+	}
+
+	tag, err := tc.tu.DeleteTag(loginUserId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toTagResponse(tag))
+}
+
+func (tc *TagController) GetWordTags(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	tags, err := tc.tu.GetTagsByWordId(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toTagResponses(tags))
+}
+
+func (tc *TagController) AddTagToWord(c echo.Context) error {
+	return tc.changeWordTag(c, tc.tu.AddTagToWord)
+}
+
+func (tc *TagController) RemoveTagFromWord(c echo.Context) error {
+	return tc.changeWordTag(c, tc.tu.RemoveTagFromWord)
+}
+
+func (tc *TagController) GetSentenceTags(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	tags, err := tc.tu.GetTagsBySentenceId(loginUserId, sentenceId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toTagResponses(tags))
+}
+
+func (tc *TagController) AddTagToSentence(c echo.Context) error {
+	return tc.changeSentenceTag(c, tc.tu.AddTagToSentence)
+}
+
+func (tc *TagController) RemoveTagFromSentence(c echo.Context) error {
+	return tc.changeSentenceTag(c, tc.tu.RemoveTagFromSentence)
+}
+
+func (tc *TagController) changeWordTag(c echo.Context, change func(loginUserId, wordId, tagId uint64) error) error {
+	// Wordへのタグの付け外しは何度行っても同じ結果となるため、本文の無い204を返す
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	tagId, err := parseIdParam(c, "tagId")
+	if err != nil {
+		return err
+	}
+
+	err = change(loginUserId, wordId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (tc *TagController) changeSentenceTag(c echo.Context, change func(loginUserId, sentenceId, tagId uint64) error) error {
+	// Sentenceへのタグの付け外しは何度行っても同じ結果となるため、本文の無い204を返す
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	tagId, err := parseIdParam(c, "tagId")
+	if err != nil {
+		return err
+	}
+
+	err = change(loginUserId, sentenceId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toTagResponse(tag model.Tag) model.TagResponse {
+	return model.TagResponse{
+		Id:     tag.Id,
+		Name:   tag.Name,
+		UserId: tag.UserId,
+	}
+}
+
+func toTagResponses(tags []model.Tag) []model.TagResponse {
+	// タグが1件も無い場合も、nullではなく[]を返す
+	tagResponses := []model.TagResponse{}
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, toTagResponse(tag))
+	}
+
+	return tagResponses
+}
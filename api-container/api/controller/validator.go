@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// リクエストのモデルのvalidateタグを検証するecho.Validator
+// 独自のタグとして、以下を使用できる
+//   - trimmed: 前後に空白が無い
+//   - nocontrol: 制御文字を含まない（nocontrol=multilineの場合、改行とタブは許可する）
+//   - kana: ひらがな、カタカナ、長音符のみからなる
+//   - maxmorae: 同じ構造体の、パラメータで指定した項目の読みのモーラ数以下である（maxmorae=Reading など）
+type RequestValidator struct {
+	v *validator.Validate
+}
+
+func NewRequestValidator() echo.Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// エラーの項目名には、JSONのキーを使用する
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	v.RegisterValidation("trimmed", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		return value == strings.TrimSpace(value)
+	})
+
+	v.RegisterValidation("nocontrol", func(fl validator.FieldLevel) bool {
+		multiline := fl.Param() == "multiline"
+		for _, r := range fl.Field().String() {
+			if multiline && (r == '\n' || r == '\r' || r == '\t') {
+				continue
+			}
+			if unicode.IsControl(r) {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	v.RegisterValidation("kana", func(fl validator.FieldLevel) bool {
+		return usecase.IsKana(fl.Field().String())
+	})
+
+	v.RegisterValidation("maxmorae", func(fl validator.FieldLevel) bool {
+		reading := reflect.Indirect(fl.Parent()).FieldByName(fl.Param())
+		if !reading.IsValid() || reading.Kind() != reflect.String {
+			return false
+		}
+
+		return fl.Field().Int() <= int64(usecase.CountMorae(reading.String()))
+	})
+
+	return &RequestValidator{v}
+}
+
+func (rv *RequestValidator) Validate(i interface{}) error {
+	err := rv.v.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	var fieldErrors []model.FieldValidationError
+	for _, fieldErr := range validationErrs {
+		fieldErrors = append(fieldErrors, model.FieldValidationError{
+			Field:  toFieldPath(fieldErr.Namespace()),
+			Reason: toReason(fieldErr),
+		})
+	}
+
+	return usecase.NewValidationError(fmt.Sprintf("%d field(s) are invalid", len(fieldErrors)), fieldErrors)
+}
+
+func toFieldPath(namespace string) string {
+	// "MultipleWordsCreationRequest.words[1].word" -> "words[1].word"
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+
+	return path
+}
+
+func toReason(fieldErr validator.FieldError) string {
+	isString := fieldErr.Kind() == reflect.String
+
+	switch fieldErr.Tag() {
+	case "required", "required_if", "required_with":
+		return "required"
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(fieldErr.Param()), ", "))
+	case "email":
+		return "must be a valid email address"
+	case "bcp47_language_tag":
+		return "must be a BCP 47 language tag"
+	case "http_url":
+		return "must be a valid URL"
+	case "unique":
+		return "must not contain duplicates"
+	case "trimmed":
+		return "must not have leading or trailing whitespace"
+	case "nocontrol":
+		return "must not contain control characters"
+	case "kana":
+		return "must contain only kana"
+	case "maxmorae":
+		return fmt.Sprintf("must be at most the number of morae in %s", strings.ToLower(fieldErr.Param()))
+	default:
+		return fmt.Sprintf("failed on %s", fieldErr.Tag())
+	}
+}
+
+func bindRequest(c echo.Context, req interface{}) error {
+	// リクエストボディをreqに読み込み、validateタグで検証する
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+
+	return c.Validate(req)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"api/model"
 	"api/usecase"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -16,6 +17,7 @@ type IWordController interface {
 	CreateMultipleWords(c echo.Context) error
 	DeleteWord(c echo.Context) error
 	UpdateWord(c echo.Context) error
+	MergeWords(c echo.Context) error
 	GetAssociatedSentencesWithLink(c echo.Context) error
 }
 
@@ -32,129 +34,204 @@ func NewWordController(
 }
 
 func (wc *WordController) GetAllWords(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	words, err := wc.wu.GetAllWords(loginUserId)
+	// limitが指定されない場合は既定の件数を取得する
+	// 数値でないlimitは422とする
+	var limit uint64
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		limit, err = strconv.ParseUint(limitParam, 10, 64)
+		if err != nil {
+			return usecase.NewValidationError("invalid limit", nil)
+		}
+	}
+
+	filter, err := parseListFilterParams(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	var wordResponses []model.WordResponse
-	for _, word := range words {
+	wordListQuery := model.WordListQuery{
+		LoginUserId: loginUserId,
+		Limit:       limit,
+		Sort:        c.QueryParam("sort"),
+		Order:       c.QueryParam("order"),
+		Cursor:      c.QueryParam("cursor"),
+		Filter:      filter,
+	}
+
+	wordPage, err := wc.wu.GetWordsPage(wordListQuery)
+	if err != nil {
+		return err
+	}
+
+	// Wordが1件も無い場合も、nullではなく[]を返す
+	wordResponses := []model.WordResponse{}
+	for _, word := range wordPage.Words {
 		wordRes := model.WordResponse{
-			Id:     word.Id,
-			Word:   word.Word,
-			Memo:   word.Memo,
-			UserId: word.UserId,
+			Id:           word.Id,
+			Word:         word.Word,
+			Memo:         word.Memo,
+			Reading:      word.Reading,
+			PartOfSpeech: word.PartOfSpeech,
+			PitchAccent:  word.PitchAccent,
+			UserId:       word.UserId,
+			Senses:       toWordSenseResponses(word.Senses),
 		}
 		wordResponses = append(wordResponses, wordRes)
 	}
 
-	return c.JSON(http.StatusOK, wordResponses)
+	// 次のページが無い場合、next_cursorはnullとする
+	var nextCursor *string
+	if wordPage.NextCursor != "" {
+		nextCursor = &wordPage.NextCursor
+	}
+
+	wordPageRes := model.WordPageResponse{
+		Words:      wordResponses,
+		NextCursor: nextCursor,
+		TotalCount: wordPage.TotalCount,
+	}
+
+	return c.JSON(http.StatusOK, wordPageRes)
 }
 
 func (wc *WordController) GetWordById(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	word, err := wc.wu.GetWordById(loginUserId, wordId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if (word == model.Word{}) {
-		// usecaseで取得した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusOK, make(map[string]interface{}))
+		return err
 	}
 
 	wordRes := model.WordResponse{
-		Id:     word.Id,
-		Word:   word.Word,
-		Memo:   word.Memo,
-		UserId: word.UserId,
+		Id:                 word.Id,
+		Word:               word.Word,
+		Memo:               word.Memo,
+		Reading:            word.Reading,
+		PartOfSpeech:       word.PartOfSpeech,
+		PitchAccent:        word.PitchAccent,
+		UserId:             word.UserId,
+		AssociationPending: word.AssociationJobId != 0,
+		AssociationJobId:   word.AssociationJobId,
+		Senses:             toWordSenseResponses(word.Senses),
 	}
 	return c.JSON(http.StatusOK, wordRes)
 }
 
 func (wc *WordController) CreateWord(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.WordCreationRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	onDuplicate, err := parseOnDuplicateParam(c)
+	if err != nil {
+		return err
 	}
 
 	WordCreation := model.WordCreation{
-		Word:   req.Word,
-		Memo:   req.Memo,
-		LoginUserId: loginUserId,
+		Word:         req.Word,
+		Memo:         req.Memo,
+		Reading:      req.Reading,
+		PartOfSpeech: req.PartOfSpeech,
+		PitchAccent:  req.PitchAccent,
+		LoginUserId:  loginUserId,
+		OnDuplicate:  onDuplicate,
 	}
 
 	word, err := wc.wu.CreateWord(WordCreation)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	wordRes := model.WordResponse{
-		Id:     word.Id,
-		Word:   word.Word,
-		Memo:   word.Memo,
-		UserId: word.UserId,
+		Id:                 word.Id,
+		Word:               word.Word,
+		Memo:               word.Memo,
+		Reading:            word.Reading,
+		PartOfSpeech:       word.PartOfSpeech,
+		PitchAccent:        word.PitchAccent,
+		UserId:             word.UserId,
+		AssociationPending: word.AssociationJobId != 0,
+		AssociationJobId:   word.AssociationJobId,
+		AlreadyExisted:     word.AlreadyExisted,
+	}
+
+	// 既存のWordを返した場合は、新規作成ではないため200を返す
+	if word.AlreadyExisted {
+		return c.JSON(http.StatusOK, wordRes)
 	}
 
 	return c.JSON(http.StatusCreated, wordRes)
 }
 
 func (wc *WordController) CreateMultipleWords(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
-	// TODO: words[]の中に不適切な形式のデータが入っていた場合、
-	// すべてを登録失敗とするのではなく、不適切なデータのみを弾く実装にする
-	
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
+	// words[]の中に不適切な形式のデータが1件でも入っていた場合、すべてを登録失敗とする
 	var req model.MultipleWordsCreationRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	onDuplicate, err := parseOnDuplicateParam(c)
+	if err != nil {
+		return err
 	}
 
 	var wordCreations []model.WordCreation
 	for _, wordCreationReq := range req.Words {
 		wordCreation := model.WordCreation{
-			Word: wordCreationReq.Word,
-			Memo: wordCreationReq.Memo,
-			LoginUserId:   loginUserId,
+			Word:         wordCreationReq.Word,
+			Memo:         wordCreationReq.Memo,
+			Reading:      wordCreationReq.Reading,
+			PartOfSpeech: wordCreationReq.PartOfSpeech,
+			PitchAccent:  wordCreationReq.PitchAccent,
+			LoginUserId:  loginUserId,
+			OnDuplicate:  onDuplicate,
 		}
 		wordCreations = append(wordCreations, wordCreation)
 	} 
 
 	words, err := wc.wu.CreateMultipleWords(wordCreations)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		// 不正な項目があった場合、どれも作成されず項目ごとのエラーがdetailsとして返る
+		return err
 	}
 
 	var wordResponses []model.WordResponse
 	for _, word := range words {
 		wordRes := model.WordResponse{
-			Id:       word.Id,
-			Word: word.Word,
-			Memo: word.Memo,
-			UserId: word.UserId,
+			Id:                 word.Id,
+			Word:               word.Word,
+			Memo:               word.Memo,
+			Reading:            word.Reading,
+			PartOfSpeech:       word.PartOfSpeech,
+			PitchAccent:        word.PitchAccent,
+			UserId:             word.UserId,
+			AssociationPending: word.AssociationJobId != 0,
+			AssociationJobId:   word.AssociationJobId,
+			AlreadyExisted:     word.AlreadyExisted,
 		}
 		wordResponses = append(wordResponses, wordRes)
 	}
@@ -163,107 +240,163 @@ func (wc *WordController) CreateMultipleWords(c echo.Context) error {
 }
 
 func (wc *WordController) DeleteWord(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	word, err := wc.wu.DeleteWord(loginUserId, wordId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	if (word == model.Word{}) {
-		// usecaseでWordが削除されなかった場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+		return err
 	}
 
 	wordRes := model.WordResponse{
-		Id:     word.Id,
-		Word:   word.Word,
-		Memo:   word.Memo,
-		UserId: word.UserId,
+		Id:           word.Id,
+		Word:         word.Word,
+		Memo:         word.Memo,
+		Reading:      word.Reading,
+		PartOfSpeech: word.PartOfSpeech,
+		PitchAccent:  word.PitchAccent,
+		UserId:       word.UserId,
 	}
 	return c.JSON(http.StatusAccepted, wordRes)
 }
 
 func (wc *WordController) UpdateWord(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	var req model.WordUpdateRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	wordUpdate := model.WordUpdate{
-		Id:     wordId,
-		Word:   req.Word,
-		Memo:   req.Memo,
-		LoginUserId: loginUserId,
+		Id:           wordId,
+		Word:         req.Word,
+		Memo:         req.Memo,
+		Reading:      req.Reading,
+		PartOfSpeech: req.PartOfSpeech,
+		PitchAccent:  req.PitchAccent,
+		LoginUserId:  loginUserId,
 	}
 
 	word, err := wc.wu.UpdateWord(wordUpdate)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	wordRes := model.WordResponse{
+		Id:                 word.Id,
+		Word:               word.Word,
+		Memo:               word.Memo,
+		Reading:            word.Reading,
+		PartOfSpeech:       word.PartOfSpeech,
+		PitchAccent:        word.PitchAccent,
+		UserId:             word.UserId,
+		AssociationPending: word.AssociationJobId != 0,
+		AssociationJobId:   word.AssociationJobId,
+	}
+
+	return c.JSON(http.StatusAccepted, wordRes)
+}
+
+func (wc *WordController) MergeWords(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.WordMergeRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	wordMerge := model.WordMerge{
+		Id:           wordId,
+		FromWordId:   req.FromWordId,
+		MemoStrategy: req.MemoStrategy,
+		LoginUserId:  loginUserId,
 	}
 
-	if (word == model.Word{}) {
-		// usecaseで更新した結果がゼロ値の場合
-		// {}を返す
-		return c.JSON(http.StatusUnauthorized, make(map[string]interface{}))
+	word, err := wc.wu.MergeWords(wordMerge)
+	if err != nil {
+		return err
 	}
 
 	wordRes := model.WordResponse{
-		Id:     word.Id,
-		Word:   word.Word,
-		Memo:   word.Memo,
-		UserId: word.UserId,
+		Id:                 word.Id,
+		Word:               word.Word,
+		Memo:               word.Memo,
+		Reading:            word.Reading,
+		PartOfSpeech:       word.PartOfSpeech,
+		PitchAccent:        word.PitchAccent,
+		UserId:             word.UserId,
+		AssociationPending: word.AssociationJobId != 0,
+		AssociationJobId:   word.AssociationJobId,
 	}
 
-	return c.JSON(http.StatusAccepted, wordRes)
+	return c.JSON(http.StatusOK, wordRes)
 }
 
 func (wc *WordController) GetAssociatedSentencesWithLink(c echo.Context) error {
-	loginUserId, err := GetLoginUserId()
+	loginUserId, err := GetLoginUserId(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	wordId, err := strconv.ParseUint(c.Param("wordId"), 10, 32)
+	wordId, err := parseIdParam(c, "wordId")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	sentenceWithLinks, err := wc.au.GetAssociatedSentencesWithLinkByWordId(loginUserId, wordId)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
+	// クエリパラメータ ?with-html=true の場合、
+	// リンクをaタグにしたHTMLもレスポンスに含める
+	withHTML := c.QueryParam("with-html") == "true"
+
 	var sentenceWithLinkResponses []model.SentenceWithLinkResponse
 	for _, sentenceWithLink := range sentenceWithLinks {
-		sentenceWithLinkRes := model.SentenceWithLinkResponse{
-			Id:               sentenceWithLink.Id,
-			Sentence:         sentenceWithLink.Sentence,
-			SentenceWithLink: sentenceWithLink.SentenceWithLink,
-			UserId:           sentenceWithLink.UserId,
-		}
+		sentenceWithLinkRes := toSentenceWithLinkResponse(sentenceWithLink, withHTML)
 
 		sentenceWithLinkResponses = append(sentenceWithLinkResponses, sentenceWithLinkRes)
 	}
 
 	return c.JSON(http.StatusOK, sentenceWithLinkResponses)
 }
+
+func parseOnDuplicateParam(c echo.Context) (string, error) {
+	// クエリパラメータ ?on_duplicate=skip などで、追加しようとしたWordが既に存在する場合の扱いを指定する
+	// 指定されない場合は409を返す
+	onDuplicate := c.QueryParam("on_duplicate")
+	if onDuplicate == "" {
+		return model.OnDuplicateError, nil
+	}
+
+	if !model.IsValidOnDuplicate(onDuplicate) {
+		return "", usecase.NewValidationError(fmt.Sprintf("invalid on_duplicate: %s", onDuplicate), nil)
+	}
+
+	return onDuplicate, nil
+}
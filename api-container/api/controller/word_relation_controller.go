@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IWordRelationController interface {
+	GetRelations(c echo.Context) error
+	CreateRelation(c echo.Context) error
+	UpdateRelation(c echo.Context) error
+	DeleteRelation(c echo.Context) error
+	GetCompoundSuggestions(c echo.Context) error
+}
+
+type WordRelationController struct {
+	wru *usecase.WordRelationUsecase
+}
+
+func NewWordRelationController(wru *usecase.WordRelationUsecase) IWordRelationController {
+	return &WordRelationController{wru}
+}
+
+func (wrc *WordRelationController) GetRelations(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	relations, err := wrc.wru.GetRelations(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	// 関係が1件も無い場合も、nullではなく[]を返す
+	relationResponses := []model.WordRelationResponse{}
+	for _, relation := range relations {
+		relationResponses = append(relationResponses, toWordRelationResponse(relation))
+	}
+
+	return c.JSON(http.StatusOK, relationResponses)
+}
+
+func (wrc *WordRelationController) CreateRelation(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.WordRelationRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	relationCreation := model.WordRelationCreation{
+		WordId:        wordId,
+		RelatedWordId: req.RelatedWordId,
+		Kind:          req.Kind,
+		Bidirectional: req.Bidirectional,
+		LoginUserId:   loginUserId,
+	}
+
+	relation, err := wrc.wru.CreateRelation(relationCreation)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toWordRelationResponse(relation))
+}
+
+func (wrc *WordRelationController) UpdateRelation(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.WordRelationUpdateRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	relationId, err := parseIdParam(c, "relationId")
+	if err != nil {
+		return err
+	}
+
+	relationUpdate := model.WordRelationUpdate{
+		Id:            relationId,
+		WordId:        wordId,
+		Kind:          req.Kind,
+		Bidirectional: req.Bidirectional,
+		LoginUserId:   loginUserId,
+	}
+
+	relation, err := wrc.wru.UpdateRelation(relationUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toWordRelationResponse(relation))
+}
+
+func (wrc *WordRelationController) DeleteRelation(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	relationId, err := parseIdParam(c, "relationId")
+	if err != nil {
+		return err
+	}
+
+	relation, err := wrc.wru.DeleteRelation(loginUserId, wordId, relationId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toWordRelationResponse(relation))
+}
+
+func (wrc *WordRelationController) GetCompoundSuggestions(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := wrc.wru.SuggestCompounds(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	// 提案が1件も無い場合も、nullではなく[]を返す
+	suggestionResponses := []model.WordRelationSuggestionResponse{}
+	for _, suggestion := range suggestions {
+		suggestionResponses = append(suggestionResponses, model.WordRelationSuggestionResponse{
+			WordId:        suggestion.WordId,
+			RelatedWordId: suggestion.RelatedWordId,
+			Kind:          suggestion.Kind,
+			MatchedText:   suggestion.MatchedText,
+			NotationId:    suggestion.NotationId,
+		})
+	}
+
+	return c.JSON(http.StatusOK, suggestionResponses)
+}
+
+func toWordRelationResponse(relation model.WordRelation) model.WordRelationResponse {
+	return model.WordRelationResponse{
+		Id:            relation.Id,
+		WordId:        relation.WordId,
+		RelatedWordId: relation.RelatedWordId,
+		Kind:          relation.Kind,
+		Bidirectional: relation.Bidirectional,
+	}
+}
@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"api/model"
+	"api/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IWordSenseController interface {
+	GetSenses(c echo.Context) error
+	CreateSense(c echo.Context) error
+	UpdateSense(c echo.Context) error
+	DeleteSense(c echo.Context) error
+	GetSentenceSenses(c echo.Context) error
+	SetSentenceSense(c echo.Context) error
+	DeleteSentenceSense(c echo.Context) error
+}
+
+type WordSenseController struct {
+	wsu *usecase.WordSenseUsecase
+}
+
+func NewWordSenseController(wsu *usecase.WordSenseUsecase) IWordSenseController {
+	return &WordSenseController{wsu}
+}
+
+func (wsc *WordSenseController) GetSenses(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	senses, err := wsc.wsu.GetSenses(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	// 意味が1件も無い場合も、nullではなく[]を返す
+	senseResponses := []model.WordSenseResponse{}
+	senseResponses = append(senseResponses, toWordSenseResponses(senses)...)
+
+	return c.JSON(http.StatusOK, senseResponses)
+}
+
+func (wsc *WordSenseController) CreateSense(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.WordSenseRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	senseCreation := model.WordSenseCreation{
+		WordId:       wordId,
+		Gloss:        req.Gloss,
+		Language:     req.Language,
+		PartOfSpeech: req.PartOfSpeech,
+		Register:     req.Register,
+		Position:     req.Position,
+		LoginUserId:  loginUserId,
+	}
+
+	sense, err := wsc.wsu.CreateSense(senseCreation)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toWordSenseResponse(sense))
+}
+
+func (wsc *WordSenseController) UpdateSense(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.WordSenseRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	senseId, err := parseIdParam(c, "senseId")
+	if err != nil {
+		return err
+	}
+
+	senseUpdate := model.WordSenseUpdate{
+		Id:           senseId,
+		WordId:       wordId,
+		Gloss:        req.Gloss,
+		Language:     req.Language,
+		PartOfSpeech: req.PartOfSpeech,
+		Register:     req.Register,
+		Position:     req.Position,
+		LoginUserId:  loginUserId,
+	}
+
+	sense, err := wsc.wsu.UpdateSense(senseUpdate)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toWordSenseResponse(sense))
+}
+
+func (wsc *WordSenseController) DeleteSense(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	senseId, err := parseIdParam(c, "senseId")
+	if err != nil {
+		return err
+	}
+
+	sense, err := wsc.wsu.DeleteSense(loginUserId, wordId, senseId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toWordSenseResponse(sense))
+}
+
+func (wsc *WordSenseController) GetSentenceSenses(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	sentenceSenses, err := wsc.wsu.GetSentenceSenses(loginUserId, sentenceId)
+	if err != nil {
+		return err
+	}
+
+	// 指定が1件も無い場合も、nullではなく[]を返す
+	sentenceSenseResponses := []model.SentenceWordSenseResponse{}
+	for _, sentenceSense := range sentenceSenses {
+		sentenceSenseResponses = append(sentenceSenseResponses, toSentenceWordSenseResponse(sentenceSense))
+	}
+
+	return c.JSON(http.StatusOK, sentenceSenseResponses)
+}
+
+func (wsc *WordSenseController) SetSentenceSense(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	var req model.SentenceWordSenseRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	sentenceSenseUpsert := model.SentenceWordSenseUpsert{
+		SentenceId:  sentenceId,
+		WordId:      wordId,
+		SenseId:     req.SenseId,
+		LoginUserId: loginUserId,
+	}
+
+	sentenceSense, err := wsc.wsu.SetSentenceSense(sentenceSenseUpsert)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toSentenceWordSenseResponse(sentenceSense))
+}
+
+func (wsc *WordSenseController) DeleteSentenceSense(c echo.Context) error {
+	loginUserId, err := GetLoginUserId(c)
+	if err != nil {
+		return err
+	}
+
+	sentenceId, err := parseIdParam(c, "sentenceId")
+	if err != nil {
+		return err
+	}
+
+	wordId, err := parseIdParam(c, "wordId")
+	if err != nil {
+		return err
+	}
+
+	sentenceSense, err := wsc.wsu.DeleteSentenceSense(loginUserId, sentenceId, wordId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, toSentenceWordSenseResponse(sentenceSense))
+}
+
+func toWordSenseResponse(sense model.WordSense) model.WordSenseResponse {
+	return model.WordSenseResponse{
+		Id:           sense.Id,
+		WordId:       sense.WordId,
+		Gloss:        sense.Gloss,
+		Language:     sense.Language,
+		PartOfSpeech: sense.PartOfSpeech,
+		Register:     sense.Register,
+		Position:     sense.Position,
+	}
+}
+
+func toWordSenseResponses(senses []model.WordSense) []model.WordSenseResponse {
+	// 意味が無い場合はnilを返し、WordResponseでは省略する
+	var senseResponses []model.WordSenseResponse
+	for _, sense := range senses {
+		senseResponses = append(senseResponses, toWordSenseResponse(sense))
+	}
+
+	return senseResponses
+}
+
+func toSentenceWordSenseResponse(sentenceSense model.SentenceWordSense) model.SentenceWordSenseResponse {
+	return model.SentenceWordSenseResponse{
+		SentenceId: sentenceSense.SentenceId,
+		WordId:     sentenceSense.WordId,
+		SenseId:    sentenceSense.SenseId,
+	}
+}
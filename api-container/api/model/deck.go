@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+// WordとSentenceをまとめる、階層構造を持つデッキ
+// 親のデッキは、子孫のデッキのWordとSentenceも含むものとして扱う
+type Deck struct {
+	Id     uint64
+	Name   string
+	UserId uint64
+	// 最上位のデッキの場合はnil
+	ParentId *uint64
+	// trueの場合、このデッキ（子孫を含む）のSentenceは、同じデッキのWordのみと紐づける
+	ScopeAssociation bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	// デッキの変更による、WordとSentenceの紐づけを再構築する完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
+}
+
+type DeckResponse struct {
+	Id               uint64  `json:"id"`
+	Name             string  `json:"name"`
+	UserId           uint64  `json:"user_id"`
+	ParentId         *uint64 `json:"parent_id"`
+	ScopeAssociation bool    `json:"scope_association"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
+}
+
+// validateタグの最大文字数は、DBのカラム長に合わせる
+type DeckRequest struct {
+	Name             string  `json:"name" validate:"required,trimmed,nocontrol,max=100"`
+	ParentId         *uint64 `json:"parent_id" validate:"omitempty,min=1"`
+	ScopeAssociation bool    `json:"scope_association"`
+}
+
+type DeckCreation struct {
+	Name             string
+	ParentId         *uint64
+	ScopeAssociation bool
+	LoginUserId      uint64
+}
+
+type DeckUpdate struct {
+	Id               uint64
+	Name             string
+	ParentId         *uint64
+	ScopeAssociation bool
+	LoginUserId      uint64
+}
+
+// デッキに含まれるWordまたはSentence
+// WordIdとSentenceIdのどちらか一方のみ0でない
+type DeckItem struct {
+	DeckId     uint64
+	WordId     uint64
+	SentenceId uint64
+	// デッキへの追加、削除による、WordとSentenceの紐づけを再構築する完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
+}
+
+type DeckItemResponse struct {
+	DeckId     uint64 `json:"deck_id"`
+	WordId     uint64 `json:"word_id,omitempty"`
+	SentenceId uint64 `json:"sentence_id,omitempty"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
+}
+
+// scope_associationがtrueのデッキ（子孫を含む）に含まれるWordとSentence
+type AssociationScope struct {
+	DeckId      uint64
+	WordIds     []uint64
+	SentenceIds []uint64
+}
+
+// Word、Sentenceの一覧の絞り込み
+// 0、空文字列の項目では絞り込まない
+type ListFilter struct {
+	TagId uint64
+	// 子孫のデッキに含まれるものも対象とする
+	DeckId uint64
+	// Sentenceの一覧のみ、出典の名前またはURLが一致するものに絞り込む
+	Source string
+}
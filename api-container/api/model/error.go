@@ -0,0 +1,17 @@
+package model
+
+// エラー時に全APIで共通して返すレスポンス
+type ErrorResponse struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	// ログと照合するための、リクエストごとのId
+	RequestId string `json:"request_id"`
+}
+
+// 既に存在するリソースと重複した場合に、detailsとして返す
+type DuplicateErrorDetails struct {
+	ExistingId uint64 `json:"existing_id"`
+	// 複数件の追加で重複した項目
+	Field string `json:"field,omitempty"`
+}
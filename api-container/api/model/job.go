@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// ジョブの種類
+const (
+	// TargetIdsのWordとSentenceの紐づけを再構築する
+	JobKindReassociateWords = "reassociate_words"
+	// TargetIdsのSentenceとWordの紐づけを再構築する
+	JobKindReassociateSentences = "reassociate_sentences"
+)
+
+// ジョブの状態
+const (
+	JobStatusPending   = "pending"   // 実行待ち（失敗後の再試行待ちを含む）
+	JobStatusRunning   = "running"   // ワーカーが実行中
+	JobStatusSucceeded = "succeeded" // 成功
+	JobStatusDead      = "dead"      // 最大試行回数に達しても成功しなかった
+)
+
+type Job struct {
+	Id          uint64
+	UserId      uint64
+	Kind        string
+	TargetIds   []uint64
+	Status      string
+	Attempts    uint64
+	MaxAttempts uint64
+	// 最後に失敗した際のメッセージ
+	// クライアントに返すため、DBのエラーなどの内容は含めない
+	LastError string
+	RunAt     time.Time
+	// 成功、またはdeadになった日時
+	// 完了していない場合はnil
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type JobResponse struct {
+	Id          uint64     `json:"id"`
+	Kind        string     `json:"kind"`
+	TargetIds   []uint64   `json:"target_ids"`
+	Status      string     `json:"status"`
+	Attempts    uint64     `json:"attempts"`
+	MaxAttempts uint64     `json:"max_attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	RunAt       time.Time  `json:"run_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type JobCreation struct {
+	UserId      uint64
+	Kind        string
+	TargetIds   []uint64
+	MaxAttempts uint64
+}
@@ -2,36 +2,68 @@ package model
 
 import "time"
 
+// Notationの作成元
+const (
+	NotationKindManual      = "manual"      // ユーザーが入力したNotation
+	NotationKindStem        = "stem"        // Wordの語幹から自動生成されたNotation
+	NotationKindConjugation = "conjugation" // Wordの活用形から自動生成されたNotation
+	NotationKindImport      = "import"      // 外部から取り込まれたNotation
+)
+
+func IsValidNotationKind(kind string) bool {
+	switch kind {
+	case NotationKindManual, NotationKindStem, NotationKindConjugation, NotationKindImport:
+		return true
+	}
+	return false
+}
+
+func IsGeneratedNotationKind(kind string) bool {
+	// Wordから自動生成されたNotationであるかを判定
+	return kind == NotationKindStem || kind == NotationKindConjugation
+}
+
 type Notation struct {
 	Id        uint64
 	WordId    uint64
 	Notation  string
+	Kind      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// Notationの変更による、WordとSentenceの紐づけを再構築する完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
 }
 
 type NotationResponse struct {
 	Id       uint64 `json:"id"`
 	WordId   uint64 `json:"word_id"`
 	Notation string `json:"notation"`
+	Kind     string `json:"kind"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
 }
 
+// 空文字列のNotationは全てのSentenceに一致してしまうため、必須とする
 type NotationCreationRequest struct {
-	Notation string `json:"notation"`
+	Notation string `json:"notation" validate:"required,trimmed,nocontrol,max=100"`
 }
 
 type NotationCreation struct {
 	WordId       uint64
 	Notation     string
+	Kind         string
 	LoginUserId  uint64
 }
 
 type NotationUpdateRequest struct {
-	Notation string `json:"notation"`
+	Notation string `json:"notation" validate:"required,trimmed,nocontrol,max=100"`
 }
 
 type NotationUpdate struct {
 	Id          uint64
 	Notation    string
+	Kind        string
 	LoginUserId uint64
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type RefreshToken struct {
+	Id         uint64
+	UserId     uint64
+	TokenHash  string
+	FamilyId   string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *uint64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type RefreshTokenCreation struct {
+	UserId    uint64
+	TokenHash string
+	FamilyId  string
+	ExpiresAt time.Time
+}
+
+type TokenRequest struct {
+	// "password" または "refresh_token"
+	GrantType    string `json:"grant_type" validate:"required,oneof=password refresh_token"`
+	Email        string `json:"email" validate:"required_if=GrantType password"`
+	Password     string `json:"password" validate:"required_if=GrantType password"`
+	RefreshToken string `json:"refresh_token" validate:"required_if=GrantType refresh_token"`
+}
+
+type TokenRevocationRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type IssuedTokens struct {
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+	UserId               uint64
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    uint64 `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+type Review struct {
+	Id             uint64
+	WordId         uint64
+	EaseFactor     float64
+	IntervalDays   uint64
+	Repetitions    uint64
+	DueAt          time.Time
+	LastGrade      uint64
+	LastReviewedAt time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// 復習のスケジュールを決めるための状態
+// IScheduler間で共通して使用する
+type ReviewSchedule struct {
+	EaseFactor   float64
+	IntervalDays uint64
+	Repetitions  uint64
+	DueAt        time.Time
+}
+
+type ReviewResponse struct {
+	WordId         uint64    `json:"word_id"`
+	EaseFactor     float64   `json:"ease_factor"`
+	IntervalDays   uint64    `json:"interval_days"`
+	Repetitions    uint64    `json:"repetitions"`
+	DueAt          time.Time `json:"due_at"`
+	LastGrade      uint64    `json:"last_grade"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+}
+
+type ReviewRequest struct {
+	// 0~5の評価
+	// 未指定と0を区別するためポインタにする
+	Grade *uint64 `json:"grade" validate:"required,max=5"`
+}
+
+type ReviewCreation struct {
+	WordId      uint64
+	Grade       uint64
+	LoginUserId uint64
+}
+
+type ReviewUpsert struct {
+	WordId     uint64
+	Schedule   ReviewSchedule
+	Grade      uint64
+	ReviewedAt time.Time
+}
+
+// 復習期限を迎えたWord
+// 1度も復習していないWordの場合、Reviewはnil
+type DueReview struct {
+	Word      Word
+	Review    *Review
+	Sentences []ReviewSentence
+}
+
+type DueReviewResponse struct {
+	Word      WordResponse             `json:"word"`
+	Review    *ReviewResponse          `json:"review"`
+	Sentences []ReviewSentenceResponse `json:"sentences"`
+}
+
+// 復習するWordの例文
+type ReviewSentence struct {
+	Sentence Sentence
+	// Wordのリンクとなる箇所を空欄にしたSentence
+	Cloze string
+}
+
+type ReviewSentenceResponse struct {
+	Id       uint64 `json:"id"`
+	Sentence string `json:"sentence"`
+	Cloze    string `json:"cloze"`
+	UserId   uint64 `json:"user_id"`
+}
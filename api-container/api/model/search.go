@@ -0,0 +1,57 @@
+package model
+
+type SearchQuery struct {
+	LoginUserId uint64
+	Query       string
+	Limit       uint64
+	Offset      uint64
+}
+
+type WordSearchHit struct {
+	Word  Word
+	Score float64
+	// Wordに紐づくNotationのうち、検索語を含むもの
+	MatchedNotations []Notation
+}
+
+type SentenceSearchHit struct {
+	Sentence Sentence
+	Score    float64
+	// toSentencesWithLinkと同じ方法で求めた、Sentence中のWordへのリンクとなる箇所
+	Annotations []LinkAnnotation
+}
+
+type SearchResult struct {
+	Words               []WordSearchHit
+	WordsTotalCount     uint64
+	Sentences           []SentenceSearchHit
+	SentencesTotalCount uint64
+}
+
+type WordSearchHitResponse struct {
+	Id               uint64   `json:"id"`
+	Word             string   `json:"word"`
+	Memo             string   `json:"memo"`
+	UserId           uint64   `json:"user_id"`
+	Score            float64  `json:"score"`
+	HighlightedWord  string   `json:"highlighted_word"`
+	HighlightedMemo  string   `json:"highlighted_memo"`
+	MatchedNotations []string `json:"matched_notations"`
+}
+
+type SentenceSearchHitResponse struct {
+	Id                  uint64                   `json:"id"`
+	Sentence            string                   `json:"sentence"`
+	SentenceWithLink    string                   `json:"sentence_with_link,omitempty"`
+	Annotations         []LinkAnnotationResponse `json:"annotations"`
+	HighlightedSentence string                   `json:"highlighted_sentence"`
+	UserId              uint64                   `json:"user_id"`
+	Score               float64                  `json:"score"`
+}
+
+type SearchResponse struct {
+	Words               []WordSearchHitResponse     `json:"words"`
+	WordsTotalCount     uint64                      `json:"words_total_count"`
+	Sentences           []SentenceSearchHitResponse `json:"sentences"`
+	SentencesTotalCount uint64                      `json:"sentences_total_count"`
+}
@@ -3,57 +3,185 @@ package model
 import "time"
 
 type Sentence struct {
-	Id        uint64
-	Sentence  string
-	UserId    uint64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id       uint64
+	Sentence string
+	// 言語ごとの訳文（言語順）。取得時は必要な場合のみ設定する
+	Translations []SentenceTranslation
+	Source       SentenceSource
+	Notes        string
+	UserId       uint64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// Wordとの紐づけを再構築する、完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
 }
 
 type SentenceResponse struct {
 	Id       uint64 `json:"id"`
 	Sentence string `json:"sentence"`
-	UserId   uint64 `json:"user_id"`
+	// 入力された場合のみ返す
+	Translations []SentenceTranslationResponse `json:"translations,omitempty"`
+	Source       *SentenceSourceResponse       `json:"source,omitempty"`
+	Notes        string                        `json:"notes,omitempty"`
+	UserId       uint64                        `json:"user_id"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
 }
 
+// validateタグの最大文字数は、DBのカラム長に合わせる
 type SentenceCreationRequest struct {
-	Sentence string `json:"sentence"`
+	Sentence     string                       `json:"sentence" validate:"required,trimmed,nocontrol,max=500"`
+	Translations []SentenceTranslationRequest `json:"translations" validate:"unique=Language,dive"`
+	Source       *SentenceSourceRequest       `json:"source"`
+	Notes        string                       `json:"notes" validate:"nocontrol=multiline,max=2000"`
 }
 
 type MultipleSentencesCreationRequest struct {
-	Sentences []SentenceCreationRequest `json:"sentences"`
+	Sentences []SentenceCreationRequest `json:"sentences" validate:"dive"`
 }
 
 type SentenceCreation struct {
-	Sentence    string
-	LoginUserId uint64
+	Sentence     string
+	Translations []SentenceTranslation
+	Source       SentenceSource
+	Notes        string
+	LoginUserId  uint64
 }
 
+// 訳文、出典、メモは省略した場合も、未入力として更新する
 type SentenceUpdateRequest struct {
-	Id       uint64 `json:"id"`
-	Sentence string `json:"sentence"`
+	Id           uint64                       `json:"id"`
+	Sentence     string                       `json:"sentence" validate:"required,trimmed,nocontrol,max=500"`
+	Translations []SentenceTranslationRequest `json:"translations" validate:"unique=Language,dive"`
+	Source       *SentenceSourceRequest       `json:"source"`
+	Notes        string                       `json:"notes" validate:"nocontrol=multiline,max=2000"`
 }
 
 type SentenceUpdate struct {
-	Id          uint64
-	Sentence    string
-	LoginUserId uint64
+	Id           uint64
+	Sentence     string
+	Translations []SentenceTranslation
+	Source       SentenceSource
+	Notes        string
+	LoginUserId  uint64
+}
+
+// Sentenceの訳文
+type SentenceTranslation struct {
+	SentenceId uint64
+	// 訳文の言語（BCP 47の言語タグ）
+	Language    string
+	Translation string
+}
+
+type SentenceTranslationResponse struct {
+	Language    string `json:"language"`
+	Translation string `json:"translation"`
+}
+
+type SentenceTranslationRequest struct {
+	Language    string `json:"language" validate:"required,bcp47_language_tag,max=35"`
+	Translation string `json:"translation" validate:"required,trimmed,nocontrol=multiline,max=1000"`
+}
+
+// Sentenceの出典
+// 未入力の項目は空文字列、再生位置はnilとする
+type SentenceSource struct {
+	// 書籍、番組などの名前
+	Title string
+	Url   string
+	// 話数、章など
+	Episode string
+	// 動画、音声中の再生位置（秒）
+	TimestampSeconds *int
+}
+
+func (s SentenceSource) IsEmpty() bool {
+	return s.Title == "" && s.Url == "" && s.Episode == "" && s.TimestampSeconds == nil
+}
+
+type SentenceSourceResponse struct {
+	Title            string `json:"title,omitempty"`
+	Url              string `json:"url,omitempty"`
+	Episode          string `json:"episode,omitempty"`
+	TimestampSeconds *int   `json:"timestamp_seconds,omitempty"`
+}
+
+type SentenceSourceRequest struct {
+	Title            string `json:"title" validate:"trimmed,nocontrol,max=200"`
+	Url              string `json:"url" validate:"omitempty,http_url,max=2000"`
+	Episode          string `json:"episode" validate:"trimmed,nocontrol,max=100"`
+	TimestampSeconds *int   `json:"timestamp_seconds" validate:"omitempty,min=0"`
 }
 
 type SentenceWithLink struct {
-	Id               uint64
-	Sentence         string
-	SentenceWithLink string
-	UserId           uint64
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
+	Id           uint64
+	Sentence     string
+	Translations []SentenceTranslation
+	Source       SentenceSource
+	Notes        string
+	Annotations  []LinkAnnotation
+	// 他のWordと重なり合うため、リンクとしなかった箇所
+	DiscardedAnnotations []DiscardedLinkAnnotation
+	UserId               uint64
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 type SentenceWithLinkResponse struct {
-	Id               uint64 `json:"id"`
-	Sentence         string `json:"sentence"`
-	SentenceWithLink string `json:"sentence_with_link"`
-	UserId           uint64 `json:"user_id"`
+	Id       uint64 `json:"id"`
+	Sentence string `json:"sentence"`
+	// クエリパラメータ ?with-html=true の場合のみ、リンクをaタグにしたHTMLを返す
+	SentenceWithLink string                   `json:"sentence_with_link,omitempty"`
+	Annotations      []LinkAnnotationResponse `json:"annotations"`
+	// 重なり合うWordが無い場合は省略する
+	DiscardedAnnotations []DiscardedLinkAnnotationResponse `json:"discarded_annotations,omitempty"`
+	// 入力された場合のみ返す
+	Translations []SentenceTranslationResponse `json:"translations,omitempty"`
+	Source       *SentenceSourceResponse       `json:"source,omitempty"`
+	Notes        string                        `json:"notes,omitempty"`
+	UserId       uint64                        `json:"user_id"`
+}
+
+// Sentence中でWordへのリンクとなる箇所
+type LinkAnnotation struct {
+	// ルーン単位のオフセット
+	// Sentence中の[Start, End)の範囲が、WordIdのWordへのリンクとなる
+	Start  int
+	End    int
+	WordId uint64
+	// 一致したNotationのId
+	// Word自体に一致した場合は0
+	NotationId  uint64
+	MatchedText string
+}
+
+type LinkAnnotationResponse struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	WordId      uint64 `json:"word_id"`
+	NotationId  uint64 `json:"notation_id,omitempty"`
+	MatchedText string `json:"matched_text"`
+}
+
+// Sentence中でWordが出現したが、他のWordと重なり合うためリンクとしなかった箇所
+type DiscardedLinkAnnotation struct {
+	Start       int
+	End         int
+	WordId      uint64
+	MatchedText string
+	// "overlap"、"tie_break" のいずれか
+	Reason string
+}
+
+type DiscardedLinkAnnotationResponse struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	WordId      uint64 `json:"word_id"`
+	MatchedText string `json:"matched_text"`
+	Reason      string `json:"reason"`
 }
 
 type SentencesCountResponse struct {
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type WordIdsRequest struct {
 	WordIds []uint64 `json:"word_ids"`
 }
@@ -10,4 +12,72 @@ type WordIds struct {
 
 type WordIdsResponse struct {
 	WordIds []uint64 `json:"word_ids"`
-}
\ No newline at end of file
+}
+
+// SentenceとWordの紐づけ
+type SentenceWord struct {
+	SentenceId uint64
+	WordId     uint64
+}
+
+// WordIdのWordに紐づくSentence
+type WordAssociatedSentence struct {
+	WordId   uint64
+	Sentence Sentence
+}
+
+// Sentence中でのWordの出現箇所
+type SentenceWordOccurrence struct {
+	SentenceId uint64
+	WordId     uint64
+	// 一致したNotationのId
+	// Word自体に一致した場合は0
+	NotationId uint64
+	// Sentence中の出現箇所（ルーン単位、Endのルーンは含まない）
+	Start int
+	End   int
+	// 他のWordと重なり合うため、リンクとして選ばれなかった理由
+	// リンクとして選ばれた場合は空文字列
+	DiscardReason string
+}
+
+// ユーザーが手動で指定した紐づけの種類
+const (
+	// Sentence中に出現しなくても紐づける
+	AssociationOverrideKindPin = "pin"
+	// Sentence中に出現しても紐づけない
+	AssociationOverrideKindExclude = "exclude"
+)
+
+// ユーザーが手動で指定した、SentenceとWordの紐づけ
+// 紐づけの再構築時も削除されず、自動での紐づけより優先される
+type AssociationOverride struct {
+	SentenceId uint64
+	WordId     uint64
+	Kind       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// 指定による、WordとSentenceの紐づけを再構築する完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
+}
+
+type AssociationOverrideResponse struct {
+	SentenceId uint64 `json:"sentence_id"`
+	WordId     uint64 `json:"word_id"`
+	Kind       string `json:"kind"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
+}
+
+type AssociationOverrideRequest struct {
+	Kind string `json:"kind" validate:"required,oneof=pin exclude"`
+}
+
+type AssociationOverrideUpsert struct {
+	SentenceId  uint64
+	WordId      uint64
+	Kind        string
+	LoginUserId uint64
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type Session struct {
+	Id        string
+	UserId    uint64
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SessionCreation struct {
+	Id        string
+	UserId    uint64
+	ExpiresAt time.Time
+}
+
+type IssuedSession struct {
+	// Cookieに格納するトークン
+	// DBにはハッシュ化した値をSession.Idとして保存する
+	Token     string
+	UserId    uint64
+	ExpiresAt time.Time
+}
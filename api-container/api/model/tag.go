@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// WordとSentenceを分類するための、ユーザーが定義したタグ
+type Tag struct {
+	Id        uint64
+	Name      string
+	UserId    uint64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type TagResponse struct {
+	Id     uint64 `json:"id"`
+	Name   string `json:"name"`
+	UserId uint64 `json:"user_id"`
+}
+
+// validateタグの最大文字数は、DBのカラム長に合わせる
+type TagRequest struct {
+	Name string `json:"name" validate:"required,trimmed,nocontrol,max=50"`
+}
+
+type TagCreation struct {
+	Name        string
+	LoginUserId uint64
+}
+
+type TagUpdate struct {
+	Id          uint64
+	Name        string
+	LoginUserId uint64
+}
@@ -3,9 +3,39 @@ package model
 import "time"
 
 type User struct {
-	Id        uint
+	Id        uint64
 	Email     string
 	Password  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-}
\ No newline at end of file
+}
+
+type UserResponse struct {
+	Id    uint64 `json:"id"`
+	Email string `json:"email"`
+}
+
+type UserSignUpRequest struct {
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type UserSignUp struct {
+	Email    string
+	Password string
+}
+
+type UserCreation struct {
+	Email          string
+	HashedPassword string
+}
+
+type UserLogInRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type UserLogIn struct {
+	Email    string
+	Password string
+}
@@ -0,0 +1,9 @@
+package model
+
+// リクエストの項目ごとのエラー
+// ErrorResponseのdetailsとして返す
+type FieldValidationError struct {
+	// リクエストのJSONにおける項目の位置（words[1].word など）
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
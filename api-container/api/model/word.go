@@ -3,45 +3,181 @@ package model
 import "time"
 
 type Word struct {
-	Id        uint64
-	Word      string
-	Memo      string
-	UserId    uint64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id   uint64
+	Word string
+	Memo string
+	// 読み（仮名）。未入力の場合は空文字列
+	Reading string
+	// 品詞。未入力の場合は空文字列とし、活用形はWordの語尾から推定する
+	PartOfSpeech string
+	// アクセント核の位置（0は平板型）。未入力の場合はnil
+	PitchAccent *int
+	UserId      uint64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// 意味（表示順）。取得時のみ設定する
+	Senses []WordSense
+	// Sentenceとの紐づけを再構築する、完了していないジョブのId
+	// 紐づけの再構築が完了している場合は0
+	AssociationJobId uint64
+	// 追加しようとしたWordが既に存在し、既存のWordを返した場合にtrue
+	AlreadyExisted bool
 }
 
 type WordResponse struct {
-	Id     uint64 `json:"id"`
-	Word   string `json:"word"`
-	Memo   string `json:"memo"`
-	UserId uint64 `json:"user_id"`
+	Id   uint64 `json:"id"`
+	Word string `json:"word"`
+	Memo string `json:"memo"`
+	// 入力された場合のみ返す
+	Reading      string `json:"reading,omitempty"`
+	PartOfSpeech string `json:"part_of_speech,omitempty"`
+	PitchAccent  *int   `json:"pitch_accent,omitempty"`
+	UserId       uint64 `json:"user_id"`
+	// 意味が登録されている場合のみ返す
+	Senses []WordSenseResponse `json:"senses,omitempty"`
+	// 紐づけの再構築を非同期で行っている間のみ返す
+	AssociationPending bool   `json:"association_pending,omitempty"`
+	AssociationJobId   uint64 `json:"association_job_id,omitempty"`
+	// on_duplicateにskip、upsertを指定し、既存のWordを返した場合のみ返す
+	AlreadyExisted bool `json:"already_existed,omitempty"`
 }
 
+// validateタグの最大文字数は、DBのカラム長に合わせる
+// アクセント核の位置は、読みのモーラ数以下とする
 type WordCreationRequest struct {
-	Word   string `json:"word"`
-	Memo   string `json:"memo"`
+	Word         string `json:"word" validate:"required,trimmed,nocontrol,max=100"`
+	Memo         string `json:"memo" validate:"nocontrol=multiline,max=500"`
+	Reading      string `json:"reading" validate:"required_with=PitchAccent,omitempty,kana,max=100"`
+	PartOfSpeech string `json:"part_of_speech" validate:"omitempty,oneof=noun godan_verb ichidan_verb suru_verb kuru_verb i_adjective na_adjective adverb other"`
+	PitchAccent  *int   `json:"pitch_accent" validate:"omitempty,min=0,maxmorae=Reading"`
 }
 
+// 同じリクエスト内で重複したWordは、on_duplicateによらず不正とする
 type MultipleWordsCreationRequest struct {
-	Words []WordCreationRequest `json:"words"`
+	Words []WordCreationRequest `json:"words" validate:"unique=Word,dive"`
+}
+
+// 追加しようとしたWordが既に存在する場合の扱い
+const (
+	OnDuplicateError  = "error"  // 409を返す（既定）
+	OnDuplicateSkip   = "skip"   // 既存のWordを変更せずに返す
+	OnDuplicateUpsert = "upsert" // 既存のWordのメモと、指定された読み、品詞、アクセントを更新して返す
+)
+
+func IsValidOnDuplicate(onDuplicate string) bool {
+	switch onDuplicate {
+	case OnDuplicateError, OnDuplicateSkip, OnDuplicateUpsert:
+		return true
+	}
+	return false
 }
 
+// 品詞
+// 動詞と形容詞は、品詞に従って活用形をNotationとして追加する
+const (
+	PartOfSpeechNoun        = "noun"
+	PartOfSpeechGodanVerb   = "godan_verb"
+	PartOfSpeechIchidanVerb = "ichidan_verb"
+	PartOfSpeechSuruVerb    = "suru_verb"
+	PartOfSpeechKuruVerb    = "kuru_verb"
+	PartOfSpeechIAdjective  = "i_adjective"
+	PartOfSpeechNaAdjective = "na_adjective"
+	PartOfSpeechAdverb      = "adverb"
+	PartOfSpeechOther       = "other"
+)
+
 type WordCreation struct {
-	Word        string
-	Memo        string
-	LoginUserId uint64
+	Word         string
+	Memo         string
+	Reading      string
+	PartOfSpeech string
+	PitchAccent  *int
+	LoginUserId  uint64
+	OnDuplicate  string
 }
 
+// 省略した読み、品詞、アクセントは未入力に戻る
 type WordUpdateRequest struct {
-	Id   uint64 `json:"id"` 
-	Word string `json:"word"`
-	Memo string `json:"memo"`
+	Id           uint64 `json:"id"` 
+	Word         string `json:"word" validate:"required,trimmed,nocontrol,max=100"`
+	Memo         string `json:"memo" validate:"nocontrol=multiline,max=500"`
+	Reading      string `json:"reading" validate:"required_with=PitchAccent,omitempty,kana,max=100"`
+	PartOfSpeech string `json:"part_of_speech" validate:"omitempty,oneof=noun godan_verb ichidan_verb suru_verb kuru_verb i_adjective na_adjective adverb other"`
+	PitchAccent  *int   `json:"pitch_accent" validate:"omitempty,min=0,maxmorae=Reading"`
 }
 
 type WordUpdate struct {
-	Id          uint64
-	Word        string
-	Memo        string
+	Id           uint64
+	Word         string
+	Memo         string
+	Reading      string
+	PartOfSpeech string
+	PitchAccent  *int
+	LoginUserId  uint64
+}
+
+// Wordの統合時のメモの扱い
+const (
+	MemoMergeConcat = "concat" // 統合先、統合元の順に改行で連結する（既定）
+	MemoMergeKeep   = "keep"   // 統合先のメモを残す
+	MemoMergeSource = "source" // 統合元のメモを使用する
+)
+
+type WordMergeRequest struct {
+	// 統合元のWordのId
+	// 統合元のWordは、統合後に削除される
+	FromWordId   uint64 `json:"from_word_id" validate:"required"`
+	MemoStrategy string `json:"memo_strategy" validate:"omitempty,oneof=concat keep source"`
+}
+
+type WordMerge struct {
+	Id           uint64
+	FromWordId   uint64
+	MemoStrategy string
+	LoginUserId  uint64
+}
+// Word一覧の並び替えに使用できる列
+const (
+	WordSortWord      = "word"
+	WordSortCreatedAt = "created_at"
+	WordSortUpdatedAt = "updated_at"
+)
+
+// 並び順
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Cursorには前回取得したページのnext_cursorを指定する
+// 空文字列の場合は先頭から取得する
+type WordListQuery struct {
 	LoginUserId uint64
-}
\ No newline at end of file
+	Limit       uint64
+	Sort        string
+	Order       string
+	Cursor      string
+	Filter      ListFilter
+}
+
+// 前回取得したページの最後のWordの位置
+// (Sortの列の値, Id) より後ろのWordを次のページとする
+type WordCursor struct {
+	Sort  string `json:"sort"`
+	Order string `json:"order"`
+	Value string `json:"value"`
+	Id    uint64 `json:"id"`
+}
+
+// 次のページが無い場合、NextCursorは空文字列
+type WordPage struct {
+	Words      []Word
+	NextCursor string
+	TotalCount uint64
+}
+
+type WordPageResponse struct {
+	Words      []WordResponse `json:"words"`
+	NextCursor *string        `json:"next_cursor"`
+	TotalCount uint64         `json:"total_count"`
+}
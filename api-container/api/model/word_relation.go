@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+// Word同士の関係の種類
+const (
+	WordRelationKindSynonym = "synonym" // 類義語
+	WordRelationKindAntonym = "antonym" // 対義語
+	// WordIdのWordが、RelatedWordIdのWordを含む複合語
+	WordRelationKindCompound = "compound"
+	// WordIdのWordから、RelatedWordIdのWordを参照する
+	WordRelationKindSeeAlso = "see_also"
+)
+
+// Word同士の関係
+// 2つのWordの間には、向きによらず種類ごとに1つの関係のみ存在する
+type WordRelation struct {
+	Id            uint64
+	WordId        uint64
+	RelatedWordId uint64
+	Kind          string
+	// RelatedWordIdのWordからWordIdのWordへも同じ関係が成り立つか
+	Bidirectional bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type WordRelationResponse struct {
+	Id            uint64 `json:"id"`
+	WordId        uint64 `json:"word_id"`
+	RelatedWordId uint64 `json:"related_word_id"`
+	Kind          string `json:"kind"`
+	Bidirectional bool   `json:"bidirectional"`
+}
+
+// bidirectionalを省略した場合、synonym、antonymは双方向、compound、see_alsoは一方向とする
+type WordRelationRequest struct {
+	RelatedWordId uint64 `json:"related_word_id" validate:"required"`
+	Kind          string `json:"kind" validate:"required,oneof=synonym antonym compound see_also"`
+	Bidirectional *bool  `json:"bidirectional"`
+}
+
+// 関係するWordは変更できない
+// bidirectionalを省略した場合は変更しない
+type WordRelationUpdateRequest struct {
+	Kind          string `json:"kind" validate:"required,oneof=synonym antonym compound see_also"`
+	Bidirectional *bool  `json:"bidirectional"`
+}
+
+type WordRelationCreation struct {
+	WordId        uint64
+	RelatedWordId uint64
+	Kind          string
+	Bidirectional *bool
+	LoginUserId   uint64
+}
+
+type WordRelationUpdate struct {
+	Id            uint64
+	WordId        uint64
+	Kind          string
+	Bidirectional *bool
+	LoginUserId   uint64
+}
+
+func IsBidirectionalByDefault(kind string) bool {
+	return kind == WordRelationKindSynonym || kind == WordRelationKindAntonym
+}
+
+// WordまたはNotationが他のWordに含まれることから提案する、compoundの関係
+// WordIdのWordが、RelatedWordIdのWordを含む
+type WordRelationSuggestion struct {
+	WordId        uint64
+	RelatedWordId uint64
+	Kind          string
+	// WordIdのWord中で、RelatedWordIdのWordまたはNotationに一致した部分
+	MatchedText string
+	// RelatedWordIdのWord自体に一致した場合は0
+	NotationId uint64
+}
+
+type WordRelationSuggestionResponse struct {
+	WordId        uint64 `json:"word_id"`
+	RelatedWordId uint64 `json:"related_word_id"`
+	Kind          string `json:"kind"`
+	MatchedText   string `json:"matched_text"`
+	// Notationに一致した場合のみ返す
+	NotationId uint64 `json:"notation_id,omitempty"`
+}
@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+// 意味が使用される場面、文体
+const (
+	RegisterFormal     = "formal"
+	RegisterInformal   = "informal"
+	RegisterPolite     = "polite"
+	RegisterHonorific  = "honorific"  // 尊敬語
+	RegisterHumble     = "humble"     // 謙譲語
+	RegisterColloquial = "colloquial" // 口語
+	RegisterSlang      = "slang"
+	RegisterLiterary   = "literary" // 文語
+	RegisterArchaic    = "archaic"  // 古語
+)
+
+// Wordの意味
+type WordSense struct {
+	Id     uint64
+	WordId uint64
+	// 意味の説明、訳語
+	Gloss string
+	// Glossの言語（BCP 47の言語タグ）
+	Language string
+	// 未入力の場合は空文字列
+	PartOfSpeech string
+	Register     string
+	// Word内での表示順（昇順）
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WordSenseResponse struct {
+	Id       uint64 `json:"id"`
+	WordId   uint64 `json:"word_id"`
+	Gloss    string `json:"gloss"`
+	Language string `json:"language"`
+	// 入力された場合のみ返す
+	PartOfSpeech string `json:"part_of_speech,omitempty"`
+	Register     string `json:"register,omitempty"`
+	Position     int    `json:"position"`
+}
+
+// positionを省略した場合、追加時はWordの最後の意味とし、更新時は変更しない
+type WordSenseRequest struct {
+	Gloss        string `json:"gloss" validate:"required,trimmed,nocontrol=multiline,max=500"`
+	Language     string `json:"language" validate:"required,bcp47_language_tag,max=35"`
+	PartOfSpeech string `json:"part_of_speech" validate:"omitempty,oneof=noun godan_verb ichidan_verb suru_verb kuru_verb i_adjective na_adjective adverb other"`
+	Register     string `json:"register" validate:"omitempty,oneof=formal informal polite honorific humble colloquial slang literary archaic"`
+	Position     *int   `json:"position" validate:"omitempty,min=1"`
+}
+
+type WordSenseCreation struct {
+	WordId       uint64
+	Gloss        string
+	Language     string
+	PartOfSpeech string
+	Register     string
+	Position     *int
+	LoginUserId  uint64
+}
+
+type WordSenseUpdate struct {
+	Id           uint64
+	WordId       uint64
+	Gloss        string
+	Language     string
+	PartOfSpeech string
+	Register     string
+	Position     *int
+	LoginUserId  uint64
+}
+
+// SentenceがWordのどの意味の例文であるか
+// sentences_wordsの紐づけの再構築時も削除されない
+type SentenceWordSense struct {
+	SentenceId uint64
+	WordId     uint64
+	SenseId    uint64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type SentenceWordSenseResponse struct {
+	SentenceId uint64 `json:"sentence_id"`
+	WordId     uint64 `json:"word_id"`
+	SenseId    uint64 `json:"sense_id"`
+}
+
+type SentenceWordSenseRequest struct {
+	SenseId uint64 `json:"sense_id" validate:"required"`
+}
+
+type SentenceWordSenseUpsert struct {
+	SentenceId  uint64
+	WordId      uint64
+	SenseId     uint64
+	LoginUserId uint64
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+type IDeckRepository interface {
+	GetAllDecks(userId uint64) ([]model.Deck, error)
+	GetDeckById(userId, deckId uint64) (model.Deck, error)
+	GetDeckByName(userId uint64, parentId *uint64, name string) (model.Deck, error)
+	InsertDeck(deckCreation model.DeckCreation) (model.Deck, error)
+	UpdateDeck(deckUpdate model.DeckUpdate) (model.Deck, error)
+	DeleteDeckById(userId, deckId uint64) (model.Deck, error)
+	GetDeckTreeIds(deckId uint64) ([]uint64, error)
+	IsAssociationScoped(deckId uint64) (bool, error)
+	AddWordToDeck(deckId, wordId uint64) error
+	RemoveWordFromDeck(deckId, wordId uint64) error
+	MoveDeckWords(fromWordId, toWordId uint64) error
+	AddSentenceToDeck(deckId, sentenceId uint64) error
+	RemoveSentenceFromDeck(deckId, sentenceId uint64) error
+	GetAssociationScopes(userId uint64) ([]model.AssociationScope, error)
+}
+
+type DeckRepository struct {
+	db DBTX
+}
+
+func NewDeckRepository(db DBTX) IDeckRepository {
+	return &DeckRepository{db}
+}
+
+func (dr *DeckRepository) getSequenceName() string {
+	return "deck_id_seq"
+}
+
+func (dr *DeckRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", dr.getSequenceName())
+}
+
+const deckColumns = `id, name, user_id, parent_id, scope_association, created_at, updated_at`
+
+// $%dのデッキと、その子孫のデッキのIdを取得するクエリ
+// parent_idが循環していても終了するよう、UNIONで重複を除く
+// デッキのIdはuint64のため、INTEGERに収まらない値でも失敗しないようBIGINTとして扱う
+const deckTreeQuery = `
+	WITH RECURSIVE deck_tree(id) AS (
+		SELECT CAST($%d AS BIGINT)
+		UNION
+		SELECT CAST(d.id AS BIGINT) FROM decks d JOIN deck_tree t ON d.parent_id = t.id
+	)
+	SELECT id FROM deck_tree`
+
+func scanDeck(row interface{ Scan(...any) error }) (model.Deck, error) {
+	deck := model.Deck{}
+	var parentId sql.NullInt64
+
+	err := row.Scan(
+		&deck.Id,
+		&deck.Name,
+		&deck.UserId,
+		&parentId,
+		&deck.ScopeAssociation,
+		&deck.CreatedAt,
+		&deck.UpdatedAt,
+	)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	if parentId.Valid {
+		id := uint64(parentId.Int64)
+		deck.ParentId = &id
+	}
+
+	return deck, nil
+}
+
+func (dr *DeckRepository) queryIds(query string, args ...any) ([]uint64, error) {
+	rows, err := dr.db.Query(query, args...)
+	if err != nil {
+		return []uint64{}, err
+	}
+	defer rows.Close()
+
+	ids := []uint64{}
+	for rows.Next() {
+		var id uint64
+		err := rows.Scan(&id)
+		if err != nil {
+			return []uint64{}, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (dr *DeckRepository) GetAllDecks(userId uint64) ([]model.Deck, error) {
+	rows, err := dr.db.Query(`
+		SELECT `+deckColumns+` FROM decks
+		WHERE user_id = $1
+		ORDER BY name, id;
+		`,
+		userId,
+	)
+	if err != nil {
+		return []model.Deck{}, err
+	}
+	defer rows.Close()
+
+	decks := []model.Deck{}
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return []model.Deck{}, err
+		}
+		decks = append(decks, deck)
+	}
+
+	return decks, nil
+}
+
+func (dr *DeckRepository) GetDeckById(userId, deckId uint64) (model.Deck, error) {
+	// deckIdの所有者がuserIdでない場合はsql.ErrNoRowsを返す
+	return scanDeck(dr.db.QueryRow(`
+		SELECT `+deckColumns+` FROM decks
+		WHERE user_id = $1
+			AND id = $2;
+		`,
+		userId,
+		deckId,
+	))
+}
+
+func (dr *DeckRepository) GetDeckByName(userId uint64, parentId *uint64, name string) (model.Deck, error) {
+	// parentIdの下の、nameと一致するデッキを取得
+	// parentIdがnilの場合は最上位のデッキから探す
+	// 存在しない場合はsql.ErrNoRowsを返す
+	return scanDeck(dr.db.QueryRow(`
+		SELECT `+deckColumns+` FROM decks
+		WHERE user_id = $1
+			AND parent_id IS NOT DISTINCT FROM $2
+			AND name = $3;
+		`,
+		userId,
+		parentId,
+		name,
+	))
+}
+
+func (dr *DeckRepository) InsertDeck(deckCreation model.DeckCreation) (model.Deck, error) {
+	// 同じ親の下に同じ名前のデッキが既に存在する場合は追加せず、sql.ErrNoRowsを返す
+	return scanDeck(dr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO decks
+		(id, name, user_id, parent_id, scope_association)
+		VALUES(%s, $1, $2, $3, $4)
+		ON CONFLICT (user_id, parent_id, name) DO NOTHING
+		RETURNING `+deckColumns+`;
+		`,
+		dr.getSequenceNextvalQuery(),
+	),
+		deckCreation.Name,
+		deckCreation.LoginUserId,
+		deckCreation.ParentId,
+		deckCreation.ScopeAssociation,
+	))
+}
+
+func (dr *DeckRepository) UpdateDeck(deckUpdate model.DeckUpdate) (model.Deck, error) {
+	return scanDeck(dr.db.QueryRow(`
+		UPDATE decks
+		SET name = $1,
+			parent_id = $2,
+			scope_association = $3
+		WHERE user_id = $4
+			AND id = $5
+		RETURNING `+deckColumns+`;
+		`,
+		deckUpdate.Name,
+		deckUpdate.ParentId,
+		deckUpdate.ScopeAssociation,
+		deckUpdate.LoginUserId,
+		deckUpdate.Id,
+	))
+}
+
+func (dr *DeckRepository) DeleteDeckById(userId, deckId uint64) (model.Deck, error) {
+	// 子孫のデッキと、decks_words、decks_sentencesのレコードは外部キーにより削除される
+	return scanDeck(dr.db.QueryRow(`
+		DELETE FROM decks
+		WHERE user_id = $1
+			AND id = $2
+		RETURNING `+deckColumns+`;
+		`,
+		userId,
+		deckId,
+	))
+}
+
+func (dr *DeckRepository) GetDeckTreeIds(deckId uint64) ([]uint64, error) {
+	// deckIdのデッキと、その子孫のデッキのIdを取得
+	return dr.queryIds(fmt.Sprintf(deckTreeQuery, 1)+";", deckId)
+}
+
+func (dr *DeckRepository) IsAssociationScoped(deckId uint64) (bool, error) {
+	// deckIdのデッキ、またはその祖先のデッキのscope_associationがtrueであるかを判定
+	// trueの場合、deckIdのデッキへのWord、Sentenceの追加、削除により紐づけが変わる
+	var isScoped bool
+	err := dr.db.QueryRow(`
+		WITH RECURSIVE ancestors(id, parent_id, scope_association) AS (
+			SELECT id, parent_id, scope_association FROM decks WHERE id = $1
+			UNION
+			SELECT d.id, d.parent_id, d.scope_association
+			FROM decks d JOIN ancestors a ON d.id = a.parent_id
+		)
+		SELECT COALESCE(BOOL_OR(scope_association), FALSE) FROM ancestors;
+		`,
+		deckId,
+	).Scan(&isScoped)
+	if err != nil {
+		return false, err
+	}
+
+	return isScoped, nil
+}
+
+// 以下のデッキへの追加、削除は、WordまたはSentenceとデッキの所有者が同じであることを前提とする
+// 既に含まれるものの追加、含まれないものの削除はエラーとしない
+
+func (dr *DeckRepository) AddWordToDeck(deckId, wordId uint64) error {
+	_, err := dr.db.Exec(`
+		INSERT INTO decks_words
+		(deck_id, word_id)
+		VALUES($1, $2)
+		ON CONFLICT (deck_id, word_id) DO NOTHING;
+		`,
+		deckId,
+		wordId,
+	)
+
+	return err
+}
+
+func (dr *DeckRepository) RemoveWordFromDeck(deckId, wordId uint64) error {
+	_, err := dr.db.Exec(`
+		DELETE FROM decks_words
+		WHERE deck_id = $1
+			AND word_id = $2;
+		`,
+		deckId,
+		wordId,
+	)
+
+	return err
+}
+
+func (dr *DeckRepository) MoveDeckWords(fromWordId, toWordId uint64) error {
+	// fromWordIdのWordを含むDeckに、代わりにtoWordIdのWordを含める
+	// toWordIdのWordを既に含むDeckでは移さない
+	_, err := dr.db.Exec(`
+		UPDATE decks_words d
+		SET word_id = $2
+		WHERE d.word_id = $1
+			AND NOT EXISTS(
+				SELECT 1
+				FROM decks_words k
+				WHERE k.word_id = $2
+					AND k.deck_id = d.deck_id
+			);
+		`,
+		fromWordId,
+		toWordId,
+	)
+
+	return err
+}
+
+func (dr *DeckRepository) AddSentenceToDeck(deckId, sentenceId uint64) error {
+	_, err := dr.db.Exec(`
+		INSERT INTO decks_sentences
+		(deck_id, sentence_id)
+		VALUES($1, $2)
+		ON CONFLICT (deck_id, sentence_id) DO NOTHING;
+		`,
+		deckId,
+		sentenceId,
+	)
+
+	return err
+}
+
+func (dr *DeckRepository) RemoveSentenceFromDeck(deckId, sentenceId uint64) error {
+	_, err := dr.db.Exec(`
+		DELETE FROM decks_sentences
+		WHERE deck_id = $1
+			AND sentence_id = $2;
+		`,
+		deckId,
+		sentenceId,
+	)
+
+	return err
+}
+
+func (dr *DeckRepository) GetAssociationScopes(userId uint64) ([]model.AssociationScope, error) {
+	// userIdの、scope_associationがtrueの各デッキについて、子孫のデッキを含めたWordとSentenceを取得
+	// デッキの件数によらず1回のクエリで行う
+	rows, err := dr.db.Query(`
+		WITH RECURSIVE scoped(deck_id, member_deck_id) AS (
+			SELECT id, id FROM decks
+			WHERE user_id = $1
+				AND scope_association
+			UNION
+			SELECT s.deck_id, d.id
+			FROM decks d JOIN scoped s ON d.parent_id = s.member_deck_id
+		)
+		SELECT s.deck_id, dw.word_id, 0
+		FROM scoped s JOIN decks_words dw ON dw.deck_id = s.member_deck_id
+		UNION
+		SELECT s.deck_id, 0, ds.sentence_id
+		FROM scoped s JOIN decks_sentences ds ON ds.deck_id = s.member_deck_id;
+		`,
+		userId,
+	)
+	if err != nil {
+		return []model.AssociationScope{}, err
+	}
+	defer rows.Close()
+
+	scopesByDeckId := map[uint64]*model.AssociationScope{}
+	for rows.Next() {
+		var deckId, wordId, sentenceId uint64
+		err := rows.Scan(&deckId, &wordId, &sentenceId)
+		if err != nil {
+			return []model.AssociationScope{}, err
+		}
+
+		scope, ok := scopesByDeckId[deckId]
+		if !ok {
+			scope = &model.AssociationScope{DeckId: deckId}
+			scopesByDeckId[deckId] = scope
+		}
+
+		if wordId != 0 {
+			scope.WordIds = append(scope.WordIds, wordId)
+		}
+		if sentenceId != 0 {
+			scope.SentenceIds = append(scope.SentenceIds, sentenceId)
+		}
+	}
+
+	scopes := []model.AssociationScope{}
+	for _, scope := range scopesByDeckId {
+		scopes = append(scopes, *scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].DeckId < scopes[j].DeckId
+	})
+
+	return scopes, nil
+}
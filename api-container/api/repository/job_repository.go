@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type IJobRepository interface {
+	InsertJob(jobCreation model.JobCreation) (model.Job, error)
+	GetJobById(userId, jobId uint64) (model.Job, error)
+	GetUnfinishedJobByTargetId(userId uint64, kind string, targetId uint64) (model.Job, error)
+	ClaimNextJob(lockTimeout time.Duration) (model.Job, error)
+	DeadLetterStaleJobs(lockTimeout time.Duration, lastError string) error
+	CompleteJob(jobId uint64) error
+	RetryJob(jobId uint64, lastError string, runAt time.Time) error
+	DeadLetterJob(jobId uint64, lastError string) error
+}
+
+type JobRepository struct {
+	db DBTX
+}
+
+func NewJobRepository(db DBTX) IJobRepository {
+	return &JobRepository{db}
+}
+
+func (jr *JobRepository) getSequenceName() string {
+	return "job_id_seq"
+}
+
+func (jr *JobRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", jr.getSequenceName())
+}
+
+const jobColumns = `id, user_id, kind, target_ids, status, attempts, max_attempts,
+			last_error, run_at, finished_at, created_at, updated_at`
+
+func scanJob(row interface{ Scan(...any) error }) (model.Job, error) {
+	job := model.Job{}
+	var targetIds []int64
+	var finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.Id,
+		&job.UserId,
+		&job.Kind,
+		pq.Array(&targetIds),
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&finishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return model.Job{}, err
+	}
+
+	for _, targetId := range targetIds {
+		job.TargetIds = append(job.TargetIds, uint64(targetId))
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return job, nil
+}
+
+func (jr *JobRepository) InsertJob(jobCreation model.JobCreation) (model.Job, error) {
+	targetIds := make([]int64, len(jobCreation.TargetIds))
+	for i, targetId := range jobCreation.TargetIds {
+		targetIds[i] = int64(targetId)
+	}
+
+	row := jr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO jobs
+		(id, user_id, kind, target_ids, max_attempts)
+		VALUES(%s, $1, $2, $3, $4)
+		RETURNING %s;
+		`,
+		jr.getSequenceNextvalQuery(),
+		jobColumns,
+	),
+		jobCreation.UserId,
+		jobCreation.Kind,
+		pq.Array(targetIds),
+		jobCreation.MaxAttempts,
+	)
+
+	return scanJob(row)
+}
+
+func (jr *JobRepository) GetJobById(userId, jobId uint64) (model.Job, error) {
+	row := jr.db.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM jobs
+		WHERE id = $1 AND user_id = $2;
+		`,
+		jobColumns,
+	),
+		jobId,
+		userId,
+	)
+
+	return scanJob(row)
+}
+
+func (jr *JobRepository) GetUnfinishedJobByTargetId(userId uint64, kind string, targetId uint64) (model.Job, error) {
+	// targetIdを対象とする、完了していないジョブのうち最も新しいものを取得
+	row := jr.db.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM jobs
+		WHERE user_id = $1
+			AND kind = $2
+			AND target_ids @> ARRAY[$3::INTEGER]
+			AND status IN ('pending', 'running')
+		ORDER BY id DESC
+		LIMIT 1;
+		`,
+		jobColumns,
+	),
+		userId,
+		kind,
+		targetId,
+	)
+
+	return scanJob(row)
+}
+
+func (jr *JobRepository) ClaimNextJob(lockTimeout time.Duration) (model.Job, error) {
+	// 実行待ちのジョブを1件取り出し、実行中にする
+	// 他のワーカーが取り出し中のジョブはSKIP LOCKEDにより飛ばすため、同じジョブを複数のワーカーが実行することは無い
+	// 実行中のままlockTimeoutを過ぎたジョブは、ワーカーが停止したものとみなし再度取り出す
+	// ただし最大試行回数に達したものは取り出さない（DeadLetterStaleJobsでdeadにする）
+	// 実行待ちのジョブが無い場合はsql.ErrNoRowsを返す
+	row := jr.db.QueryRow(fmt.Sprintf(`
+		UPDATE jobs
+		SET status = 'running',
+			attempts = attempts + 1,
+			locked_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pending' AND run_at <= CURRENT_TIMESTAMP)
+				OR (
+					status = 'running'
+					AND locked_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'
+					AND attempts < max_attempts
+				)
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s;
+		`,
+		jobColumns,
+	),
+		lockTimeout.Seconds(),
+	)
+
+	return scanJob(row)
+}
+
+func (jr *JobRepository) DeadLetterStaleJobs(lockTimeout time.Duration, lastError string) error {
+	// 実行中のままlockTimeoutを過ぎ、最大試行回数に達したジョブを、再度実行しないようdeadにする
+	// 他のワーカーが取り出し中のジョブは、SKIP LOCKEDにより飛ばす
+	_, err := jr.db.Exec(`
+		UPDATE jobs
+		SET status = 'dead',
+			last_error = $2,
+			locked_at = NULL,
+			finished_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE status = 'running'
+				AND locked_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'
+				AND attempts >= max_attempts
+			FOR UPDATE SKIP LOCKED
+		);
+		`,
+		lockTimeout.Seconds(),
+		lastError,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (jr *JobRepository) CompleteJob(jobId uint64) error {
+	_, err := jr.db.Exec(`
+		UPDATE jobs
+		SET status = 'succeeded',
+			locked_at = NULL,
+			finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+		`,
+		jobId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (jr *JobRepository) RetryJob(jobId uint64, lastError string, runAt time.Time) error {
+	// 失敗したジョブを、runAt以降に再度実行するよう実行待ちに戻す
+	_, err := jr.db.Exec(`
+		UPDATE jobs
+		SET status = 'pending',
+			last_error = $2,
+			run_at = $3,
+			locked_at = NULL
+		WHERE id = $1;
+		`,
+		jobId,
+		lastError,
+		runAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (jr *JobRepository) DeadLetterJob(jobId uint64, lastError string) error {
+	// 最大試行回数に達したジョブを、再度実行しないようdeadにする
+	_, err := jr.db.Exec(`
+		UPDATE jobs
+		SET status = 'dead',
+			last_error = $2,
+			locked_at = NULL,
+			finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+		`,
+		jobId,
+		lastError,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"api/model"
+	"fmt"
+)
+
+func listFilterCondition(item string, filter model.ListFilter, args []any) (string, []any) {
+	// Word、Sentenceの一覧をfilterで絞り込む、WHERE句に続けるための条件を作成
+	// itemは "word" または "sentence" とし、条件中の値はargsに続くプレースホルダとする
+	// filter.Sourceは、itemが "sentence" の場合のみ指定する
+	// タグ、デッキの所有者は確認しないが、一覧自体がUserで絞り込まれるため他のUserのものは含まれない
+	condition := ""
+
+	if filter.TagId != 0 {
+		args = append(args, filter.TagId)
+		condition += fmt.Sprintf(
+			" AND id IN (SELECT %s_id FROM %ss_tags WHERE tag_id = $%d)",
+			item, item, len(args),
+		)
+	}
+
+	if filter.DeckId != 0 {
+		args = append(args, filter.DeckId)
+		condition += fmt.Sprintf(
+			" AND id IN (SELECT %s_id FROM decks_%ss WHERE deck_id IN (%s))",
+			item, item, fmt.Sprintf(deckTreeQuery, len(args)),
+		)
+	}
+
+	if filter.Source != "" {
+		// 出典はSentenceのみ
+		args = append(args, filter.Source)
+		condition += fmt.Sprintf(" AND (source_title = $%d OR source_url = $%d)", len(args), len(args))
+	}
+
+	return condition, args
+}
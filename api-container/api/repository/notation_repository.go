@@ -4,22 +4,30 @@ import (
 	"api/model"
 	"database/sql"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
 type INotationRepository interface {
 	GetAllNotations(uint64) ([]model.Notation, error)
+	GetAllNotationsByUserId(userId uint64) ([]model.Notation, error)
+	GetNotationsByWordIds(wordIds []uint64) ([]model.Notation, error)
+	GetNotationsByKind(wordId uint64, kind string) ([]model.Notation, error)
 	GetNotationById(uint64) (model.Notation, error)
+	GetNotationByNotation(wordId uint64, notation string) (model.Notation, error)
 	InsertNotation(model.NotationCreation) (model.Notation, error)
 	UpdateNotation(model.NotationUpdate) (model.Notation, error)
 	DeleteNotationById(uint64) (model.Notation, error)
 	DeleteNotationIfExists(wordId uint64, notation string) (model.Notation, error)
+	DeleteGeneratedNotations(wordId uint64) error
+	MoveNotations(fromWordId, toWordId uint64) error
 }
 
 type NotationRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewNotationRepository(db *sql.DB) INotationRepository {
+func NewNotationRepository(db DBTX) INotationRepository {
 	return &NotationRepository{db}
 }
 
@@ -35,10 +43,132 @@ func (nr *NotationRepository) GetAllNotations(wordId uint64) ([]model.Notation,
 	var notations []model.Notation
 
 	rows, err := nr.db.Query(`
-		SELECT id, word_id, notation, created_at, updated_at FROM notations
+		SELECT id, word_id, notation, kind, created_at, updated_at FROM notations
+		WHERE word_id = $1
+		`,
+		wordId,
+	)
+	if err != nil {
+		return []model.Notation{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notation := model.Notation{}
+		err := rows.Scan(
+			&notation.Id,
+			&notation.WordId,
+			&notation.Notation,
+			&notation.Kind,
+			&notation.CreatedAt,
+			&notation.UpdatedAt,
+		);
+		if err != nil {
+			return []model.Notation{}, err
+		}
+		notations = append(notations, notation)
+	}
+
+	return notations, nil
+}
+
+func (nr *NotationRepository) GetAllNotationsByUserId(userId uint64) ([]model.Notation, error) {
+	// userIdの所有する全WordのNotationを、1回のクエリで取得
+	var notations []model.Notation
+
+	rows, err := nr.db.Query(`
+		SELECT
+			notations.id,
+			notations.word_id,
+			notations.notation,
+			notations.kind,
+			notations.created_at,
+			notations.updated_at
+		FROM notations
+		INNER JOIN words
+			ON notations.word_id = words.id
+		WHERE words.user_id = $1
+		`,
+		userId,
+	)
+	if err != nil {
+		return []model.Notation{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notation := model.Notation{}
+		err := rows.Scan(
+			&notation.Id,
+			&notation.WordId,
+			&notation.Notation,
+			&notation.Kind,
+			&notation.CreatedAt,
+			&notation.UpdatedAt,
+		)
+		if err != nil {
+			return []model.Notation{}, err
+		}
+		notations = append(notations, notation)
+	}
+
+	return notations, nil
+}
+
+func (nr *NotationRepository) GetNotationsByWordIds(wordIds []uint64) ([]model.Notation, error) {
+	// wordIdsの各WordのNotationを、1回のクエリでまとめて取得
+	// Wordごとに、NotationのId順に返す
+	if len(wordIds) == 0 {
+		return []model.Notation{}, nil
+	}
+
+	ids := make([]int64, len(wordIds))
+	for i, wordId := range wordIds {
+		ids[i] = int64(wordId)
+	}
+
+	rows, err := nr.db.Query(`
+		SELECT id, word_id, notation, kind, created_at, updated_at FROM notations
+		WHERE word_id = ANY($1::INTEGER[])
+		ORDER BY word_id, id;
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return []model.Notation{}, err
+	}
+	defer rows.Close()
+
+	notations := []model.Notation{}
+	for rows.Next() {
+		notation := model.Notation{}
+		err := rows.Scan(
+			&notation.Id,
+			&notation.WordId,
+			&notation.Notation,
+			&notation.Kind,
+			&notation.CreatedAt,
+			&notation.UpdatedAt,
+		)
+		if err != nil {
+			return []model.Notation{}, err
+		}
+		notations = append(notations, notation)
+	}
+
+	return notations, nil
+}
+
+func (nr *NotationRepository) GetNotationsByKind(wordId uint64, kind string) ([]model.Notation, error) {
+	var notations []model.Notation
+
+	rows, err := nr.db.Query(`
+		SELECT id, word_id, notation, kind, created_at, updated_at FROM notations
 		WHERE word_id = $1
+		AND kind = $2
 		`,
 		wordId,
+		kind,
 	)
 	if err != nil {
 		return []model.Notation{}, err
@@ -51,6 +181,7 @@ func (nr *NotationRepository) GetAllNotations(wordId uint64) ([]model.Notation,
 			&notation.Id,
 			&notation.WordId,
 			&notation.Notation,
+			&notation.Kind,
 			&notation.CreatedAt,
 			&notation.UpdatedAt,
 		);
@@ -67,7 +198,7 @@ func (nr *NotationRepository) GetNotationById(id uint64) (model.Notation, error)
 	notation := model.Notation{}
 
 	err := nr.db.QueryRow(`
-		SELECT id, word_id, notation, created_at, updated_at FROM notations
+		SELECT id, word_id, notation, kind, created_at, updated_at FROM notations
 		WHERE id = $1
 		`,
 		id,
@@ -75,6 +206,7 @@ func (nr *NotationRepository) GetNotationById(id uint64) (model.Notation, error)
 		&notation.Id,
 		&notation.WordId,
 		&notation.Notation,
+		&notation.Kind,
 		&notation.CreatedAt,
 		&notation.UpdatedAt,
 	);
@@ -86,34 +218,59 @@ func (nr *NotationRepository) GetNotationById(id uint64) (model.Notation, error)
 	return notation, nil
 }
 
+func (nr *NotationRepository) GetNotationByNotation(wordId uint64, notation string) (model.Notation, error) {
+	// wordIdのWordの、notationと一致するNotationを取得
+	// 存在しない場合はsql.ErrNoRowsを返す
+	foundNotation := model.Notation{}
+
+	err := nr.db.QueryRow(`
+		SELECT id, word_id, notation, kind, created_at, updated_at FROM notations
+		WHERE word_id = $1
+			AND notation = $2
+		`,
+		wordId,
+		notation,
+	).Scan(
+		&foundNotation.Id,
+		&foundNotation.WordId,
+		&foundNotation.Notation,
+		&foundNotation.Kind,
+		&foundNotation.CreatedAt,
+		&foundNotation.UpdatedAt,
+	)
+	if err != nil {
+		return model.Notation{}, err
+	}
+
+	return foundNotation, nil
+}
+
 func (nr *NotationRepository) InsertNotation(notationCreation model.NotationCreation) (model.Notation, error) {
 	// (wordId, notation)の組が存在しない場合にだけ新規追加
+	// 既に存在する場合はsql.ErrNoRowsを返す
 
 	createdNotation := model.Notation{}
 
-	// WHERE NOT EXISTSを付けると、goのstringがPostgreSQLのTEXT型に推論され、
-	// VARCHARの型推論がうまくいかず、エラーが発生する
-	// これを回避するため、明示的にキャストしている
+	// WHERE NOT EXISTSによる確認では、同時に追加された場合に重複してしまうため、
+	// 一意制約との衝突を ON CONFLICT DO NOTHING で無視する
+	// 衝突してもトランザクションは中断されないため、続けて既存のNotationを取得できる
 	err := nr.db.QueryRow(fmt.Sprintf(`
 		INSERT INTO notations
-		(id, word_id, notation)
-		SELECT %s, $1, CAST($2 AS VARCHAR)
-		WHERE NOT EXISTS(
-			SELECT 1
-			FROM notations
-			WHERE word_id = $1
-			AND notation = CAST($2 AS VARCHAR)
-		)
-		RETURNING id, word_id, notation, created_at, updated_at;
+		(id, word_id, notation, kind)
+		VALUES(%s, $1, $2, $3)
+		ON CONFLICT (word_id, notation) DO NOTHING
+		RETURNING id, word_id, notation, kind, created_at, updated_at;
 		`, 
 		nr.getSequenceNextvalQuery(),
 		),
 		notationCreation.WordId,
 		notationCreation.Notation,
+		notationCreation.Kind,
 	).Scan(
 		&createdNotation.Id,
 		&createdNotation.WordId,
 		&createdNotation.Notation,
+		&createdNotation.Kind,
 		&createdNotation.CreatedAt,
 		&createdNotation.UpdatedAt,
 	)
@@ -129,16 +286,19 @@ func (nr *NotationRepository) UpdateNotation(notationUpdate model.NotationUpdate
 
 	err := nr.db.QueryRow(`
 		UPDATE notations
-		SET notation = $1
-		WHERE id = $2
-		RETURNING id, word_id, notation, created_at, updated_at;
+		SET notation = $1,
+			kind = $2
+		WHERE id = $3
+		RETURNING id, word_id, notation, kind, created_at, updated_at;
 		`, 
 		notationUpdate.Notation,
+		notationUpdate.Kind,
 		notationUpdate.Id,
 	).Scan(
 		&updatedNotation.Id,
 		&updatedNotation.WordId,
 		&updatedNotation.Notation,
+		&updatedNotation.Kind,
 		&updatedNotation.CreatedAt,
 		&updatedNotation.UpdatedAt,
 	)
@@ -155,13 +315,14 @@ func (nr *NotationRepository) DeleteNotationById(notationId uint64) (model.Notat
 	err := nr.db.QueryRow(`
 		DELETE FROM notations
 		WHERE id = $1
-		RETURNING id, word_id, notation, created_at, updated_at;
+		RETURNING id, word_id, notation, kind, created_at, updated_at;
 		`, 
 		notationId,
 	).Scan(
 		&deletedNotation.Id,
 		&deletedNotation.WordId,
 		&deletedNotation.Notation,
+		&deletedNotation.Kind,
 		&deletedNotation.CreatedAt,
 		&deletedNotation.UpdatedAt,
 	)
@@ -179,7 +340,7 @@ func (nr *NotationRepository) DeleteNotationIfExists(wordId uint64, notation str
 		DELETE FROM notations
 		WHERE word_id = $1
 		AND notation = $2
-		RETURNING id, word_id, notation, created_at, updated_at;
+		RETURNING id, word_id, notation, kind, created_at, updated_at;
 		`, 
 		wordId,
 		notation,
@@ -187,6 +348,7 @@ func (nr *NotationRepository) DeleteNotationIfExists(wordId uint64, notation str
 		&deletedNotation.Id,
 		&deletedNotation.WordId,
 		&deletedNotation.Notation,
+		&deletedNotation.Kind,
 		&deletedNotation.CreatedAt,
 		&deletedNotation.UpdatedAt,
 	)
@@ -199,4 +361,49 @@ func (nr *NotationRepository) DeleteNotationIfExists(wordId uint64, notation str
 	}
 
 	return deletedNotation, nil
-}
\ No newline at end of file
+}
+
+func (nr *NotationRepository) DeleteGeneratedNotations(wordId uint64) error {
+	// wordIdのNotationのうち、Wordから自動生成されたものを全削除
+	// ユーザーが入力したNotationは削除しない
+	_, err := nr.db.Exec(`
+		DELETE FROM notations
+		WHERE word_id = $1
+		AND kind IN ($2, $3)
+		`,
+		wordId,
+		model.NotationKindStem,
+		model.NotationKindConjugation,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (nr *NotationRepository) MoveNotations(fromWordId, toWordId uint64) error {
+	// fromWordIdのNotationを、toWordIdのWordに移す
+	// toWordIdのWordに同じNotationが既に存在する場合は移さない
+	// 自動生成されたNotationは移した先のWordから生成されたものではないため、ユーザーが入力したものとして扱う
+	_, err := nr.db.Exec(`
+		UPDATE notations n
+		SET word_id = $2,
+			kind = CASE WHEN n.kind IN ($3, $4) THEN $5 ELSE n.kind END
+		WHERE n.word_id = $1
+			AND NOT EXISTS(
+				SELECT 1
+				FROM notations k
+				WHERE k.word_id = $2
+					AND k.notation = n.notation
+			);
+		`,
+		fromWordId,
+		toWordId,
+		model.NotationKindStem,
+		model.NotationKindConjugation,
+		model.NotationKindManual,
+	)
+
+	return err
+}
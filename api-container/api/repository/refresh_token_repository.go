@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+	"fmt"
+)
+
+type IRefreshTokenRepository interface {
+	GetRefreshTokenByHash(tokenHash string) (model.RefreshToken, error)
+	InsertRefreshToken(refreshTokenCreation model.RefreshTokenCreation) (model.RefreshToken, error)
+	RevokeRefreshToken(refreshTokenId uint64, replacedBy *uint64) (bool, error)
+	RevokeRefreshTokenFamily(familyId string) error
+}
+
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) IRefreshTokenRepository {
+	return &RefreshTokenRepository{db}
+}
+
+func (rtr *RefreshTokenRepository) getSequenceName() string {
+	return "refresh_token_id_seq"
+}
+
+func (rtr *RefreshTokenRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", rtr.getSequenceName())
+}
+
+func (rtr *RefreshTokenRepository) GetRefreshTokenByHash(tokenHash string) (model.RefreshToken, error) {
+	// 失効済み、有効期限切れのものも取得する
+	// 失効済みのトークンの再利用を検知するため
+
+	refreshToken := model.RefreshToken{}
+
+	err := rtr.db.QueryRow(`
+		SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by, created_at, updated_at
+		FROM refresh_tokens
+		WHERE token_hash = $1;
+		`,
+		tokenHash,
+	).Scan(
+		&refreshToken.Id,
+		&refreshToken.UserId,
+		&refreshToken.TokenHash,
+		&refreshToken.FamilyId,
+		&refreshToken.ExpiresAt,
+		&refreshToken.RevokedAt,
+		&refreshToken.ReplacedBy,
+		&refreshToken.CreatedAt,
+		&refreshToken.UpdatedAt,
+	)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+
+	return refreshToken, nil
+}
+
+func (rtr *RefreshTokenRepository) InsertRefreshToken(refreshTokenCreation model.RefreshTokenCreation) (model.RefreshToken, error) {
+	createdRefreshToken := model.RefreshToken{}
+
+	err := rtr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO refresh_tokens
+		(id, user_id, token_hash, family_id, expires_at)
+		VALUES(%s, $1, $2, $3, $4)
+		RETURNING id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by, created_at, updated_at;
+		`,
+		rtr.getSequenceNextvalQuery(),
+		),
+		refreshTokenCreation.UserId,
+		refreshTokenCreation.TokenHash,
+		refreshTokenCreation.FamilyId,
+		refreshTokenCreation.ExpiresAt,
+	).Scan(
+		&createdRefreshToken.Id,
+		&createdRefreshToken.UserId,
+		&createdRefreshToken.TokenHash,
+		&createdRefreshToken.FamilyId,
+		&createdRefreshToken.ExpiresAt,
+		&createdRefreshToken.RevokedAt,
+		&createdRefreshToken.ReplacedBy,
+		&createdRefreshToken.CreatedAt,
+		&createdRefreshToken.UpdatedAt,
+	)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+
+	return createdRefreshToken, nil
+}
+
+func (rtr *RefreshTokenRepository) RevokeRefreshToken(refreshTokenId uint64, replacedBy *uint64) (bool, error) {
+	// 未失効のトークンを失効させる
+	// 既に失効済みだった場合はfalseを返す
+
+	result, err := rtr.db.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP,
+			replaced_by = $2
+		WHERE id = $1
+			AND revoked_at IS NULL;
+		`,
+		refreshTokenId,
+		replacedBy,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+func (rtr *RefreshTokenRepository) RevokeRefreshTokenFamily(familyId string) error {
+	// 同じログインから発行されたトークンを全て失効させる
+
+	_, err := rtr.db.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1
+			AND revoked_at IS NULL;
+		`,
+		familyId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type IReviewRepository interface {
+	LockWordForReview(wordId uint64) error
+	GetReviewByWordId(wordId uint64) (model.Review, error)
+	UpsertReview(reviewUpsert model.ReviewUpsert) (model.Review, error)
+	GetDueReviews(userId uint64, dueBefore time.Time, limit uint64) ([]model.DueReview, error)
+}
+
+type ReviewRepository struct {
+	db DBTX
+}
+
+func NewReviewRepository(db DBTX) IReviewRepository {
+	return &ReviewRepository{db}
+}
+
+func (rvr *ReviewRepository) getSequenceName() string {
+	return "review_id_seq"
+}
+
+func (rvr *ReviewRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", rvr.getSequenceName())
+}
+
+func (rvr *ReviewRepository) LockWordForReview(wordId uint64) error {
+	// 同じWordの復習の記録を1件ずつ行うため、トランザクションの終了までwordIdのWordの行をロックする
+	// 1度も復習していないWordはreviewsの行が無いため、wordsの行をロックする
+	_, err := rvr.db.Exec(`
+		SELECT id FROM words
+		WHERE id = $1
+		FOR UPDATE;
+		`,
+		wordId,
+	)
+
+	return err
+}
+
+func (rvr *ReviewRepository) GetReviewByWordId(wordId uint64) (model.Review, error) {
+	review := model.Review{}
+
+	err := rvr.db.QueryRow(`
+		SELECT id, word_id, ease_factor, interval_days, repetitions, due_at,
+			last_grade, last_reviewed_at, created_at, updated_at
+		FROM reviews
+		WHERE word_id = $1;
+		`,
+		wordId,
+	).Scan(
+		&review.Id,
+		&review.WordId,
+		&review.EaseFactor,
+		&review.IntervalDays,
+		&review.Repetitions,
+		&review.DueAt,
+		&review.LastGrade,
+		&review.LastReviewedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return model.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (rvr *ReviewRepository) UpsertReview(reviewUpsert model.ReviewUpsert) (model.Review, error) {
+	// word_idのレコードが無ければ追加し、あれば更新する
+	review := model.Review{}
+
+	err := rvr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO reviews
+		(id, word_id, ease_factor, interval_days, repetitions, due_at, last_grade, last_reviewed_at)
+		VALUES(%s, $1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (word_id) DO UPDATE
+		SET ease_factor = EXCLUDED.ease_factor,
+			interval_days = EXCLUDED.interval_days,
+			repetitions = EXCLUDED.repetitions,
+			due_at = EXCLUDED.due_at,
+			last_grade = EXCLUDED.last_grade,
+			last_reviewed_at = EXCLUDED.last_reviewed_at
+		RETURNING id, word_id, ease_factor, interval_days, repetitions, due_at,
+			last_grade, last_reviewed_at, created_at, updated_at;
+		`,
+		rvr.getSequenceNextvalQuery(),
+	),
+		reviewUpsert.WordId,
+		reviewUpsert.Schedule.EaseFactor,
+		reviewUpsert.Schedule.IntervalDays,
+		reviewUpsert.Schedule.Repetitions,
+		reviewUpsert.Schedule.DueAt,
+		reviewUpsert.Grade,
+		reviewUpsert.ReviewedAt,
+	).Scan(
+		&review.Id,
+		&review.WordId,
+		&review.EaseFactor,
+		&review.IntervalDays,
+		&review.Repetitions,
+		&review.DueAt,
+		&review.LastGrade,
+		&review.LastReviewedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return model.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (rvr *ReviewRepository) GetDueReviews(userId uint64, dueBefore time.Time, limit uint64) ([]model.DueReview, error) {
+	// userIdのWordのうち、dueBeforeまでに復習期限を迎えるものを期限の古い順にlimit件取得
+	// 1度も復習していないWordは、期限を迎えたものより後ろに作成順で含める
+	// Sentencesは取得しないため、呼び出し元で取得する
+	rows, err := rvr.db.Query(`
+		SELECT
+			words.id, words.word, words.memo, words.user_id, words.created_at, words.updated_at,
+			reviews.id, reviews.ease_factor, reviews.interval_days, reviews.repetitions, reviews.due_at,
+			reviews.last_grade, reviews.last_reviewed_at, reviews.created_at, reviews.updated_at
+		FROM words
+		LEFT JOIN reviews ON reviews.word_id = words.id
+		WHERE words.user_id = $1
+			AND (reviews.id IS NULL OR reviews.due_at < $2)
+		ORDER BY reviews.due_at ASC NULLS LAST, words.created_at ASC, words.id ASC
+		LIMIT $3;
+		`,
+		userId,
+		dueBefore,
+		limit,
+	)
+	if err != nil {
+		return []model.DueReview{}, err
+	}
+	defer rows.Close()
+
+	var dueReviews []model.DueReview
+	for rows.Next() {
+		word := model.Word{}
+
+		// 1度も復習していないWordの場合、reviewsの列は全てNULLになる
+		var reviewId, intervalDays, repetitions, lastGrade sql.NullInt64
+		var easeFactor sql.NullFloat64
+		var dueAt, lastReviewedAt, createdAt, updatedAt sql.NullTime
+
+		err := rows.Scan(
+			&word.Id, &word.Word, &word.Memo, &word.UserId, &word.CreatedAt, &word.UpdatedAt,
+			&reviewId, &easeFactor, &intervalDays, &repetitions, &dueAt,
+			&lastGrade, &lastReviewedAt, &createdAt, &updatedAt,
+		)
+		if err != nil {
+			return []model.DueReview{}, err
+		}
+
+		dueReview := model.DueReview{Word: word}
+		if reviewId.Valid {
+			dueReview.Review = &model.Review{
+				Id:             uint64(reviewId.Int64),
+				WordId:         word.Id,
+				EaseFactor:     easeFactor.Float64,
+				IntervalDays:   uint64(intervalDays.Int64),
+				Repetitions:    uint64(repetitions.Int64),
+				DueAt:          dueAt.Time,
+				LastGrade:      uint64(lastGrade.Int64),
+				LastReviewedAt: lastReviewedAt.Time,
+				CreatedAt:      createdAt.Time,
+				UpdatedAt:      updatedAt.Time,
+			}
+		}
+
+		dueReviews = append(dueReviews, dueReview)
+	}
+
+	return dueReviews, nil
+}
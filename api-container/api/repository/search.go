@@ -0,0 +1,15 @@
+package repository
+
+import "strings"
+
+func toContainsPattern(query string) string {
+	// queryを含む文字列にマッチするLIKEのパターンを作成
+	// query中の%、_、\はワイルドカードとして扱わない
+	escaper := strings.NewReplacer(
+		`\`, `\\`,
+		`%`, `\%`,
+		`_`, `\_`,
+	)
+
+	return "%" + escaper.Replace(query) + "%"
+}
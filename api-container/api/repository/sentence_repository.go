@@ -4,24 +4,30 @@ import (
 	"api/model"
 	"database/sql"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
 type ISentenceRepository interface {
 	GetAllSentences(userId uint64) ([]model.Sentence, error)
-	GetAllSentencesWithLimit(userId uint64, limit uint64, offset uint64) ([]model.Sentence, error)
+	GetAllSentencesWithLimit(userId uint64, filter model.ListFilter, limit uint64, offset uint64) ([]model.Sentence, error)
 	GetSentenceById(userId uint64, sentenceId uint64) (model.Sentence, error)
 	InsertSentence(model.SentenceCreation) (model.Sentence, error)
 	UpdateSentence(model.SentenceUpdate) (model.Sentence, error)
 	DeleteSentenceById(userId uint64, sentenceId uint64) (model.Sentence, error)
 	IsSentenceOwner(sentenceId uint64, userId uint64) (bool, error)
-	GetSentencesCount(userId uint64) (uint64, error)
+	GetSentencesCount(userId uint64, filter model.ListFilter) (uint64, error)
+	SearchSentences(userId uint64, query string, limit, offset uint64) ([]model.SentenceSearchHit, error)
+	GetSearchSentencesCount(userId uint64, query string) (uint64, error)
+	GetTranslationsBySentenceIds(sentenceIds []uint64) ([]model.SentenceTranslation, error)
+	ReplaceTranslations(sentenceId uint64, translations []model.SentenceTranslation) ([]model.SentenceTranslation, error)
 }
 
 type SentenceRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSentenceRepository(db *sql.DB) ISentenceRepository {
+func NewSentenceRepository(db DBTX) ISentenceRepository {
 	return &SentenceRepository{db}
 }
 
@@ -33,11 +39,41 @@ func (sr *SentenceRepository) getSequenceNextvalQuery() string {
 	return fmt.Sprintf("nextval('%s')", sr.getSequenceName())
 }
 
+const sentenceColumns = `id, sentence, source_title, source_url, source_episode, source_timestamp_seconds, notes, user_id, created_at, updated_at`
+
+func scanSentence(row interface{ Scan(...any) error }, dest ...any) (model.Sentence, error) {
+	// destには、sentenceColumnsに続けて取得する列の格納先を指定する
+	sentence := model.Sentence{}
+	var timestampSeconds sql.NullInt32
+	err := row.Scan(append([]any{
+		&sentence.Id,
+		&sentence.Sentence,
+		&sentence.Source.Title,
+		&sentence.Source.Url,
+		&sentence.Source.Episode,
+		&timestampSeconds,
+		&sentence.Notes,
+		&sentence.UserId,
+		&sentence.CreatedAt,
+		&sentence.UpdatedAt,
+	}, dest...)...)
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	if timestampSeconds.Valid {
+		seconds := int(timestampSeconds.Int32)
+		sentence.Source.TimestampSeconds = &seconds
+	}
+
+	return sentence, nil
+}
+
 func (sr *SentenceRepository) GetAllSentences(userId uint64) ([]model.Sentence, error) {
 	var sentences []model.Sentence
 
 	rows, err := sr.db.Query(`
-		SELECT id, sentence, user_id, created_at, updated_at FROM sentences
+		SELECT `+sentenceColumns+` FROM sentences
 		WHERE user_id = $1;
 		`,
 		userId,
@@ -48,8 +84,7 @@ func (sr *SentenceRepository) GetAllSentences(userId uint64) ([]model.Sentence,
 	defer rows.Close()
 
 	for rows.Next() {
-		sentence := model.Sentence{}
-		err:= rows.Scan(&sentence.Id, &sentence.Sentence, &sentence.UserId, &sentence.CreatedAt, &sentence.UpdatedAt);
+		sentence, err := scanSentence(rows)
 		if err != nil {
 			return []model.Sentence{}, err
 		}
@@ -59,28 +94,24 @@ func (sr *SentenceRepository) GetAllSentences(userId uint64) ([]model.Sentence,
 	return sentences, nil
 }
 
-func (sr *SentenceRepository) GetAllSentencesWithLimit(userId, limit, offset uint64) ([]model.Sentence, error) {
+func (sr *SentenceRepository) GetAllSentencesWithLimit(userId uint64, filter model.ListFilter, limit, offset uint64) ([]model.Sentence, error) {
+	// filterのタグ、デッキ、出典で絞り込む
 	var sentences []model.Sentence
 
-	rows, err := sr.db.Query(`
-		SELECT id, sentence, user_id, created_at, updated_at FROM sentences
-		WHERE user_id = $1
-		ORDER BY updated_at DESC
-		LIMIT $2
-		OFFSET $3;
-		`,
-		userId,
-		limit,
-		offset,
-	)
+	filterCondition, args := listFilterCondition("sentence", filter, []any{userId})
+	query := "SELECT " + sentenceColumns + " FROM sentences" +
+		" WHERE user_id = $1" + filterCondition +
+		fmt.Sprintf(" ORDER BY updated_at DESC LIMIT $%d OFFSET $%d;", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := sr.db.Query(query, args...)
 	if err != nil {
 		return []model.Sentence{}, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		sentence := model.Sentence{}
-		err:= rows.Scan(&sentence.Id, &sentence.Sentence, &sentence.UserId, &sentence.CreatedAt, &sentence.UpdatedAt);
+		sentence, err := scanSentence(rows)
 		if err != nil {
 			return []model.Sentence{}, err
 		}
@@ -91,98 +122,71 @@ func (sr *SentenceRepository) GetAllSentencesWithLimit(userId, limit, offset uin
 }
 
 func (sr *SentenceRepository) GetSentenceById(userId, sentenceId uint64) (model.Sentence, error) {
-	sentence := model.Sentence{}
-
-	err := sr.db.QueryRow(`
-		SELECT id, sentence, user_id, created_at, updated_at
+	return scanSentence(sr.db.QueryRow(`
+		SELECT `+sentenceColumns+`
 		FROM sentences
 		WHERE id = $1
 			AND user_id = $2
 		`,
 		sentenceId,
 		userId,
-	).Scan(&sentence.Id, &sentence.Sentence, &sentence.UserId, &sentence.CreatedAt, &sentence.UpdatedAt)
-	if err != nil {
-		return model.Sentence{}, err
-	}
-
-	return sentence, nil
+	))
 }
 
 func (sr *SentenceRepository) InsertSentence(newSentence model.SentenceCreation) (model.Sentence, error) {
-	createdSentence := model.Sentence{}
-
-	err := sr.db.QueryRow(
-		"INSERT INTO sentences" +
-		" (id, sentence, user_id)" +
-		" VALUES(" + sr.getSequenceNextvalQuery() + ", $1, $2)" +
-		" RETURNING id, sentence, user_id, created_at, updated_at;",
+	// 訳文はReplaceTranslations() で保存する
+	return scanSentence(sr.db.QueryRow(
+		"INSERT INTO sentences"+
+			" (id, sentence, source_title, source_url, source_episode, source_timestamp_seconds, notes, user_id)"+
+			" VALUES("+sr.getSequenceNextvalQuery()+", $1, $2, $3, $4, $5, $6, $7)"+
+			" RETURNING "+sentenceColumns+";",
 		newSentence.Sentence,
+		newSentence.Source.Title,
+		newSentence.Source.Url,
+		newSentence.Source.Episode,
+		newSentence.Source.TimestampSeconds,
+		newSentence.Notes,
 		newSentence.LoginUserId,
-	).Scan(
-		&createdSentence.Id,
-		&createdSentence.Sentence,
-		&createdSentence.UserId,
-		&createdSentence.CreatedAt,
-		&createdSentence.UpdatedAt,
-	)
-	if err != nil {
-		return model.Sentence{}, err
-	}
-
-	return createdSentence, nil
+	))
 }
 
 func (sr *SentenceRepository) UpdateSentence(sentenceUpdate model.SentenceUpdate) (model.Sentence, error) {
-	updatedSentence := model.Sentence{}
-
-	err := sr.db.QueryRow(`
+	// 訳文はReplaceTranslations() で更新する
+	return scanSentence(sr.db.QueryRow(`
 		UPDATE sentences
-		SET sentence = $1
-		WHERE user_id = $2
-			AND id = $3
-		RETURNING id, sentence, user_id, created_at, updated_at;
+		SET
+			sentence = $1,
+			source_title = $2,
+			source_url = $3,
+			source_episode = $4,
+			source_timestamp_seconds = $5,
+			notes = $6
+		WHERE user_id = $7
+			AND id = $8
+		RETURNING `+sentenceColumns+`;
 		`,
 		sentenceUpdate.Sentence,
+		sentenceUpdate.Source.Title,
+		sentenceUpdate.Source.Url,
+		sentenceUpdate.Source.Episode,
+		sentenceUpdate.Source.TimestampSeconds,
+		sentenceUpdate.Notes,
 		sentenceUpdate.LoginUserId,
 		sentenceUpdate.Id,
-	).Scan(
-		&updatedSentence.Id,
-		&updatedSentence.Sentence,
-		&updatedSentence.UserId,
-		&updatedSentence.CreatedAt,
-		&updatedSentence.UpdatedAt,
-	)
-	if err != nil {
-		return model.Sentence{}, err
-	}
-
-	return updatedSentence, nil
+	))
 }
 
 func (sr *SentenceRepository) DeleteSentenceById(userId, sentenceId uint64) (model.Sentence, error) {
-	deletedSentence := model.Sentence{}
-
-	err := sr.db.QueryRow(`
+	// sentence_translationsのレコードは外部キーにより削除される
+	return scanSentence(sr.db.QueryRow(`
 		DELETE FROM sentences
 		WHERE user_id = $1
 			AND id = $2
-		RETURNING id, sentence, user_id, created_at, updated_at;
+		RETURNING `+sentenceColumns+`;
 		`,
 		userId,
 		sentenceId,
-	).Scan(
-		&deletedSentence.Id,
-		&deletedSentence.Sentence,
-		&deletedSentence.UserId,
-		&deletedSentence.CreatedAt,
-		&deletedSentence.UpdatedAt,
-	)
-	if err != nil {
-		return model.Sentence{}, err
-	}
-
-	return deletedSentence, nil
+	))
 }
 
 func (sr *SentenceRepository) IsSentenceOwner(sentenceId uint64, userId uint64) (bool, error) {
@@ -202,19 +206,166 @@ func (sr *SentenceRepository) IsSentenceOwner(sentenceId uint64, userId uint64)
 	return count == 1, nil
 }
 
-func (sr *SentenceRepository) GetSentencesCount(userId uint64) (uint64, error) {
+func (sr *SentenceRepository) GetSentencesCount(userId uint64, filter model.ListFilter) (uint64, error) {
+	var count uint64
+
+	filterCondition, args := listFilterCondition("sentence", filter, []any{userId})
+	err := sr.db.QueryRow(
+		"SELECT COUNT(*) FROM sentences"+
+			" WHERE user_id = $1"+filterCondition,
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Sentenceがqueryを含むか、queryとの類似度が高いSentenceを検索する条件
+// $1: userId, $2: query, $3: queryを含む文字列にマッチするLIKEのパターン
+const searchSentencesCondition = `
+	user_id = $1
+	AND (
+		sentence ILIKE $3
+		OR $2 <% sentence
+	)
+`
+
+func (sr *SentenceRepository) SearchSentences(userId uint64, query string, limit, offset uint64) ([]model.SentenceSearchHit, error) {
+	// queryに一致するSentenceを、スコアの高い順に取得
+	// 部分一致したものを優先し、同じ条件の中ではqueryとの類似度が高いものを優先する
+	rows, err := sr.db.Query(`
+		SELECT ` + sentenceColumns + `, score FROM (
+			SELECT
+				sentences.*,
+				word_similarity($2, sentence)
+				+ CASE WHEN sentence ILIKE $3 THEN 1 ELSE 0 END
+				AS score
+			FROM sentences
+			WHERE ` + searchSentencesCondition + `
+		) AS hits
+		ORDER BY score DESC, id ASC
+		LIMIT $4
+		OFFSET $5;
+		`,
+		userId,
+		query,
+		toContainsPattern(query),
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []model.SentenceSearchHit{}, err
+	}
+	defer rows.Close()
+
+	var sentenceSearchHits []model.SentenceSearchHit
+	for rows.Next() {
+		sentenceSearchHit := model.SentenceSearchHit{}
+		sentenceSearchHit.Sentence, err = scanSentence(rows, &sentenceSearchHit.Score)
+		if err != nil {
+			return []model.SentenceSearchHit{}, err
+		}
+		sentenceSearchHits = append(sentenceSearchHits, sentenceSearchHit)
+	}
+
+	return sentenceSearchHits, nil
+}
+
+func (sr *SentenceRepository) GetSearchSentencesCount(userId uint64, query string) (uint64, error) {
 	var count uint64
 
 	err := sr.db.QueryRow(`
-		SELECT COUNT(*)
-		FROM sentences
-		WHERE user_id = $1
+		SELECT COUNT(*) FROM sentences
+		WHERE ` + searchSentencesCondition + `;
 		`,
 		userId,
+		query,
+		toContainsPattern(query),
 	).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+func (sr *SentenceRepository) GetTranslationsBySentenceIds(sentenceIds []uint64) ([]model.SentenceTranslation, error) {
+	// sentenceIdsの各Sentenceの訳文を、1回のクエリでまとめて取得
+	// Sentenceごとに、言語順に返す
+	if len(sentenceIds) == 0 {
+		return []model.SentenceTranslation{}, nil
+	}
+
+	ids := make([]int64, len(sentenceIds))
+	for i, sentenceId := range sentenceIds {
+		ids[i] = int64(sentenceId)
+	}
+
+	rows, err := sr.db.Query(`
+		SELECT sentence_id, language, translation
+		FROM sentence_translations
+		WHERE sentence_id = ANY($1::INTEGER[])
+		ORDER BY sentence_id, language;
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return []model.SentenceTranslation{}, err
+	}
+	defer rows.Close()
+
+	translations := []model.SentenceTranslation{}
+	for rows.Next() {
+		translation := model.SentenceTranslation{}
+		err := rows.Scan(&translation.SentenceId, &translation.Language, &translation.Translation)
+		if err != nil {
+			return []model.SentenceTranslation{}, err
+		}
+		translations = append(translations, translation)
+	}
+
+	return translations, nil
+}
+
+func (sr *SentenceRepository) ReplaceTranslations(sentenceId uint64, translations []model.SentenceTranslation) ([]model.SentenceTranslation, error) {
+	// sentenceIdのSentenceの訳文を、translationsで置き換える
+	// sentenceIdのSentenceの所有者の検証が済んでいることを前提とする
+	_, err := sr.db.Exec(`
+		DELETE FROM sentence_translations
+		WHERE sentence_id = $1;
+		`,
+		sentenceId,
+	)
+	if err != nil {
+		return []model.SentenceTranslation{}, err
+	}
+
+	if len(translations) == 0 {
+		return []model.SentenceTranslation{}, nil
+	}
+
+	languages := make([]string, len(translations))
+	texts := make([]string, len(translations))
+	for i, translation := range translations {
+		languages[i] = translation.Language
+		texts[i] = translation.Translation
+	}
+
+	_, err = sr.db.Exec(`
+		INSERT INTO sentence_translations
+		(sentence_id, language, translation)
+		SELECT $1, t.language, t.translation
+		FROM UNNEST($2::VARCHAR[], $3::VARCHAR[]) AS t(language, translation);
+		`,
+		sentenceId,
+		pq.Array(languages),
+		pq.Array(texts),
+	)
+	if err != nil {
+		return []model.SentenceTranslation{}, err
+	}
+
+	return sr.GetTranslationsBySentenceIds([]uint64{sentenceId})
+}
@@ -2,22 +2,32 @@ package repository
 
 import (
 	"api/model"
-	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 type ISentencesWordsRepository interface {
 	AssociateSentenceWithWord(sentenceId uint64, wordId uint64) error
+	AssociateSentencesWithWords(sentencesWords []model.SentenceWord) error
+	InsertOccurrences(occurrences []model.SentenceWordOccurrence) error
+	GetOccurrencesBySentenceIds(sentenceIds []uint64) ([]model.SentenceWordOccurrence, error)
+	UpsertOverride(overrideUpsert model.AssociationOverrideUpsert) (model.AssociationOverride, error)
+	DeleteOverride(sentenceId, wordId uint64) (model.AssociationOverride, error)
+	GetOverridesBySentenceIds(sentenceIds []uint64) ([]model.AssociationOverride, error)
+	MoveOverrides(fromWordId, toWordId uint64) error
 	GetUserAssociatedSentencesByWordId(wordId uint64) ([]model.Sentence, error)
+	GetUserAssociatedSentencesByWordIds(wordIds []uint64) ([]model.WordAssociatedSentence, error)
 	GetUserAssociatedWordsBySentenceId(sentenceId uint64) ([]model.Word, error)
 	DeleteAllAssociationBySentenceId(sentenceId uint64) error
+	DeleteAllAssociationBySentenceIds(sentenceIds []uint64) error
 	DeleteAllAssociationByWordId(sentenceId uint64) error
 }
 
 type SentencesWordsRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSentencesWordsRepository(db *sql.DB) ISentencesWordsRepository {
+func NewSentencesWordsRepository(db DBTX) ISentencesWordsRepository {
 	return &SentencesWordsRepository{db}
 }
 
@@ -47,6 +57,253 @@ func (swr *SentencesWordsRepository) AssociateSentenceWithWord(sentenceId uint64
 	return nil
 }
 
+func (swr *SentencesWordsRepository) AssociateSentencesWithWords(sentencesWords []model.SentenceWord) error {
+	// sentencesWordsのうち、テーブルにレコードが存在しないものを1回のクエリでまとめて追加
+	// AssociateSentenceWithWord() と同じく、SentenceとWordの所有者は同じであることを前提とする
+
+	if len(sentencesWords) == 0 {
+		return nil
+	}
+
+	sentenceIds := make([]int64, len(sentencesWords))
+	wordIds := make([]int64, len(sentencesWords))
+	for i, sentenceWord := range sentencesWords {
+		sentenceIds[i] = int64(sentenceWord.SentenceId)
+		wordIds[i] = int64(sentenceWord.WordId)
+	}
+
+	_, err := swr.db.Exec(`
+		INSERT INTO sentences_words
+		(sentence_id, word_id)
+		SELECT * FROM unnest($1::INTEGER[], $2::INTEGER[])
+		ON CONFLICT (sentence_id, word_id) DO NOTHING;
+		`,
+		pq.Array(sentenceIds),
+		pq.Array(wordIds),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (swr *SentencesWordsRepository) InsertOccurrences(occurrences []model.SentenceWordOccurrence) error {
+	// occurrencesを1回のクエリでまとめて追加
+	// 出現箇所の紐づけがsentences_wordsに追加済みであることを前提とする
+
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	sentenceIds := make([]int64, len(occurrences))
+	wordIds := make([]int64, len(occurrences))
+	notationIds := make([]int64, len(occurrences))
+	startOffsets := make([]int64, len(occurrences))
+	endOffsets := make([]int64, len(occurrences))
+	discardReasons := make([]string, len(occurrences))
+	for i, occurrence := range occurrences {
+		sentenceIds[i] = int64(occurrence.SentenceId)
+		wordIds[i] = int64(occurrence.WordId)
+		notationIds[i] = int64(occurrence.NotationId)
+		startOffsets[i] = int64(occurrence.Start)
+		endOffsets[i] = int64(occurrence.End)
+		discardReasons[i] = occurrence.DiscardReason
+	}
+
+	// Word自体に一致した場合のnotation_idは、0ではなくNULLとする
+	_, err := swr.db.Exec(`
+		INSERT INTO sentence_word_occurrences
+		(sentence_id, word_id, notation_id, start_offset, end_offset, discard_reason)
+		SELECT sentence_id, word_id, NULLIF(notation_id, 0), start_offset, end_offset, discard_reason
+		FROM unnest($1::INTEGER[], $2::INTEGER[], $3::INTEGER[], $4::INTEGER[], $5::INTEGER[], $6::TEXT[])
+			AS t(sentence_id, word_id, notation_id, start_offset, end_offset, discard_reason)
+		ON CONFLICT (sentence_id, word_id, start_offset, end_offset) DO NOTHING;
+		`,
+		pq.Array(sentenceIds),
+		pq.Array(wordIds),
+		pq.Array(notationIds),
+		pq.Array(startOffsets),
+		pq.Array(endOffsets),
+		pq.Array(discardReasons),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (swr *SentencesWordsRepository) GetOccurrencesBySentenceIds(sentenceIds []uint64) ([]model.SentenceWordOccurrence, error) {
+	// sentenceIdsの各SentenceにおけるWordの出現箇所を、1回のクエリでまとめて取得
+	// Sentenceごとに、出現箇所の前から順に返す
+	if len(sentenceIds) == 0 {
+		return []model.SentenceWordOccurrence{}, nil
+	}
+
+	ids := make([]int64, len(sentenceIds))
+	for i, sentenceId := range sentenceIds {
+		ids[i] = int64(sentenceId)
+	}
+
+	rows, err := swr.db.Query(`
+		SELECT
+			sentence_id,
+			word_id,
+			COALESCE(notation_id, 0),
+			start_offset,
+			end_offset,
+			discard_reason
+		FROM sentence_word_occurrences
+		WHERE sentence_id = ANY($1::INTEGER[])
+		ORDER BY sentence_id, start_offset, end_offset DESC, word_id;
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return []model.SentenceWordOccurrence{}, err
+	}
+	defer rows.Close()
+
+	occurrences := []model.SentenceWordOccurrence{}
+	for rows.Next() {
+		occurrence := model.SentenceWordOccurrence{}
+		err := rows.Scan(
+			&occurrence.SentenceId,
+			&occurrence.WordId,
+			&occurrence.NotationId,
+			&occurrence.Start,
+			&occurrence.End,
+			&occurrence.DiscardReason,
+		)
+		if err != nil {
+			return []model.SentenceWordOccurrence{}, err
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences, nil
+}
+
+func (swr *SentencesWordsRepository) UpsertOverride(overrideUpsert model.AssociationOverrideUpsert) (model.AssociationOverride, error) {
+	// SentenceとWordの組に対する手動の指定を追加し、既に指定がある場合は種類を更新する
+	// SentenceとWordの所有者は同じであることを前提とする
+	override := model.AssociationOverride{}
+
+	err := swr.db.QueryRow(`
+		INSERT INTO sentence_word_overrides
+		(sentence_id, word_id, kind)
+		VALUES($1, $2, $3)
+		ON CONFLICT (sentence_id, word_id) DO UPDATE
+		SET kind = EXCLUDED.kind
+		RETURNING sentence_id, word_id, kind, created_at, updated_at;
+		`,
+		overrideUpsert.SentenceId,
+		overrideUpsert.WordId,
+		overrideUpsert.Kind,
+	).Scan(
+		&override.SentenceId,
+		&override.WordId,
+		&override.Kind,
+		&override.CreatedAt,
+		&override.UpdatedAt,
+	)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
+}
+
+func (swr *SentencesWordsRepository) DeleteOverride(sentenceId, wordId uint64) (model.AssociationOverride, error) {
+	// 指定が無い場合はsql.ErrNoRowsを返す
+	override := model.AssociationOverride{}
+
+	err := swr.db.QueryRow(`
+		DELETE FROM sentence_word_overrides
+		WHERE sentence_id = $1 AND word_id = $2
+		RETURNING sentence_id, word_id, kind, created_at, updated_at;
+		`,
+		sentenceId,
+		wordId,
+	).Scan(
+		&override.SentenceId,
+		&override.WordId,
+		&override.Kind,
+		&override.CreatedAt,
+		&override.UpdatedAt,
+	)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
+}
+
+func (swr *SentencesWordsRepository) MoveOverrides(fromWordId, toWordId uint64) error {
+	// fromWordIdのWordに対する手動の指定を、toWordIdのWordに移す
+	// 同じSentenceに対してtoWordIdのWordの指定が既にある場合は、そちらを優先して移さない
+	_, err := swr.db.Exec(`
+		UPDATE sentence_word_overrides o
+		SET word_id = $2
+		WHERE o.word_id = $1
+			AND NOT EXISTS(
+				SELECT 1
+				FROM sentence_word_overrides k
+				WHERE k.word_id = $2
+					AND k.sentence_id = o.sentence_id
+			);
+		`,
+		fromWordId,
+		toWordId,
+	)
+
+	return err
+}
+
+func (swr *SentencesWordsRepository) GetOverridesBySentenceIds(sentenceIds []uint64) ([]model.AssociationOverride, error) {
+	// sentenceIdsの各Sentenceに対する手動の指定を、1回のクエリでまとめて取得
+	if len(sentenceIds) == 0 {
+		return []model.AssociationOverride{}, nil
+	}
+
+	ids := make([]int64, len(sentenceIds))
+	for i, sentenceId := range sentenceIds {
+		ids[i] = int64(sentenceId)
+	}
+
+	rows, err := swr.db.Query(`
+		SELECT sentence_id, word_id, kind, created_at, updated_at
+		FROM sentence_word_overrides
+		WHERE sentence_id = ANY($1::INTEGER[])
+		ORDER BY sentence_id, word_id;
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return []model.AssociationOverride{}, err
+	}
+	defer rows.Close()
+
+	overrides := []model.AssociationOverride{}
+	for rows.Next() {
+		override := model.AssociationOverride{}
+		err := rows.Scan(
+			&override.SentenceId,
+			&override.WordId,
+			&override.Kind,
+			&override.CreatedAt,
+			&override.UpdatedAt,
+		)
+		if err != nil {
+			return []model.AssociationOverride{}, err
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
 func (swr *SentencesWordsRepository) GetUserAssociatedSentencesByWordId(wordId uint64) ([]model.Sentence, error) {
 	// wordIdに紐づくSentenceを全件取得
 	// sentenceIdのSentenceとwordIdのWordのuserIdは一致する（SentenceとWordの所有者は同じである）ことを前提とするため、
@@ -55,18 +312,13 @@ func (swr *SentencesWordsRepository) GetUserAssociatedSentencesByWordId(wordId u
 	var sentences []model.Sentence
 
 	rows, err := swr.db.Query(`
-		SELECT
-			sentences.id,
-			sentences.sentence,
-			sentences.user_id,
-			sentences.created_at,
-			sentences.updated_at
-		FROM sentences_words
-		LEFT JOIN words
-			ON sentences_words.word_id = words.id
-		LEFT JOIN sentences
-			ON sentences_words.sentence_id = sentences.id
-		WHERE sentences_words.word_id = $1;
+		SELECT `+sentenceColumns+`
+		FROM sentences
+		WHERE id IN (
+			SELECT sentence_id
+			FROM sentences_words
+			WHERE word_id = $1
+		);
 		`,
 		wordId,
 	)
@@ -76,8 +328,7 @@ func (swr *SentencesWordsRepository) GetUserAssociatedSentencesByWordId(wordId u
 	defer rows.Close()
 
 	for rows.Next() {
-		sentence := model.Sentence{}
-		err := rows.Scan(&sentence.Id, &sentence.Sentence, &sentence.UserId, &sentence.CreatedAt, &sentence.UpdatedAt)
+		sentence, err := scanSentence(rows)
 		if err != nil {
 			return []model.Sentence{}, err
 		}
@@ -87,6 +338,52 @@ func (swr *SentencesWordsRepository) GetUserAssociatedSentencesByWordId(wordId u
 	return sentences, nil
 }
 
+func (swr *SentencesWordsRepository) GetUserAssociatedSentencesByWordIds(wordIds []uint64) ([]model.WordAssociatedSentence, error) {
+	// wordIdsの各Wordに紐づくSentenceを、1回のクエリでまとめて取得
+	// Wordごとに、SentenceのId順に返す
+	// GetUserAssociatedSentencesByWordIdと同じく、参照時にはuserIdの検証を行わない
+	if len(wordIds) == 0 {
+		return []model.WordAssociatedSentence{}, nil
+	}
+
+	ids := make([]int64, len(wordIds))
+	for i, wordId := range wordIds {
+		ids[i] = int64(wordId)
+	}
+
+	rows, err := swr.db.Query(`
+		SELECT `+sentenceColumns+`, word_id
+		FROM (
+			SELECT s.*, sw.word_id
+			FROM sentences s
+			JOIN sentences_words sw ON sw.sentence_id = s.id
+			WHERE sw.word_id = ANY($1::INTEGER[])
+		) AS associated
+		ORDER BY word_id, id;
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return []model.WordAssociatedSentence{}, err
+	}
+	defer rows.Close()
+
+	associatedSentences := []model.WordAssociatedSentence{}
+	for rows.Next() {
+		var wordId uint64
+		sentence, err := scanSentence(rows, &wordId)
+		if err != nil {
+			return []model.WordAssociatedSentence{}, err
+		}
+		associatedSentences = append(associatedSentences, model.WordAssociatedSentence{
+			WordId:   wordId,
+			Sentence: sentence,
+		})
+	}
+
+	return associatedSentences, nil
+}
+
 func (swr *SentencesWordsRepository) GetUserAssociatedWordsBySentenceId(sentenceId uint64) ([]model.Word, error) {
 	// sentenceIdに紐づくWordのを全件取得
 	// sentenceIdのSentenceとwordIdのWordのuserIdは一致する（SentenceとWordの所有者は同じである）ことを前提とするため、
@@ -95,19 +392,14 @@ func (swr *SentencesWordsRepository) GetUserAssociatedWordsBySentenceId(sentence
 	var words []model.Word
 
 	rows, err := swr.db.Query(`
-		SELECT
-			words.id,
-			words.word,
-			words.memo,
-			words.user_id,
-			words.created_at,
-			words.updated_at
-		FROM sentences_words
-		LEFT JOIN words
-			ON sentences_words.word_id = words.id
-		LEFT JOIN sentences
-			ON sentences_words.sentence_id = sentences.id
-		WHERE sentences_words.sentence_id = $1
+		SELECT `+wordColumns+`
+		FROM words
+		WHERE id IN (
+			SELECT word_id
+			FROM sentences_words
+			WHERE sentence_id = $1
+		)
+		ORDER BY id;
 		`,
 		sentenceId,
 	)
@@ -117,8 +409,7 @@ func (swr *SentencesWordsRepository) GetUserAssociatedWordsBySentenceId(sentence
 	defer rows.Close()
 
 	for rows.Next() {
-		word := model.Word{}
-		err := rows.Scan(&word.Id, &word.Word, &word.Memo, &word.UserId, &word.CreatedAt, &word.UpdatedAt)
+		word, err := scanWord(rows)
 		if err != nil {
 			return []model.Word{}, err
 		}
@@ -142,6 +433,30 @@ func (swr *SentencesWordsRepository) DeleteAllAssociationBySentenceId(sentenceId
 	return nil
 }
 
+func (swr *SentencesWordsRepository) DeleteAllAssociationBySentenceIds(sentenceIds []uint64) error {
+	// sentenceIdsの各Sentenceの紐づけを、1回のクエリでまとめて削除
+	if len(sentenceIds) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(sentenceIds))
+	for i, sentenceId := range sentenceIds {
+		ids[i] = int64(sentenceId)
+	}
+
+	_, err := swr.db.Exec(`
+		DELETE FROM sentences_words
+		WHERE sentence_id = ANY($1::INTEGER[]);
+		`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (swr *SentencesWordsRepository) DeleteAllAssociationByWordId(wordId uint64) error {
 	_, err := swr.db.Exec(`
 		DELETE FROM sentences_words
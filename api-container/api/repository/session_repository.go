@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+)
+
+type ISessionRepository interface {
+	GetValidSessionById(sessionId string) (model.Session, error)
+	InsertSession(sessionCreation model.SessionCreation) (model.Session, error)
+	DeleteSessionById(sessionId string) error
+	DeleteExpiredSessions() error
+}
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) ISessionRepository {
+	return &SessionRepository{db}
+}
+
+func (ssr *SessionRepository) GetValidSessionById(sessionId string) (model.Session, error) {
+	// 有効期限切れのSessionは取得しない
+
+	session := model.Session{}
+
+	err := ssr.db.QueryRow(`
+		SELECT id, user_id, expires_at, created_at, updated_at
+		FROM sessions
+		WHERE id = $1
+			AND expires_at > CURRENT_TIMESTAMP;
+		`,
+		sessionId,
+	).Scan(
+		&session.Id,
+		&session.UserId,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (ssr *SessionRepository) InsertSession(sessionCreation model.SessionCreation) (model.Session, error) {
+	createdSession := model.Session{}
+
+	err := ssr.db.QueryRow(`
+		INSERT INTO sessions
+		(id, user_id, expires_at)
+		VALUES($1, $2, $3)
+		RETURNING id, user_id, expires_at, created_at, updated_at;
+		`,
+		sessionCreation.Id,
+		sessionCreation.UserId,
+		sessionCreation.ExpiresAt,
+	).Scan(
+		&createdSession.Id,
+		&createdSession.UserId,
+		&createdSession.ExpiresAt,
+		&createdSession.CreatedAt,
+		&createdSession.UpdatedAt,
+	)
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	return createdSession, nil
+}
+
+func (ssr *SessionRepository) DeleteSessionById(sessionId string) error {
+	_, err := ssr.db.Exec(`
+		DELETE FROM sessions
+		WHERE id = $1;
+		`,
+		sessionId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ssr *SessionRepository) DeleteExpiredSessions() error {
+	_, err := ssr.db.Exec(`
+		DELETE FROM sessions
+		WHERE expires_at <= CURRENT_TIMESTAMP;
+		`,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"api/model"
+	"fmt"
+)
+
+type ITagRepository interface {
+	GetAllTags(userId uint64) ([]model.Tag, error)
+	GetTagById(userId, tagId uint64) (model.Tag, error)
+	GetTagByName(userId uint64, name string) (model.Tag, error)
+	InsertTag(tagCreation model.TagCreation) (model.Tag, error)
+	UpdateTag(tagUpdate model.TagUpdate) (model.Tag, error)
+	DeleteTagById(userId, tagId uint64) (model.Tag, error)
+	GetTagsByWordId(wordId uint64) ([]model.Tag, error)
+	GetTagsBySentenceId(sentenceId uint64) ([]model.Tag, error)
+	AddTagToWord(wordId, tagId uint64) error
+	RemoveTagFromWord(wordId, tagId uint64) error
+	MoveTags(fromWordId, toWordId uint64) error
+	AddTagToSentence(sentenceId, tagId uint64) error
+	RemoveTagFromSentence(sentenceId, tagId uint64) error
+}
+
+type TagRepository struct {
+	db DBTX
+}
+
+func NewTagRepository(db DBTX) ITagRepository {
+	return &TagRepository{db}
+}
+
+func (tr *TagRepository) getSequenceName() string {
+	return "tag_id_seq"
+}
+
+func (tr *TagRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", tr.getSequenceName())
+}
+
+const tagColumns = `id, name, user_id, created_at, updated_at`
+
+func scanTag(row interface{ Scan(...any) error }) (model.Tag, error) {
+	tag := model.Tag{}
+	err := row.Scan(
+		&tag.Id,
+		&tag.Name,
+		&tag.UserId,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (tr *TagRepository) queryTags(query string, args ...any) ([]model.Tag, error) {
+	rows, err := tr.db.Query(query, args...)
+	if err != nil {
+		return []model.Tag{}, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return []model.Tag{}, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (tr *TagRepository) GetAllTags(userId uint64) ([]model.Tag, error) {
+	return tr.queryTags(`
+		SELECT `+tagColumns+` FROM tags
+		WHERE user_id = $1
+		ORDER BY name, id;
+		`,
+		userId,
+	)
+}
+
+func (tr *TagRepository) GetTagById(userId, tagId uint64) (model.Tag, error) {
+	// tagIdの所有者がuserIdでない場合はsql.ErrNoRowsを返す
+	return scanTag(tr.db.QueryRow(`
+		SELECT `+tagColumns+` FROM tags
+		WHERE user_id = $1
+			AND id = $2;
+		`,
+		userId,
+		tagId,
+	))
+}
+
+func (tr *TagRepository) GetTagByName(userId uint64, name string) (model.Tag, error) {
+	// 存在しない場合はsql.ErrNoRowsを返す
+	return scanTag(tr.db.QueryRow(`
+		SELECT `+tagColumns+` FROM tags
+		WHERE user_id = $1
+			AND name = $2;
+		`,
+		userId,
+		name,
+	))
+}
+
+func (tr *TagRepository) InsertTag(tagCreation model.TagCreation) (model.Tag, error) {
+	// 同じUserに同じ名前のタグが既に存在する場合は追加せず、sql.ErrNoRowsを返す
+	return scanTag(tr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO tags
+		(id, name, user_id)
+		VALUES(%s, $1, $2)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING `+tagColumns+`;
+		`,
+		tr.getSequenceNextvalQuery(),
+	),
+		tagCreation.Name,
+		tagCreation.LoginUserId,
+	))
+}
+
+func (tr *TagRepository) UpdateTag(tagUpdate model.TagUpdate) (model.Tag, error) {
+	return scanTag(tr.db.QueryRow(`
+		UPDATE tags
+		SET name = $1
+		WHERE user_id = $2
+			AND id = $3
+		RETURNING `+tagColumns+`;
+		`,
+		tagUpdate.Name,
+		tagUpdate.LoginUserId,
+		tagUpdate.Id,
+	))
+}
+
+func (tr *TagRepository) DeleteTagById(userId, tagId uint64) (model.Tag, error) {
+	// words_tags、sentences_tagsのレコードは外部キーにより削除される
+	return scanTag(tr.db.QueryRow(`
+		DELETE FROM tags
+		WHERE user_id = $1
+			AND id = $2
+		RETURNING `+tagColumns+`;
+		`,
+		userId,
+		tagId,
+	))
+}
+
+func (tr *TagRepository) GetTagsByWordId(wordId uint64) ([]model.Tag, error) {
+	return tr.queryTags(`
+		SELECT t.id, t.name, t.user_id, t.created_at, t.updated_at
+		FROM tags t
+		JOIN words_tags wt ON wt.tag_id = t.id
+		WHERE wt.word_id = $1
+		ORDER BY t.name, t.id;
+		`,
+		wordId,
+	)
+}
+
+func (tr *TagRepository) GetTagsBySentenceId(sentenceId uint64) ([]model.Tag, error) {
+	return tr.queryTags(`
+		SELECT t.id, t.name, t.user_id, t.created_at, t.updated_at
+		FROM tags t
+		JOIN sentences_tags st ON st.tag_id = t.id
+		WHERE st.sentence_id = $1
+		ORDER BY t.name, t.id;
+		`,
+		sentenceId,
+	)
+}
+
+// 以下のタグの付け外しは、WordまたはSentenceとタグの所有者が同じであることを前提とする
+// 既に付いているタグの追加、付いていないタグの削除はエラーとしない
+
+func (tr *TagRepository) AddTagToWord(wordId, tagId uint64) error {
+	_, err := tr.db.Exec(`
+		INSERT INTO words_tags
+		(word_id, tag_id)
+		VALUES($1, $2)
+		ON CONFLICT (word_id, tag_id) DO NOTHING;
+		`,
+		wordId,
+		tagId,
+	)
+
+	return err
+}
+
+func (tr *TagRepository) RemoveTagFromWord(wordId, tagId uint64) error {
+	_, err := tr.db.Exec(`
+		DELETE FROM words_tags
+		WHERE word_id = $1
+			AND tag_id = $2;
+		`,
+		wordId,
+		tagId,
+	)
+
+	return err
+}
+
+func (tr *TagRepository) MoveTags(fromWordId, toWordId uint64) error {
+	// fromWordIdのWordに付けられたTagを、toWordIdのWordに付け替える
+	// toWordIdのWordに既に付けられているTagは移さない
+	_, err := tr.db.Exec(`
+		UPDATE words_tags t
+		SET word_id = $2
+		WHERE t.word_id = $1
+			AND NOT EXISTS(
+				SELECT 1
+				FROM words_tags k
+				WHERE k.word_id = $2
+					AND k.tag_id = t.tag_id
+			);
+		`,
+		fromWordId,
+		toWordId,
+	)
+
+	return err
+}
+
+func (tr *TagRepository) AddTagToSentence(sentenceId, tagId uint64) error {
+	_, err := tr.db.Exec(`
+		INSERT INTO sentences_tags
+		(sentence_id, tag_id)
+		VALUES($1, $2)
+		ON CONFLICT (sentence_id, tag_id) DO NOTHING;
+		`,
+		sentenceId,
+		tagId,
+	)
+
+	return err
+}
+
+func (tr *TagRepository) RemoveTagFromSentence(sentenceId, tagId uint64) error {
+	_, err := tr.db.Exec(`
+		DELETE FROM sentences_tags
+		WHERE sentence_id = $1
+			AND tag_id = $2;
+		`,
+		sentenceId,
+		tagId,
+	)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+)
+
+// *sql.DBと*sql.Txのどちらでもクエリを実行できるようにするためのインターフェース
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// 1つのトランザクションで共有するRepositoryの組
+type Repositories struct {
+	Word           IWordRepository
+	Sentence       ISentenceRepository
+	SentencesWords ISentencesWordsRepository
+	Notation       INotationRepository
+	Job            IJobRepository
+	Tag            ITagRepository
+	Deck           IDeckRepository
+	WordSense      IWordSenseRepository
+	WordRelation   IWordRelationRepository
+	Review         IReviewRepository
+}
+
+func NewRepositories(db DBTX) Repositories {
+	return Repositories{
+		Word:           NewWordRepository(db),
+		Sentence:       NewSentenceRepository(db),
+		SentencesWords: NewSentencesWordsRepository(db),
+		Notation:       NewNotationRepository(db),
+		Job:            NewJobRepository(db),
+		Tag:            NewTagRepository(db),
+		Deck:           NewDeckRepository(db),
+		WordSense:      NewWordSenseRepository(db),
+		WordRelation:   NewWordRelationRepository(db),
+		Review:         NewReviewRepository(db),
+	}
+}
+
+type IUnitOfWork interface {
+	// fnに渡したRepositoriesによる操作を、1つのトランザクション内で実行する
+	// fnがエラーを返した場合はロールバックし、そのエラーを返す
+	Do(fn func(repos Repositories) error) error
+}
+
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) IUnitOfWork {
+	return &UnitOfWork{db}
+}
+
+func (uow *UnitOfWork) Do(fn func(repos Repositories) error) (err error) {
+	tx, err := uow.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// fn内でpanicが発生した場合もロールバックする
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(NewRepositories(tx))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type TransactionalUnitOfWork struct {
+	repos Repositories
+}
+
+func NewTransactionalUnitOfWork(repos Repositories) IUnitOfWork {
+	// 既に開始済みのトランザクションに参加するIUnitOfWorkを作成
+	// Doは新たなトランザクションを開始せず、reposをそのまま使ってfnを実行する
+	// コミット、ロールバックは外側のUnitOfWork.Doが行う
+	return &TransactionalUnitOfWork{repos}
+}
+
+func (tuow *TransactionalUnitOfWork) Do(fn func(repos Repositories) error) error {
+	return fn(tuow.repos)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"api/model"
+	"database/sql"
+	"fmt"
+)
+
+type IUserRepository interface {
+	GetUserById(userId uint64) (model.User, error)
+	GetUserByEmail(email string) (model.User, error)
+	InsertUser(userCreation model.UserCreation) (model.User, error)
+}
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) IUserRepository {
+	return &UserRepository{db}
+}
+
+func (ur *UserRepository) getSequenceName() string {
+	return "user_id_seq"
+}
+
+func (ur *UserRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", ur.getSequenceName())
+}
+
+func (ur *UserRepository) GetUserById(userId uint64) (model.User, error) {
+	user := model.User{}
+
+	err := ur.db.QueryRow(`
+		SELECT id, email, password, created_at, updated_at
+		FROM users
+		WHERE id = $1;
+		`,
+		userId,
+	).Scan(
+		&user.Id,
+		&user.Email,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func (ur *UserRepository) GetUserByEmail(email string) (model.User, error) {
+	user := model.User{}
+
+	err := ur.db.QueryRow(`
+		SELECT id, email, password, created_at, updated_at
+		FROM users
+		WHERE email = $1;
+		`,
+		email,
+	).Scan(
+		&user.Id,
+		&user.Email,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func (ur *UserRepository) InsertUser(userCreation model.UserCreation) (model.User, error) {
+	// emailが既に使われている場合は何も追加せず、sql.ErrNoRowsを返す
+
+	createdUser := model.User{}
+
+	err := ur.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO users
+		(id, email, password)
+		SELECT %s, CAST($1 AS VARCHAR), CAST($2 AS VARCHAR)
+		WHERE NOT EXISTS(
+			SELECT 1
+			FROM users
+			WHERE email = CAST($1 AS VARCHAR)
+		)
+		RETURNING id, email, password, created_at, updated_at;
+		`,
+		ur.getSequenceNextvalQuery(),
+		),
+		userCreation.Email,
+		userCreation.HashedPassword,
+	).Scan(
+		&createdUser.Id,
+		&createdUser.Email,
+		&createdUser.Password,
+		&createdUser.CreatedAt,
+		&createdUser.UpdatedAt,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return createdUser, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"api/model"
+	"fmt"
+)
+
+type IWordRelationRepository interface {
+	GetRelationsByWordId(wordId uint64) ([]model.WordRelation, error)
+	GetRelationById(wordId, relationId uint64) (model.WordRelation, error)
+	GetRelationBetween(wordId, relatedWordId uint64, kind string) (model.WordRelation, error)
+	InsertRelation(relationCreation model.WordRelationCreation) (model.WordRelation, error)
+	UpdateRelation(relationUpdate model.WordRelationUpdate) (model.WordRelation, error)
+	DeleteRelationById(wordId, relationId uint64) (model.WordRelation, error)
+	MoveRelations(fromWordId, toWordId uint64) error
+}
+
+type WordRelationRepository struct {
+	db DBTX
+}
+
+func NewWordRelationRepository(db DBTX) IWordRelationRepository {
+	return &WordRelationRepository{db}
+}
+
+func (wrr *WordRelationRepository) getSequenceName() string {
+	return "word_relation_id_seq"
+}
+
+func (wrr *WordRelationRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", wrr.getSequenceName())
+}
+
+const wordRelationColumns = `id, word_id, related_word_id, kind, bidirectional, created_at, updated_at`
+
+func scanWordRelation(row interface{ Scan(...any) error }) (model.WordRelation, error) {
+	relation := model.WordRelation{}
+	err := row.Scan(
+		&relation.Id,
+		&relation.WordId,
+		&relation.RelatedWordId,
+		&relation.Kind,
+		&relation.Bidirectional,
+		&relation.CreatedAt,
+		&relation.UpdatedAt,
+	)
+	if err != nil {
+		return model.WordRelation{}, err
+	}
+
+	return relation, nil
+}
+
+// 以下の関係の操作は、wordIdのWordの所有者の検証が済んでいることを前提とする
+// 関係は、wordIdのWordがword_id、related_word_idのどちらであっても対象とする
+
+func (wrr *WordRelationRepository) GetRelationsByWordId(wordId uint64) ([]model.WordRelation, error) {
+	rows, err := wrr.db.Query(`
+		SELECT `+wordRelationColumns+` FROM word_relations
+		WHERE word_id = $1
+			OR related_word_id = $1
+		ORDER BY id;
+		`,
+		wordId,
+	)
+	if err != nil {
+		return []model.WordRelation{}, err
+	}
+	defer rows.Close()
+
+	relations := []model.WordRelation{}
+	for rows.Next() {
+		relation, err := scanWordRelation(rows)
+		if err != nil {
+			return []model.WordRelation{}, err
+		}
+		relations = append(relations, relation)
+	}
+
+	return relations, nil
+}
+
+func (wrr *WordRelationRepository) GetRelationById(wordId, relationId uint64) (model.WordRelation, error) {
+	// relationIdがwordIdのWordの関係でない場合はsql.ErrNoRowsを返す
+	return scanWordRelation(wrr.db.QueryRow(`
+		SELECT `+wordRelationColumns+` FROM word_relations
+		WHERE id = $2
+			AND (word_id = $1 OR related_word_id = $1);
+		`,
+		wordId,
+		relationId,
+	))
+}
+
+func (wrr *WordRelationRepository) GetRelationBetween(wordId, relatedWordId uint64, kind string) (model.WordRelation, error) {
+	// 2つのWordの間のkindの関係を、向きによらず取得
+	// 存在しない場合はsql.ErrNoRowsを返す
+	return scanWordRelation(wrr.db.QueryRow(`
+		SELECT `+wordRelationColumns+` FROM word_relations
+		WHERE LEAST(word_id, related_word_id) = LEAST($1::INTEGER, $2::INTEGER)
+			AND GREATEST(word_id, related_word_id) = GREATEST($1::INTEGER, $2::INTEGER)
+			AND kind = $3;
+		`,
+		wordId,
+		relatedWordId,
+		kind,
+	))
+}
+
+func (wrr *WordRelationRepository) InsertRelation(relationCreation model.WordRelationCreation) (model.WordRelation, error) {
+	// 2つのWordの間に同じ種類の関係が既に存在する場合は追加せず、sql.ErrNoRowsを返す
+	// bidirectionalは、呼び出し元で省略時の値を決めておく
+	return scanWordRelation(wrr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO word_relations
+		(id, word_id, related_word_id, kind, bidirectional)
+		VALUES(%s, $1, $2, $3, $4)
+		ON CONFLICT (LEAST(word_id, related_word_id), GREATEST(word_id, related_word_id), kind) DO NOTHING
+		RETURNING `+wordRelationColumns+`;
+		`,
+		wrr.getSequenceNextvalQuery(),
+	),
+		relationCreation.WordId,
+		relationCreation.RelatedWordId,
+		relationCreation.Kind,
+		relationCreation.Bidirectional,
+	))
+}
+
+func (wrr *WordRelationRepository) UpdateRelation(relationUpdate model.WordRelationUpdate) (model.WordRelation, error) {
+	// bidirectionalが指定されない場合は変更しない
+	// relationIdがwordIdのWordの関係でない場合はsql.ErrNoRowsを返す
+	return scanWordRelation(wrr.db.QueryRow(`
+		UPDATE word_relations
+		SET
+			kind = $1,
+			bidirectional = COALESCE($2::BOOLEAN, bidirectional)
+		WHERE id = $4
+			AND (word_id = $3 OR related_word_id = $3)
+		RETURNING `+wordRelationColumns+`;
+		`,
+		relationUpdate.Kind,
+		relationUpdate.Bidirectional,
+		relationUpdate.WordId,
+		relationUpdate.Id,
+	))
+}
+
+func (wrr *WordRelationRepository) DeleteRelationById(wordId, relationId uint64) (model.WordRelation, error) {
+	return scanWordRelation(wrr.db.QueryRow(`
+		DELETE FROM word_relations
+		WHERE id = $2
+			AND (word_id = $1 OR related_word_id = $1)
+		RETURNING `+wordRelationColumns+`;
+		`,
+		wordId,
+		relationId,
+	))
+}
+
+func (wrr *WordRelationRepository) MoveRelations(fromWordId, toWordId uint64) error {
+	// fromWordIdのWordの関係を、toWordIdのWordの関係に付け替える
+	// 2つのWordの間の関係と、toWordIdのWordに既に同じWordとの同じ種類の関係がある場合は削除する
+	_, err := wrr.db.Exec(`
+		DELETE FROM word_relations r
+		WHERE (r.word_id = $1 AND r.related_word_id = $2)
+			OR (r.word_id = $2 AND r.related_word_id = $1)
+			OR (
+				(r.word_id = $1 OR r.related_word_id = $1)
+				AND EXISTS(
+					SELECT 1
+					FROM word_relations k
+					WHERE k.kind = r.kind
+						AND (
+							(k.word_id = $2 AND k.related_word_id = CASE WHEN r.word_id = $1 THEN r.related_word_id ELSE r.word_id END)
+							OR (k.related_word_id = $2 AND k.word_id = CASE WHEN r.word_id = $1 THEN r.related_word_id ELSE r.word_id END)
+						)
+				)
+			);
+		`,
+		fromWordId,
+		toWordId,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = wrr.db.Exec(`
+		UPDATE word_relations
+		SET
+			word_id = CASE WHEN word_id = $1 THEN $2 ELSE word_id END,
+			related_word_id = CASE WHEN related_word_id = $1 THEN $2 ELSE related_word_id END
+		WHERE word_id = $1
+			OR related_word_id = $1;
+		`,
+		fromWordId,
+		toWordId,
+	)
+
+	return err
+}
@@ -8,7 +8,12 @@ import (
 
 type IWordRepository interface {
 	GetAllWords(userId uint64) ([]model.Word, error)
+	GetWordsAfterCursor(userId uint64, sort, order string, cursor *model.WordCursor, filter model.ListFilter, limit uint64) ([]model.Word, error)
+	GetWordsCount(userId uint64, filter model.ListFilter) (uint64, error)
+	SearchWords(userId uint64, query string, limit, offset uint64) ([]model.WordSearchHit, error)
+	GetSearchWordsCount(userId uint64, query string) (uint64, error)
 	GetWordById(userId, wordId uint64) (model.Word, error)
+	GetWordByWord(userId uint64, word string) (model.Word, error)
 	InsertWord(wordCreation model.WordCreation) (model.Word, error)
 	DeleteWordById(userId, wordId uint64) (model.Word, error)
 	UpdateWord(wordUpate model.WordUpdate) (model.Word, error)
@@ -16,10 +21,10 @@ type IWordRepository interface {
 }
 
 type WordRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewWordRepository(db *sql.DB) IWordRepository {
+func NewWordRepository(db DBTX) IWordRepository {
 	return &WordRepository{db}
 }
 
@@ -31,11 +36,41 @@ func (wr *WordRepository) getSequenceNextvalQuery() string {
 	return fmt.Sprintf("nextval('%s')", wr.getSequenceName())
 }
 
+const wordColumns = "id, word, memo, reading, part_of_speech, pitch_accent, user_id, created_at, updated_at"
+
+func scanWord(row interface{ Scan(...any) error }) (model.Word, error) {
+	// wordColumnsの順に取得した列をmodel.Wordに読み込む
+	word := model.Word{}
+	var pitchAccent sql.NullInt16
+
+	err := row.Scan(
+		&word.Id,
+		&word.Word,
+		&word.Memo,
+		&word.Reading,
+		&word.PartOfSpeech,
+		&pitchAccent,
+		&word.UserId,
+		&word.CreatedAt,
+		&word.UpdatedAt,
+	)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	if pitchAccent.Valid {
+		value := int(pitchAccent.Int16)
+		word.PitchAccent = &value
+	}
+
+	return word, nil
+}
+
 func (wr *WordRepository) GetAllWords(userId uint64) ([]model.Word, error) {
 	var words []model.Word
 
 	rows, err := wr.db.Query(
-		"SELECT id, word, memo, user_id, created_at, updated_at FROM words" +
+		"SELECT " + wordColumns + " FROM words" +
 		" WHERE user_id = $1",
 		userId,
 	)
@@ -45,8 +80,7 @@ func (wr *WordRepository) GetAllWords(userId uint64) ([]model.Word, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		word := model.Word{}
-		err:= rows.Scan(&word.Id, &word.Word, &word.Memo, &word.UserId, &word.CreatedAt, &word.UpdatedAt);
+		word, err := scanWord(rows)
 		if err != nil {
 			return []model.Word{}, err
 		}
@@ -56,103 +90,261 @@ func (wr *WordRepository) GetAllWords(userId uint64) ([]model.Word, error) {
 	return words, nil
 }
 
-func (wr *WordRepository) GetWordById(userId uint64, wordId uint64) (model.Word, error) {
-	word := model.Word{}
+func (wr *WordRepository) GetWordsAfterCursor(
+	userId uint64,
+	sort, order string,
+	cursor *model.WordCursor,
+	filter model.ListFilter,
+	limit uint64,
+) ([]model.Word, error) {
+	// sort列とidの昇順または降順で、cursorより後ろのWordをlimit件取得
+	// cursorがnilの場合は先頭から取得する
+	// filterのタグ、デッキで絞り込む
+	// sort、orderは呼び出し元で検証済みであることを前提とする
+
+	// SQLに埋め込むため、列名と並び順はここで決まった値に限定する
+	sortColumns := map[string]string{
+		model.WordSortWord:      "word",
+		model.WordSortCreatedAt: "created_at",
+		model.WordSortUpdatedAt: "updated_at",
+	}
+	column, ok := sortColumns[sort]
+	if !ok {
+		return []model.Word{}, fmt.Errorf("invalid sort: %s", sort)
+	}
+
+	direction := "ASC"
+	comparison := ">"
+	if order == model.SortOrderDesc {
+		direction = "DESC"
+		comparison = "<"
+	}
+
+	query := "SELECT " + wordColumns + " FROM words" +
+		" WHERE user_id = $1"
+	args := []interface{}{userId}
+
+	if cursor != nil {
+		// 日時の列の場合、cursorの値をTIMESTAMPTZとして比較する
+		valueType := "VARCHAR"
+		if column != "word" {
+			valueType = "TIMESTAMPTZ"
+		}
+
+		query += fmt.Sprintf(" AND (%s, id) %s (CAST($2 AS %s), $3)", column, comparison, valueType)
+		args = append(args, cursor.Value, cursor.Id)
+	}
+
+	filterCondition, args := listFilterCondition("word", filter, args)
+	query += filterCondition
+
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d;", column, direction, direction, len(args)+1)
+	args = append(args, limit)
 
+	rows, err := wr.db.Query(query, args...)
+	if err != nil {
+		return []model.Word{}, err
+	}
+	defer rows.Close()
+
+	var words []model.Word
+	for rows.Next() {
+		word, err := scanWord(rows)
+		if err != nil {
+			return []model.Word{}, err
+		}
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+func (wr *WordRepository) GetWordsCount(userId uint64, filter model.ListFilter) (uint64, error) {
+	var count uint64
+
+	filterCondition, args := listFilterCondition("word", filter, []any{userId})
 	err := wr.db.QueryRow(
-		"SELECT id, word, memo, user_id, created_at, updated_at" + 
+		"SELECT COUNT(*) FROM words"+
+			" WHERE user_id = $1"+filterCondition+";",
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Word、Memo、Notationのいずれかがqueryを含むか、queryとの類似度が高いWordを検索する条件
+// $1: userId, $2: query, $3: queryを含む文字列にマッチするLIKEのパターン
+const searchWordsCondition = `
+	words.user_id = $1
+	AND (
+		words.word ILIKE $3
+		OR words.memo ILIKE $3
+		OR $2 <% words.word
+		OR EXISTS(
+			SELECT 1 FROM notations
+			WHERE notations.word_id = words.id
+			AND (notations.notation ILIKE $3 OR $2 <% notations.notation)
+		)
+	)
+`
+
+func (wr *WordRepository) SearchWords(userId uint64, query string, limit, offset uint64) ([]model.WordSearchHit, error) {
+	// queryに一致するWordを、スコアの高い順に取得
+	// 部分一致したものを優先し、同じ条件の中ではqueryとの類似度が高いものを優先する
+	rows, err := wr.db.Query(`
+		SELECT id, word, memo, user_id, created_at, updated_at, score FROM (
+			SELECT
+				words.*,
+				GREATEST(
+					word_similarity($2, words.word),
+					word_similarity($2, COALESCE(words.memo, '')) * 0.5,
+					COALESCE((
+						SELECT MAX(word_similarity($2, notations.notation)) FROM notations
+						WHERE notations.word_id = words.id
+					), 0)
+				)
+				+ CASE WHEN words.word ILIKE $3 THEN 1 ELSE 0 END
+				+ CASE WHEN EXISTS(
+					SELECT 1 FROM notations
+					WHERE notations.word_id = words.id
+					AND notations.notation ILIKE $3
+				) THEN 0.8 ELSE 0 END
+				+ CASE WHEN words.memo ILIKE $3 THEN 0.5 ELSE 0 END
+				AS score
+			FROM words
+			WHERE ` + searchWordsCondition + `
+		) AS hits
+		ORDER BY score DESC, id ASC
+		LIMIT $4
+		OFFSET $5;
+		`,
+		userId,
+		query,
+		toContainsPattern(query),
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []model.WordSearchHit{}, err
+	}
+	defer rows.Close()
+
+	var wordSearchHits []model.WordSearchHit
+	for rows.Next() {
+		wordSearchHit := model.WordSearchHit{}
+		err := rows.Scan(
+			&wordSearchHit.Word.Id,
+			&wordSearchHit.Word.Word,
+			&wordSearchHit.Word.Memo,
+			&wordSearchHit.Word.UserId,
+			&wordSearchHit.Word.CreatedAt,
+			&wordSearchHit.Word.UpdatedAt,
+			&wordSearchHit.Score,
+		)
+		if err != nil {
+			return []model.WordSearchHit{}, err
+		}
+		wordSearchHits = append(wordSearchHits, wordSearchHit)
+	}
+
+	return wordSearchHits, nil
+}
+
+func (wr *WordRepository) GetSearchWordsCount(userId uint64, query string) (uint64, error) {
+	var count uint64
+
+	err := wr.db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE ` + searchWordsCondition + `;
+		`,
+		userId,
+		query,
+		toContainsPattern(query),
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (wr *WordRepository) GetWordById(userId uint64, wordId uint64) (model.Word, error) {
+	return scanWord(wr.db.QueryRow(
+		"SELECT " + wordColumns +
 		" FROM words" +
 		" WHERE id = $1" +
 		" AND user_id = $2;",
 		wordId,
 		userId,
-	).Scan(&word.Id, &word.Word, &word.Memo, &word.UserId, &word.CreatedAt, &word.UpdatedAt)
-	if err != nil {
-		return model.Word{}, err
-	}
+	))
+}
 
-	return word, nil
+func (wr *WordRepository) GetWordByWord(userId uint64, word string) (model.Word, error) {
+	// userIdのUserの、wordと一致するWordを取得
+	// 存在しない場合はsql.ErrNoRowsを返す
+	return scanWord(wr.db.QueryRow(`
+		SELECT `+wordColumns+` FROM words
+		WHERE user_id = $1
+			AND word = $2
+		`,
+		userId,
+		word,
+	))
 }
 
 func (wr *WordRepository) InsertWord(wordCreation model.WordCreation) (model.Word, error) {
-	createdWord := model.Word{}
-	err := wr.db.QueryRow(
+	// 同じUserに同じWordが既に存在する場合は追加せず、sql.ErrNoRowsを返す
+	// 一意制約との衝突でトランザクションが中断されないよう、ON CONFLICT DO NOTHING とする
+	return scanWord(wr.db.QueryRow(
 		"INSERT INTO words" +
-		" (id, word, memo, user_id)" +
-		" VALUES(" + wr.getSequenceNextvalQuery() + ", $1, $2, $3)" +
-		" RETURNING id, word, memo, user_id, created_at, updated_at;",
+		" (id, word, memo, reading, part_of_speech, pitch_accent, user_id)" +
+		" VALUES(" + wr.getSequenceNextvalQuery() + ", $1, $2, $3, $4, $5, $6)" +
+		" ON CONFLICT (user_id, word) DO NOTHING" +
+		" RETURNING " + wordColumns + ";",
 		wordCreation.Word,
 		wordCreation.Memo,
+		wordCreation.Reading,
+		wordCreation.PartOfSpeech,
+		wordCreation.PitchAccent,
 		wordCreation.LoginUserId,
-	).Scan(
-		&createdWord.Id,
-		&createdWord.Word,
-		&createdWord.Memo,
-		&createdWord.UserId,
-		&createdWord.CreatedAt,
-		&createdWord.UpdatedAt,
-	)
-	if err != nil {
-		return model.Word{}, err
-	}
-	
-	return createdWord, nil
+	))
 }
 
 func (wr *WordRepository) DeleteWordById(userId, wordId uint64) (model.Word, error) {
-	deletedWord := model.Word{}
-
-	err := wr.db.QueryRow(`
+	return scanWord(wr.db.QueryRow(`
 		DELETE FROM words
 		WHERE user_id = $1
 			AND id = $2
-		RETURNING id, word, memo, user_id, created_at, updated_at;
+		RETURNING `+wordColumns+`;
 		`,
 		userId,
 		wordId,
-	).Scan(
-		&deletedWord.Id,
-		&deletedWord.Word,
-		&deletedWord.Memo,
-		&deletedWord.UserId,
-		&deletedWord.CreatedAt,
-		&deletedWord.UpdatedAt,
-	)
-	if err != nil {
-		return model.Word{}, err
-	}
-
-	return deletedWord, nil
+	))
 }
 
 func (wr *WordRepository) UpdateWord(wordUpdate model.WordUpdate) (model.Word, error) {
-	updatedWord := model.Word{}
-
-	err := wr.db.QueryRow(`
+	return scanWord(wr.db.QueryRow(`
 		UPDATE words
 		SET word = $1,
-			memo = $2
-		WHERE user_id = $3
-		AND id = $4
-		RETURNING id, word, memo, user_id, created_at, updated_at;
+			memo = $2,
+			reading = $3,
+			part_of_speech = $4,
+			pitch_accent = $5
+		WHERE user_id = $6
+		AND id = $7
+		RETURNING `+wordColumns+`;
 		`,
 		wordUpdate.Word,
 		wordUpdate.Memo,
+		wordUpdate.Reading,
+		wordUpdate.PartOfSpeech,
+		wordUpdate.PitchAccent,
 		wordUpdate.LoginUserId,
 		wordUpdate.Id,
-	).Scan(
-		&updatedWord.Id,
-		&updatedWord.Word,
-		&updatedWord.Memo,
-		&updatedWord.UserId,
-		&updatedWord.CreatedAt,
-		&updatedWord.UpdatedAt,
-	)
-	if err != nil {
-		return model.Word{}, err
-	}
-
-	return updatedWord, nil
+	))
 }
 
 func (wr *WordRepository) IsWordOwner(wordId uint64, userId uint64) (bool, error) {
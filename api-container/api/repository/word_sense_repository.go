@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"api/model"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type IWordSenseRepository interface {
+	GetSensesByWordId(wordId uint64) ([]model.WordSense, error)
+	GetSensesByWordIds(wordIds []uint64) ([]model.WordSense, error)
+	GetSenseById(wordId, senseId uint64) (model.WordSense, error)
+	InsertSense(senseCreation model.WordSenseCreation) (model.WordSense, error)
+	UpdateSense(senseUpdate model.WordSenseUpdate) (model.WordSense, error)
+	DeleteSenseById(wordId, senseId uint64) (model.WordSense, error)
+	MoveSenses(fromWordId, toWordId uint64) error
+	GetSentenceSensesBySentenceId(sentenceId uint64) ([]model.SentenceWordSense, error)
+	UpsertSentenceSense(sentenceSenseUpsert model.SentenceWordSenseUpsert) (model.SentenceWordSense, error)
+	DeleteSentenceSense(sentenceId, wordId uint64) (model.SentenceWordSense, error)
+}
+
+type WordSenseRepository struct {
+	db DBTX
+}
+
+func NewWordSenseRepository(db DBTX) IWordSenseRepository {
+	return &WordSenseRepository{db}
+}
+
+func (wsr *WordSenseRepository) getSequenceName() string {
+	return "word_sense_id_seq"
+}
+
+func (wsr *WordSenseRepository) getSequenceNextvalQuery() string {
+	return fmt.Sprintf("nextval('%s')", wsr.getSequenceName())
+}
+
+const wordSenseColumns = `id, word_id, gloss, language, part_of_speech, register, position, created_at, updated_at`
+
+func scanWordSense(row interface{ Scan(...any) error }) (model.WordSense, error) {
+	sense := model.WordSense{}
+	err := row.Scan(
+		&sense.Id,
+		&sense.WordId,
+		&sense.Gloss,
+		&sense.Language,
+		&sense.PartOfSpeech,
+		&sense.Register,
+		&sense.Position,
+		&sense.CreatedAt,
+		&sense.UpdatedAt,
+	)
+	if err != nil {
+		return model.WordSense{}, err
+	}
+
+	return sense, nil
+}
+
+func (wsr *WordSenseRepository) querySenses(query string, args ...any) ([]model.WordSense, error) {
+	rows, err := wsr.db.Query(query, args...)
+	if err != nil {
+		return []model.WordSense{}, err
+	}
+	defer rows.Close()
+
+	senses := []model.WordSense{}
+	for rows.Next() {
+		sense, err := scanWordSense(rows)
+		if err != nil {
+			return []model.WordSense{}, err
+		}
+		senses = append(senses, sense)
+	}
+
+	return senses, nil
+}
+
+// 以下の意味の操作は、wordIdのWordの所有者の検証が済んでいることを前提とする
+
+func (wsr *WordSenseRepository) GetSensesByWordId(wordId uint64) ([]model.WordSense, error) {
+	return wsr.querySenses(`
+		SELECT `+wordSenseColumns+` FROM word_senses
+		WHERE word_id = $1
+		ORDER BY position, id;
+		`,
+		wordId,
+	)
+}
+
+func (wsr *WordSenseRepository) GetSensesByWordIds(wordIds []uint64) ([]model.WordSense, error) {
+	// wordIdsの各Wordの意味を、1回のクエリでまとめて取得
+	// Wordごとに、表示順に返す
+	if len(wordIds) == 0 {
+		return []model.WordSense{}, nil
+	}
+
+	ids := make([]int64, len(wordIds))
+	for i, wordId := range wordIds {
+		ids[i] = int64(wordId)
+	}
+
+	return wsr.querySenses(`
+		SELECT `+wordSenseColumns+` FROM word_senses
+		WHERE word_id = ANY($1::INTEGER[])
+		ORDER BY word_id, position, id;
+		`,
+		pq.Array(ids),
+	)
+}
+
+func (wsr *WordSenseRepository) GetSenseById(wordId, senseId uint64) (model.WordSense, error) {
+	// senseIdがwordIdのWordの意味でない場合はsql.ErrNoRowsを返す
+	return scanWordSense(wsr.db.QueryRow(`
+		SELECT `+wordSenseColumns+` FROM word_senses
+		WHERE word_id = $1
+			AND id = $2;
+		`,
+		wordId,
+		senseId,
+	))
+}
+
+func (wsr *WordSenseRepository) InsertSense(senseCreation model.WordSenseCreation) (model.WordSense, error) {
+	// positionが指定されない場合は、Wordの最後の意味として追加する
+	return scanWordSense(wsr.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO word_senses
+		(id, word_id, gloss, language, part_of_speech, register, position)
+		VALUES(
+			%s, $1, $2, $3, $4, $5,
+			COALESCE(
+				$6::INTEGER,
+				(SELECT COALESCE(MAX(position), 0) + 1 FROM word_senses WHERE word_id = $1)
+			)
+		)
+		RETURNING `+wordSenseColumns+`;
+		`,
+		wsr.getSequenceNextvalQuery(),
+	),
+		senseCreation.WordId,
+		senseCreation.Gloss,
+		senseCreation.Language,
+		senseCreation.PartOfSpeech,
+		senseCreation.Register,
+		senseCreation.Position,
+	))
+}
+
+func (wsr *WordSenseRepository) UpdateSense(senseUpdate model.WordSenseUpdate) (model.WordSense, error) {
+	// positionが指定されない場合は変更しない
+	// senseIdがwordIdのWordの意味でない場合はsql.ErrNoRowsを返す
+	return scanWordSense(wsr.db.QueryRow(`
+		UPDATE word_senses
+		SET
+			gloss = $1,
+			language = $2,
+			part_of_speech = $3,
+			register = $4,
+			position = COALESCE($5::INTEGER, position)
+		WHERE word_id = $6
+			AND id = $7
+		RETURNING `+wordSenseColumns+`;
+		`,
+		senseUpdate.Gloss,
+		senseUpdate.Language,
+		senseUpdate.PartOfSpeech,
+		senseUpdate.Register,
+		senseUpdate.Position,
+		senseUpdate.WordId,
+		senseUpdate.Id,
+	))
+}
+
+func (wsr *WordSenseRepository) DeleteSenseById(wordId, senseId uint64) (model.WordSense, error) {
+	// sentence_word_sensesのレコードは外部キーにより削除される
+	return scanWordSense(wsr.db.QueryRow(`
+		DELETE FROM word_senses
+		WHERE word_id = $1
+			AND id = $2
+		RETURNING `+wordSenseColumns+`;
+		`,
+		wordId,
+		senseId,
+	))
+}
+
+func (wsr *WordSenseRepository) MoveSenses(fromWordId, toWordId uint64) error {
+	// fromWordIdのWordの意味を、toWordIdのWordの意味の後ろに移す
+	// 同じSentenceに対してtoWordIdのWordの意味が既に指定されている場合は、そちらを優先する
+	_, err := wsr.db.Exec(`
+		DELETE FROM sentence_word_senses s
+		WHERE s.word_id = $1
+			AND EXISTS(
+				SELECT 1
+				FROM sentence_word_senses k
+				WHERE k.word_id = $2
+					AND k.sentence_id = s.sentence_id
+			);
+		`,
+		fromWordId,
+		toWordId,
+	)
+	if err != nil {
+		return err
+	}
+
+	// sentence_word_sensesのword_idは外部キーにより更新される
+	_, err = wsr.db.Exec(`
+		UPDATE word_senses
+		SET
+			word_id = $2,
+			position = position + (SELECT COALESCE(MAX(position), 0) FROM word_senses WHERE word_id = $2)
+		WHERE word_id = $1;
+		`,
+		fromWordId,
+		toWordId,
+	)
+
+	return err
+}
+
+const sentenceWordSenseColumns = `sentence_id, word_id, sense_id, created_at, updated_at`
+
+func scanSentenceWordSense(row interface{ Scan(...any) error }) (model.SentenceWordSense, error) {
+	sentenceSense := model.SentenceWordSense{}
+	err := row.Scan(
+		&sentenceSense.SentenceId,
+		&sentenceSense.WordId,
+		&sentenceSense.SenseId,
+		&sentenceSense.CreatedAt,
+		&sentenceSense.UpdatedAt,
+	)
+	if err != nil {
+		return model.SentenceWordSense{}, err
+	}
+
+	return sentenceSense, nil
+}
+
+func (wsr *WordSenseRepository) GetSentenceSensesBySentenceId(sentenceId uint64) ([]model.SentenceWordSense, error) {
+	// sentenceIdのSentenceに指定された意味のうち、現在Wordと紐づいているもののみ取得
+	// 紐づけが無くなった指定も、再び紐づいた場合に有効となるよう削除はしない
+	rows, err := wsr.db.Query(`
+		SELECT s.sentence_id, s.word_id, s.sense_id, s.created_at, s.updated_at
+		FROM sentence_word_senses s
+		JOIN sentences_words sw
+			ON sw.sentence_id = s.sentence_id
+			AND sw.word_id = s.word_id
+		WHERE s.sentence_id = $1
+		ORDER BY s.word_id;
+		`,
+		sentenceId,
+	)
+	if err != nil {
+		return []model.SentenceWordSense{}, err
+	}
+	defer rows.Close()
+
+	sentenceSenses := []model.SentenceWordSense{}
+	for rows.Next() {
+		sentenceSense, err := scanSentenceWordSense(rows)
+		if err != nil {
+			return []model.SentenceWordSense{}, err
+		}
+		sentenceSenses = append(sentenceSenses, sentenceSense)
+	}
+
+	return sentenceSenses, nil
+}
+
+func (wsr *WordSenseRepository) UpsertSentenceSense(sentenceSenseUpsert model.SentenceWordSenseUpsert) (model.SentenceWordSense, error) {
+	// SentenceとWordの組に意味を指定し、既に指定がある場合は意味を更新する
+	// SentenceとWordが紐づいていない場合は指定せず、sql.ErrNoRowsを返す
+	return scanSentenceWordSense(wsr.db.QueryRow(`
+		INSERT INTO sentence_word_senses
+		(sentence_id, word_id, sense_id)
+		SELECT $1, $2, $3
+		WHERE EXISTS(
+			SELECT 1
+			FROM sentences_words
+			WHERE sentence_id = $1
+				AND word_id = $2
+		)
+		ON CONFLICT (sentence_id, word_id) DO UPDATE
+		SET sense_id = EXCLUDED.sense_id
+		RETURNING `+sentenceWordSenseColumns+`;
+		`,
+		sentenceSenseUpsert.SentenceId,
+		sentenceSenseUpsert.WordId,
+		sentenceSenseUpsert.SenseId,
+	))
+}
+
+func (wsr *WordSenseRepository) DeleteSentenceSense(sentenceId, wordId uint64) (model.SentenceWordSense, error) {
+	// 指定が無い場合はsql.ErrNoRowsを返す
+	return scanSentenceWordSense(wsr.db.QueryRow(`
+		DELETE FROM sentence_word_senses
+		WHERE sentence_id = $1
+			AND word_id = $2
+		RETURNING `+sentenceWordSenseColumns+`;
+		`,
+		sentenceId,
+		wordId,
+	))
+}
@@ -5,8 +5,14 @@ import (
 	"api/db"
 	"api/repository"
 	"api/usecase"
+	"context"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -15,6 +21,11 @@ import (
 func main() {
 	e := echo.New()
 	db := db.NewDB()
+	// エラーは全て{code, message, details, request_id}の形式で返す
+	e.HTTPErrorHandler = controller.HTTPErrorHandler
+	// リクエストのモデルのvalidateタグを検証する
+	e.Validator = controller.NewRequestValidator()
+	e.Use(middleware.RequestID())
 	e.Use(middleware.CORSWithConfig(
 		middleware.CORSConfig{
 			AllowOrigins: []string{
@@ -27,6 +38,12 @@ func main() {
 				http.MethodDelete,
 			},
 			AllowHeaders: []string{},
+			// エラー時にrequest_idと照合できるよう、レスポンスのX-Request-Idを参照可能にする
+			ExposeHeaders: []string{
+				echo.HeaderXRequestID,
+			},
+			// Session用のCookieを送受信するため
+			AllowCredentials: true,
 		},
 	))
 
@@ -35,27 +52,121 @@ func main() {
 	sr := repository.NewSentenceRepository(db)
 	swr := repository.NewSentencesWordsRepository(db)
 	nr := repository.NewNotationRepository(db)
+	ur := repository.NewUserRepository(db)
+	ssr := repository.NewSessionRepository(db)
+	rtr := repository.NewRefreshTokenRepository(db)
+	rvr := repository.NewReviewRepository(db)
+	jr := repository.NewJobRepository(db)
+	tr := repository.NewTagRepository(db)
+	dr := repository.NewDeckRepository(db)
+	wsr := repository.NewWordSenseRepository(db)
+	wrr := repository.NewWordRelationRepository(db)
+	uow := repository.NewUnitOfWork(db)
+
+	// WordとSentenceの紐づけ方式
+	// WORD_MATCHER=substringの場合、形態素解析を行わず部分文字列で判定する
+	// WORD_MATCHER=aho_corasickの場合、substringと同じ判定をWordの件数によらない時間で行う
+	m, err := usecase.NewMatcher(os.Getenv("WORD_MATCHER"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	// 同じ範囲に複数のWordが出現する場合に、どのWordをリンクとするかの規則
+	// LINK_TIE_BREAK=notation_kind,newer のようにカンマ区切りで、優先する規則から順に指定する
+	tieBreakRules, err := usecase.ParseTieBreakRules(os.Getenv("LINK_TIE_BREAK"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	lr := usecase.NewLinkResolver(tieBreakRules)
+
+	// WordとSentenceの紐づけの再構築を行う時点
+	// ASSOCIATION_MODE=syncの場合、Word、Notation、Sentenceを変更したリクエスト内で行う
+	// 未指定またはasyncの場合、ジョブを追加してワーカーが行う
+	associationMode, err := usecase.ParseAssociationMode(os.Getenv("ASSOCIATION_MODE"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
 
 	// Usecase
-	wu := usecase.NewWordUsecase(wr, sr, swr, nr)
-	su := usecase.NewSentenceUsecase(sr, wr, swr, nr)
-	au := usecase.NewAssociationUsecase(wr, sr, swr, nr)
+	wu := usecase.NewWordUsecase(wr, sr, swr, nr, tr, dr, wsr, wrr, jr, uow, m, lr, associationMode)
+	su := usecase.NewSentenceUsecase(sr, wr, swr, nr, dr, jr, uow, m, lr, associationMode)
+	au := usecase.NewAssociationUsecase(wr, sr, swr, nr, dr, uow, m, lr)
+	seu := usecase.NewSearchUsecase(wr, sr, nr, au)
+	rvu := usecase.NewReviewUsecase(rvr, wr, swr, uow, usecase.NewSM2Scheduler())
+	atu := usecase.NewAuthUsecase(ur, ssr, rtr, []byte(os.Getenv("JWT_SECRET")))
+	ju := usecase.NewJobUsecase(jr, wu, su)
+	tu := usecase.NewTagUsecase(tr, wr, sr)
+	du := usecase.NewDeckUsecase(dr, wr, sr, uow, wu, su)
+	wsu := usecase.NewWordSenseUsecase(wsr, wr, sr)
+	wru := usecase.NewWordRelationUsecase(wrr, wr, nr)
+
+	// ジョブを実行するワーカー
+	// ASSOCIATION_MODE=syncの場合もジョブが残っている場合があるため起動する
+	// JOB_WORKERSで起動する数を指定する（未指定の場合は2）
+	jobWorkers := 2
+	if jobWorkersParam := os.Getenv("JOB_WORKERS"); jobWorkersParam != "" {
+		jobWorkers, err = strconv.Atoi(jobWorkersParam)
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+	}
+	// SIGINT、SIGTERMを受け取るとctxがキャンセルされ、ワーカーは実行中のジョブを終えてから停止する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+	for i := 0; i < jobWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			ju.RunWorker(ctx, time.Second)
+		}()
+	}
 
 	// Controller
 	wc := controller.NewWordController(wu, au)
 	sc := controller.NewSentenceController(su, au)
 	nc := controller.NewNotationController(wu)
+	ac := controller.NewAuthController(atu)
+	sec := controller.NewSearchController(seu)
+	rvc := controller.NewReviewController(rvu)
+	jc := controller.NewJobController(ju)
+	tc := controller.NewTagController(tu)
+	dc := controller.NewDeckController(du)
+	wsc := controller.NewWordSenseController(wsu)
+	wrc := controller.NewWordRelationController(wru)
+
+	a := e.Group("/auth")
+	a.POST("/signup", ac.SignUp)
+	a.POST("/login", ac.LogIn)
+	a.POST("/logout", ac.LogOut)
+	a.POST("/token", ac.IssueToken)
+	a.POST("/token/revoke", ac.RevokeToken)
+	a.GET("/me", ac.GetLoginUser, ac.RequireLogin)
 
-	w := e.Group("/words")
+	// 以下のルートは有効なSessionまたはアクセストークンが無い場合401を返す
+	w := e.Group("/words", ac.RequireLogin)
 	w.GET("", wc.GetAllWords)
 	w.GET("/:wordId", wc.GetWordById)
 	w.POST("", wc.CreateWord)
 	w.POST("/multiple", wc.CreateMultipleWords)
 	w.PUT("/:wordId", wc.UpdateWord)
 	w.DELETE("/:wordId", wc.DeleteWord)
+	w.POST("/:wordId/merge", wc.MergeWords)
 	w.GET("/:wordId/associated-sentences", wc.GetAssociatedSentencesWithLink)
+	w.GET("/:wordId/tags", tc.GetWordTags)
+	w.PUT("/:wordId/tags/:tagId", tc.AddTagToWord)
+	w.DELETE("/:wordId/tags/:tagId", tc.RemoveTagFromWord)
+	w.GET("/:wordId/senses", wsc.GetSenses)
+	w.POST("/:wordId/senses", wsc.CreateSense)
+	w.PUT("/:wordId/senses/:senseId", wsc.UpdateSense)
+	w.DELETE("/:wordId/senses/:senseId", wsc.DeleteSense)
+	w.GET("/:wordId/relations", wrc.GetRelations)
+	w.GET("/:wordId/relations/suggestions", wrc.GetCompoundSuggestions)
+	w.POST("/:wordId/relations", wrc.CreateRelation)
+	w.PUT("/:wordId/relations/:relationId", wrc.UpdateRelation)
+	w.DELETE("/:wordId/relations/:relationId", wrc.DeleteRelation)
 
-	s := e.Group("/sentences")
+	s := e.Group("/sentences", ac.RequireLogin)
 	s.GET("", sc.GetAllSentences)
 	s.GET("/:sentenceId", sc.GetSentenceById)
 	s.GET("/count", sc.GetSentencesCount)
@@ -64,14 +175,61 @@ func main() {
 	s.PUT("/:sentenceId", sc.UpdateSentence)
 	s.DELETE("/:sentenceId", sc.DeleteSentence)
 	s.GET("/:sentenceId/associated-words", sc.GetAssociatedWords)
+	s.GET("/:sentenceId/word-overrides", sc.GetAssociationOverrides)
+	s.PUT("/:sentenceId/word-overrides/:wordId", sc.SetAssociationOverride)
+	s.DELETE("/:sentenceId/word-overrides/:wordId", sc.DeleteAssociationOverride)
+	s.GET("/:sentenceId/tags", tc.GetSentenceTags)
+	s.PUT("/:sentenceId/tags/:tagId", tc.AddTagToSentence)
+	s.DELETE("/:sentenceId/tags/:tagId", tc.RemoveTagFromSentence)
+	s.GET("/:sentenceId/word-senses", wsc.GetSentenceSenses)
+	s.PUT("/:sentenceId/word-senses/:wordId", wsc.SetSentenceSense)
+	s.DELETE("/:sentenceId/word-senses/:wordId", wsc.DeleteSentenceSense)
 
-	wn := e.Group("/words/:wordId/notations")
+	wn := e.Group("/words/:wordId/notations", ac.RequireLogin)
 	wn.GET("", nc.GetAllNotations)
 	wn.POST("", nc.CreateNotation)
 
-	n := e.Group("/notations")
+	n := e.Group("/notations", ac.RequireLogin)
 	n.PUT("/:notationId", nc.UpdateNotation)
 	n.DELETE("/:notationId", nc.DeleteNotation)
 
-	e.Logger.Fatal(e.Start(":8080"))
+	t := e.Group("/tags", ac.RequireLogin)
+	t.GET("", tc.GetAllTags)
+	t.POST("", tc.CreateTag)
+	t.PUT("/:tagId", tc.UpdateTag)
+	t.DELETE("/:tagId", tc.DeleteTag)
+
+	d := e.Group("/decks", ac.RequireLogin)
+	d.GET("", dc.GetAllDecks)
+	d.GET("/:deckId", dc.GetDeckById)
+	d.POST("", dc.CreateDeck)
+	d.PUT("/:deckId", dc.UpdateDeck)
+	d.DELETE("/:deckId", dc.DeleteDeck)
+	d.PUT("/:deckId/words/:wordId", dc.AddWordToDeck)
+	d.DELETE("/:deckId/words/:wordId", dc.RemoveWordFromDeck)
+	d.PUT("/:deckId/sentences/:sentenceId", dc.AddSentenceToDeck)
+	d.DELETE("/:deckId/sentences/:sentenceId", dc.RemoveSentenceFromDeck)
+
+	e.GET("/search", sec.Search, ac.RequireLogin)
+
+	rv := e.Group("/reviews", ac.RequireLogin)
+	rv.GET("/due", rvc.GetDueReviews)
+	rv.POST("/:wordId", rvc.ReviewWord)
+
+	e.GET("/jobs/:jobId", jc.GetJobById, ac.RequireLogin)
+
+	go func() {
+		if err := e.Start(":8080"); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// シグナルを受け取ったら、処理中のリクエストとジョブの完了を待ってから終了する
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+	workers.Wait()
 }
@@ -0,0 +1,287 @@
+package test
+
+import (
+	"api/model"
+	"api/repository"
+	"api/usecase"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// データベースを使用しないベンチマーク
+// リポジトリをメモリ上の実装に置き換え、Sentenceとの紐づけの計算とクエリの回数のみを計測する
+// go test ./test -run '^$' -bench Associate -benchtime 1x
+
+const (
+	benchmarkUserId         = 1
+	benchmarkWordsCount     = 10000
+	benchmarkSentencesCount = 50000
+	benchmarkSentenceLength = 20
+)
+
+var benchmarkKana = []rune("あいうえおかきくけこさしすせそたちつてとなにぬねのはひふへほまみむめもやゆよらりるれろわをん")
+
+func benchmarkTerm(n, length int) string {
+	// nをbenchmarkKanaの文字による、length桁の文字列に変換
+	runes := make([]rune, length)
+	for i := length - 1; i >= 0; i-- {
+		runes[i] = benchmarkKana[n%len(benchmarkKana)]
+		n /= len(benchmarkKana)
+	}
+
+	return string(runes)
+}
+
+// 実行したクエリの回数を数える、メモリ上のリポジトリ
+// 使用しないメソッドは埋め込んだインターフェースのゼロ値に委ねる（呼び出すとpanicする）
+type benchmarkWordRepository struct {
+	repository.IWordRepository
+	words   []model.Word
+	queries *int
+}
+
+func (wr *benchmarkWordRepository) GetAllWords(userId uint64) ([]model.Word, error) {
+	*wr.queries++
+	return wr.words, nil
+}
+
+func (wr *benchmarkWordRepository) GetWordById(userId, wordId uint64) (model.Word, error) {
+	*wr.queries++
+	return wr.words[wordId-1], nil
+}
+
+func (wr *benchmarkWordRepository) IsWordOwner(wordId, userId uint64) (bool, error) {
+	*wr.queries++
+	return true, nil
+}
+
+type benchmarkSentenceRepository struct {
+	repository.ISentenceRepository
+	sentences []model.Sentence
+	queries   *int
+}
+
+func (sr *benchmarkSentenceRepository) GetAllSentences(userId uint64) ([]model.Sentence, error) {
+	*sr.queries++
+	return sr.sentences, nil
+}
+
+func (sr *benchmarkSentenceRepository) GetSentenceById(userId, sentenceId uint64) (model.Sentence, error) {
+	*sr.queries++
+	return sr.sentences[sentenceId-1], nil
+}
+
+func (sr *benchmarkSentenceRepository) IsSentenceOwner(sentenceId, userId uint64) (bool, error) {
+	*sr.queries++
+	return true, nil
+}
+
+type benchmarkNotationRepository struct {
+	repository.INotationRepository
+	notationsByWordId map[uint64][]model.Notation
+	notations         []model.Notation
+	queries           *int
+}
+
+func (nr *benchmarkNotationRepository) GetAllNotations(wordId uint64) ([]model.Notation, error) {
+	*nr.queries++
+	return nr.notationsByWordId[wordId], nil
+}
+
+func (nr *benchmarkNotationRepository) GetAllNotationsByUserId(userId uint64) ([]model.Notation, error) {
+	*nr.queries++
+	return nr.notations, nil
+}
+
+type benchmarkSentencesWordsRepository struct {
+	repository.ISentencesWordsRepository
+	queries *int
+}
+
+func (swr *benchmarkSentencesWordsRepository) AssociateSentenceWithWord(sentenceId, wordId uint64) error {
+	*swr.queries++
+	return nil
+}
+
+func (swr *benchmarkSentencesWordsRepository) AssociateSentencesWithWords(sentencesWords []model.SentenceWord) error {
+	*swr.queries++
+	return nil
+}
+
+func (swr *benchmarkSentencesWordsRepository) InsertOccurrences(occurrences []model.SentenceWordOccurrence) error {
+	*swr.queries++
+	return nil
+}
+
+func (swr *benchmarkSentencesWordsRepository) GetOverridesBySentenceIds(sentenceIds []uint64) ([]model.AssociationOverride, error) {
+	*swr.queries++
+	return []model.AssociationOverride{}, nil
+}
+
+func (swr *benchmarkSentencesWordsRepository) DeleteAllAssociationBySentenceIds(sentenceIds []uint64) error {
+	*swr.queries++
+	return nil
+}
+
+type benchmarkDeckRepository struct {
+	repository.IDeckRepository
+	queries *int
+}
+
+func (dr *benchmarkDeckRepository) GetAssociationScopes(userId uint64) ([]model.AssociationScope, error) {
+	*dr.queries++
+	return []model.AssociationScope{}, nil
+}
+
+type benchmarkRepositories struct {
+	wr      *benchmarkWordRepository
+	sr      *benchmarkSentenceRepository
+	nr      *benchmarkNotationRepository
+	swr     *benchmarkSentencesWordsRepository
+	dr      *benchmarkDeckRepository
+	queries *int
+}
+
+func newBenchmarkRepositories() benchmarkRepositories {
+	// benchmarkWordsCount件のWord（それぞれNotationを1つ持つ）と、
+	// benchmarkSentencesCount件のSentenceを作成
+	queries := 0
+
+	words := make([]model.Word, benchmarkWordsCount)
+	notations := make([]model.Notation, benchmarkWordsCount)
+	notationsByWordId := map[uint64][]model.Notation{}
+	for i := range words {
+		words[i] = model.Word{Id: uint64(i + 1), Word: benchmarkTerm(i, 3), UserId: benchmarkUserId}
+		notations[i] = model.Notation{
+			Id:       uint64(i + 1),
+			WordId:   words[i].Id,
+			Notation: benchmarkTerm(i, 4),
+			Kind:     model.NotationKindManual,
+		}
+		notationsByWordId[words[i].Id] = []model.Notation{notations[i]}
+	}
+
+	// 結果を再現できるよう、乱数のシードは固定する
+	r := rand.New(rand.NewSource(1))
+	sentences := make([]model.Sentence, benchmarkSentencesCount)
+	for i := range sentences {
+		runes := make([]rune, benchmarkSentenceLength)
+		for j := range runes {
+			runes[j] = benchmarkKana[r.Intn(len(benchmarkKana))]
+		}
+		sentences[i] = model.Sentence{Id: uint64(i + 1), Sentence: string(runes), UserId: benchmarkUserId}
+	}
+
+	return benchmarkRepositories{
+		wr:      &benchmarkWordRepository{words: words, queries: &queries},
+		sr:      &benchmarkSentenceRepository{sentences: sentences, queries: &queries},
+		nr:      &benchmarkNotationRepository{notationsByWordId: notationsByWordId, notations: notations, queries: &queries},
+		swr:     &benchmarkSentencesWordsRepository{queries: &queries},
+		dr:      &benchmarkDeckRepository{queries: &queries},
+		queries: &queries,
+	}
+}
+
+func (repos benchmarkRepositories) newWordUsecase(m usecase.IMatcher) *usecase.WordUsecase {
+	return usecase.NewWordUsecase(repos.wr, repos.sr, repos.swr, repos.nr, nil, repos.dr, nil, nil, nil, nil, m, linkResolver, usecase.AssociationModeSync)
+}
+
+func (repos benchmarkRepositories) newSentenceUsecase(m usecase.IMatcher) *usecase.SentenceUsecase {
+	return usecase.NewSentenceUsecase(repos.sr, repos.wr, repos.swr, repos.nr, repos.dr, nil, nil, m, linkResolver, usecase.AssociationModeSync)
+}
+
+func containsWordOrNotation(sentence string, word model.Word, notations []model.Notation) bool {
+	// Aho-Corasick法の導入前の判定方法
+	if strings.Contains(sentence, word.Word) {
+		return true
+	}
+	for _, notation := range notations {
+		if strings.Contains(sentence, notation.Notation) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func BenchmarkAssociateSentenceWithAllWords(b *testing.B) {
+	// 1つのSentenceを、全Wordと紐づける
+	repos := newBenchmarkRepositories()
+	sentenceId := uint64(1)
+
+	b.Run("per_word_queries", func(b *testing.B) {
+		// 導入前の方法: Wordごとに、Notationの取得と紐づけのクエリを実行する
+		sentence := repos.sr.sentences[sentenceId-1]
+		*repos.queries = 0
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			words, _ := repos.wr.GetAllWords(benchmarkUserId)
+			for _, word := range words {
+				notations, _ := repos.nr.GetAllNotations(word.Id)
+				if containsWordOrNotation(sentence.Sentence, word, notations) {
+					repos.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
+				}
+			}
+		}
+		b.ReportMetric(float64(*repos.queries)/float64(b.N), "queries/op")
+	})
+
+	for _, name := range []string{"substring", "aho_corasick"} {
+		m, _ := usecase.NewMatcher(name)
+		su := repos.newSentenceUsecase(m)
+
+		b.Run(name, func(b *testing.B) {
+			*repos.queries = 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := su.AssociateSentenceWithAllWords(benchmarkUserId, sentenceId)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(*repos.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkAssociateWordWithAllSentences(b *testing.B) {
+	// 1つのWordを、全Sentenceと紐づける
+	repos := newBenchmarkRepositories()
+	wordId := uint64(1)
+
+	b.Run("per_sentence_queries", func(b *testing.B) {
+		// 導入前の方法: Wordが含まれるSentenceごとに紐づけのクエリを実行する
+		// 重なり合う他のWordを考慮しないため、他の方法とは紐づけの結果が異なる
+		word := repos.wr.words[wordId-1]
+		*repos.queries = 0
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sentences, _ := repos.sr.GetAllSentences(benchmarkUserId)
+			notations, _ := repos.nr.GetAllNotations(word.Id)
+			for _, sentence := range sentences {
+				if containsWordOrNotation(sentence.Sentence, word, notations) {
+					repos.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
+				}
+			}
+		}
+		b.ReportMetric(float64(*repos.queries)/float64(b.N), "queries/op")
+	})
+
+	for _, name := range []string{"substring", "aho_corasick"} {
+		m, _ := usecase.NewMatcher(name)
+		wu := repos.newWordUsecase(m)
+
+		b.Run(name, func(b *testing.B) {
+			*repos.queries = 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := wu.AssociateWordWithAllSentences(benchmarkUserId, wordId)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(*repos.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
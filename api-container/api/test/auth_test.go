@@ -0,0 +1,358 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignUp(t *testing.T) {
+	// Userを新規登録できることをテスト
+	email := "signup@example.com"
+	deleteUserByEmail(email)
+
+	reqBody := fmt.Sprintf(`{
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/signup",
+		ac.SignUp,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, email, toUserResponse(rec).Email)
+
+	// パスワードはハッシュ化して保存される
+	var password string
+	db.QueryRow(`
+		SELECT password FROM users
+		WHERE email = $1;
+		`,
+		email,
+	).Scan(&password)
+
+	assert.NotEqual(t, "", password)
+	assert.NotEqual(t, "password1234", password)
+}
+
+func TestSignUp_Duplicate(t *testing.T) {
+	// 既に使われているemailで登録しようとした場合、409が返ることをテスト
+	email := "duplicate@example.com"
+	signUpTestUser(t, email, "password1234")
+
+	reqBody := fmt.Sprintf(`{
+		"email": "%s",
+		"password": "password5678"
+	}`,
+		email,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/signup",
+		ac.SignUp,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestSignUp_WithShortPassword(t *testing.T) {
+	// パスワードが短すぎる場合、登録できないことをテスト
+	email := "short@example.com"
+	deleteUserByEmail(email)
+
+	reqBody := fmt.Sprintf(`{
+		"email": "%s",
+		"password": "pass"
+	}`,
+		email,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/signup",
+		ac.SignUp,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM users
+		WHERE email = $1;
+		`,
+		email,
+	).Scan(&count)
+
+	assert.Equal(t, 0, count)
+}
+
+func TestLogIn(t *testing.T) {
+	// ログインするとSessionが作成され、Cookieが発行されることをテスト
+	DeleteAllFromSessions()
+
+	email := "login@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	cookies := logInTestUser(t, email, "password1234")
+
+	assert.Equal(t, 1, len(cookies))
+	assert.Equal(t, "session_id", cookies[0].Name)
+	assert.NotEqual(t, "", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+
+	assert.Equal(t, 1, getCountFromSessionsByUserId(user.Id))
+}
+
+func TestLogIn_WithWrongPassword(t *testing.T) {
+	// パスワードが誤っている場合、401が返りSessionが作成されないことをテスト
+	DeleteAllFromSessions()
+
+	email := "wrongpass@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	reqBody := fmt.Sprintf(`{
+		"email": "%s",
+		"password": "wrongpassword"
+	}`,
+		email,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/login",
+		ac.LogIn,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, 0, getCountFromSessionsByUserId(user.Id))
+}
+
+func TestLogOut(t *testing.T) {
+	// ログアウトするとSessionが削除されることをテスト
+	DeleteAllFromSessions()
+
+	email := "logout@example.com"
+	user := signUpTestUser(t, email, "password1234")
+	cookies := logInTestUser(t, email, "password1234")
+	assert.Equal(t, 1, getCountFromSessionsByUserId(user.Id))
+
+	_, rec := ExecController(
+		t,
+		"/auth/logout",
+		ac.LogOut,
+		HttpMethod(http.MethodPost),
+		Cookies(cookies),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 0, getCountFromSessionsByUserId(user.Id))
+}
+
+func TestRequireLogin_WithoutSession(t *testing.T) {
+	// Sessionが無い場合、401が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/words",
+		ac.RequireLogin(wc.GetAllWords),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireLogin_WithSession(t *testing.T) {
+	// 有効なSessionがある場合、SessionのUserとしてControllerが呼ばれることをテスト
+	DeleteAllFromSessions()
+
+	email := "session@example.com"
+	user := signUpTestUser(t, email, "password1234")
+	cookies := logInTestUser(t, email, "password1234")
+
+	_, rec := ExecController(
+		t,
+		"/auth/me",
+		ac.RequireLogin(ac.GetLoginUser),
+		Cookies(cookies),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, user.Id, toUserResponse(rec).Id)
+}
+
+func TestIssueToken_WithPassword(t *testing.T) {
+	// email、passwordでアクセストークンとリフレッシュトークンを発行できることをテスト
+	email := "token@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	statusCode, tokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	))
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Bearer", tokenRes.TokenType)
+	assert.NotEqual(t, "", tokenRes.AccessToken)
+	assert.NotEqual(t, "", tokenRes.RefreshToken)
+	assert.Equal(t, 1, getCountFromValidRefreshTokensByUserId(user.Id))
+}
+
+func TestIssueToken_WithWrongPassword(t *testing.T) {
+	// パスワードが誤っている場合、401が返ることをテスト
+	email := "tokenwrong@example.com"
+	signUpTestUser(t, email, "password1234")
+
+	statusCode, _ := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "wrongpassword"
+	}`,
+		email,
+	))
+
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestIssueToken_WithRefreshToken(t *testing.T) {
+	// リフレッシュトークンを使うと新しいトークンが発行され、
+	// 使用したリフレッシュトークンは失効することをテスト
+	email := "refresh@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	_, firstTokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	))
+
+	statusCode, secondTokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "refresh_token",
+		"refresh_token": "%s"
+	}`,
+		firstTokenRes.RefreshToken,
+	))
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEqual(t, firstTokenRes.RefreshToken, secondTokenRes.RefreshToken)
+	assert.Equal(t, 1, getCountFromValidRefreshTokensByUserId(user.Id))
+}
+
+func TestIssueToken_WithReusedRefreshToken(t *testing.T) {
+	// 失効済みのリフレッシュトークンが再利用された場合、
+	// 401が返り、同じログインから発行されたトークンが全て失効することをテスト
+	email := "reuse@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	_, firstTokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	))
+
+	refreshBody := fmt.Sprintf(`{
+		"grant_type": "refresh_token",
+		"refresh_token": "%s"
+	}`,
+		firstTokenRes.RefreshToken,
+	)
+
+	issueTestToken(t, refreshBody)
+	assert.Equal(t, 1, getCountFromValidRefreshTokensByUserId(user.Id))
+
+	statusCode, _ := issueTestToken(t, refreshBody)
+
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Equal(t, 0, getCountFromValidRefreshTokensByUserId(user.Id))
+}
+
+func TestRevokeToken(t *testing.T) {
+	// リフレッシュトークンを失効させられることをテスト
+	email := "revoke@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	_, tokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	))
+
+	_, rec := ExecController(
+		t,
+		"/auth/token/revoke",
+		ac.RevokeToken,
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"refresh_token": "%s"}`, tokenRes.RefreshToken)),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 0, getCountFromValidRefreshTokensByUserId(user.Id))
+}
+
+func TestRequireLogin_WithBearerToken(t *testing.T) {
+	// 有効なアクセストークンがある場合、トークンのUserとしてControllerが呼ばれることをテスト
+	email := "bearer@example.com"
+	user := signUpTestUser(t, email, "password1234")
+
+	_, tokenRes := issueTestToken(t, fmt.Sprintf(`{
+		"grant_type": "password",
+		"email": "%s",
+		"password": "password1234"
+	}`,
+		email,
+	))
+
+	_, rec := ExecController(
+		t,
+		"/auth/me",
+		ac.RequireLogin(ac.GetLoginUser),
+		Header("Authorization", "Bearer " + tokenRes.AccessToken),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, user.Id, toUserResponse(rec).Id)
+}
+
+func TestRequireLogin_WithInvalidBearerToken(t *testing.T) {
+	// 不正なアクセストークンの場合、401が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/words",
+		ac.RequireLogin(wc.GetAllWords),
+		Header("Authorization", "Bearer invalid-token"),
+		LoginUserId(0),
+	)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
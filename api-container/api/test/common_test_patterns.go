@@ -1,6 +1,7 @@
 package test
 
 import (
+	"api/controller"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -22,6 +23,10 @@ type CallControllerOption struct {
 	queryParamNames []string
 	queryParamValues [][]string
 	body string
+	loginUserId uint64
+	cookies []*http.Cookie
+	headerNames []string
+	headerValues []string
 }
 
 // CallControllerOptionを破壊的に変更するメソッド
@@ -63,6 +68,27 @@ func Body(body string) CallControllerOptionBuildFunc {
 	}
 }
 
+func LoginUserId(loginUserId uint64) CallControllerOptionBuildFunc {
+	// ログイン中のUserを指定する
+	// 0を指定した場合、ログインしていない状態で呼び出す
+	return func(opt *CallControllerOption) {
+		opt.loginUserId = loginUserId
+	}
+}
+
+func Header(name, value string) CallControllerOptionBuildFunc {
+	return func(opt *CallControllerOption) {
+		opt.headerNames = append(opt.headerNames, name)
+		opt.headerValues = append(opt.headerValues, value)
+	}
+}
+
+func Cookies(cookies []*http.Cookie) CallControllerOptionBuildFunc {
+	return func(opt *CallControllerOption) {
+		opt.cookies = cookies
+	}
+}
+
 
 func DoSimpleTest(
 	t *testing.T,
@@ -96,9 +122,11 @@ func ExecController(
 ) {
 	// 返り値の検証をせず、Controllerの呼び出しのみを実行
 	e := echo.New()
+	e.Validator = controller.NewRequestValidator()
 
 	option := CallControllerOption{
 		httpMethod: http.MethodGet, // HTTPメソッドが指定されていなければGETを使用
+		loginUserId: 1, // ログインUserが指定されていなければuser_id=1でログインしているとする
 	}
 
 	// 引数で指定されたオプションをoptionに反映
@@ -129,14 +157,31 @@ func ExecController(
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	}
 
+	for i := 0; i < len(option.headerNames); i++ {
+		req.Header.Set(option.headerNames[i], option.headerValues[i])
+	}
+
+	for _, cookie := range option.cookies {
+		req.AddCookie(cookie)
+	}
+
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
+	if option.loginUserId != 0 {
+		controller.SetLoginUserId(c, option.loginUserId)
+	}
+
 	c.SetPath(path)
 	if option.paramNames != nil && option.paramValues != nil {
 		c.SetParamNames(option.paramNames...)
 		c.SetParamValues(option.paramValues...)
 	}
 	
-	return assert.NoError(t, controllerMethod(c)), rec
+	// サーバーと同様に、返されたエラーは共通の形式のレスポンスにする
+	if err := controllerMethod(c); err != nil {
+		controller.HTTPErrorHandler(err, c)
+	}
+
+	return true, rec
 }
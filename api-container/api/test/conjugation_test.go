@@ -0,0 +1,139 @@
+package test
+
+import (
+	"api/model"
+	"api/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuessWordClass(t *testing.T) {
+	// 語尾から活用の種類を推定できることをテスト
+	tests := map[string]usecase.WordClass{
+		"買う":   usecase.WordClassGodanVerb,
+		"泳ぐ":   usecase.WordClassGodanVerb,
+		"死ぬ":   usecase.WordClassGodanVerb,
+		"遊ぶ":   usecase.WordClassGodanVerb,
+		"走る":   usecase.WordClassGodanVerb,
+		"帰る":   usecase.WordClassGodanVerb,
+		"食べる":  usecase.WordClassIchidanVerb,
+		"起きる":  usecase.WordClassIchidanVerb,
+		"見る":   usecase.WordClassIchidanVerb,
+		"勉強する": usecase.WordClassSuruVerb,
+		"来る":   usecase.WordClassKuruVerb,
+		"赤い":   usecase.WordClassIAdjective,
+		"静かだ":  usecase.WordClassNaAdjective,
+		"きれい":  usecase.WordClassNaAdjective,
+		"りんご":  usecase.WordClassUnknown,
+		// 仮名のみの語は、一段動詞の語尾か例外として登録された動詞でなければ動詞と推定しない
+		"しめる":   usecase.WordClassIchidanVerb,
+		"はいる":   usecase.WordClassGodanVerb,
+		"ありがとう": usecase.WordClassUnknown,
+		"おはよう":  usecase.WordClassUnknown,
+		"かう":    usecase.WordClassUnknown,
+		"さる":    usecase.WordClassUnknown,
+		// 漢字で始まらない語や、漢字の後に送り仮名以外が続く語も推定しない
+		"お疲れ様でございます": usecase.WordClassUnknown,
+		"東京タワー":      usecase.WordClassUnknown,
+		"落ち着く":       usecase.WordClassGodanVerb,
+		"終わる":        usecase.WordClassGodanVerb,
+		// 形容詞も、漢字の語幹を持つ語か、仮名のみで書かれることが多い語に限る
+		"まだ":  usecase.WordClassUnknown,
+		"ただ":  usecase.WordClassUnknown,
+		"はい":  usecase.WordClassUnknown,
+		"あい":  usecase.WordClassUnknown,
+		"お願い": usecase.WordClassUnknown,
+		"いい":  usecase.WordClassIAdjective,
+		"有名だ": usecase.WordClassNaAdjective,
+	}
+
+	for word, expected := range tests {
+		assert.Equal(t, expected, usecase.GuessWordClass(word), word)
+	}
+}
+
+func TestWordClassOf(t *testing.T) {
+	// 品詞が指定された場合は語尾からの推定より優先され、品詞と語尾が合わない場合は活用しないことをテスト
+	tests := []struct {
+		word         string
+		partOfSpeech string
+		expected     usecase.WordClass
+	}{
+		// 「湿る」の意味の「しめる」は、語尾からは一段動詞と推定される
+		{"しめる", "", usecase.WordClassIchidanVerb},
+		{"しめる", model.PartOfSpeechGodanVerb, usecase.WordClassGodanVerb},
+		{"きれい", "", usecase.WordClassNaAdjective},
+		{"きれい", model.PartOfSpeechNoun, usecase.WordClassUnknown},
+		{"赤い", model.PartOfSpeechIAdjective, usecase.WordClassIAdjective},
+		{"食べる", model.PartOfSpeechGodanVerb, usecase.WordClassGodanVerb},
+		{"りんご", model.PartOfSpeechGodanVerb, usecase.WordClassUnknown},
+		// 品詞が未入力の場合、仮名のみの語はウ段で終わっても五段動詞と推定しない
+		{"ありがとう", "", usecase.WordClassUnknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, usecase.WordClassOf(test.word, test.partOfSpeech), test.word)
+	}
+}
+
+func TestCountMorae(t *testing.T) {
+	// 拗音は直前の仮名と合わせて1モーラ、促音、撥音、長音はそれぞれ1モーラとして数えることをテスト
+	tests := map[string]int{
+		"とうきょう": 4,
+		"トーキョー": 4,
+		"がっこう":  4,
+		"しんぶん":  4,
+		"ちゃ":    1,
+		"":      0,
+	}
+
+	for reading, expected := range tests {
+		assert.Equal(t, expected, usecase.CountMorae(reading), reading)
+	}
+}
+
+func TestConjugate(t *testing.T) {
+	// 活用の種類ごとに、活用形を作成できることをテスト
+	tests := map[string][]string{
+		"買う":   {"買わない", "買った", "買って", "買います", "買える", "買おう"},
+		"泳ぐ":   {"泳がない", "泳いだ", "泳いで", "泳ぎます", "泳げる", "泳ごう"},
+		"死ぬ":   {"死なない", "死んだ", "死んで", "死にます", "死ねる", "死のう"},
+		"行く":   {"行かない", "行った", "行って", "行きます", "行ける", "行こう"},
+		"食べる":  {"食べない", "食べた", "食べて", "食べます", "食べられる", "食べよう"},
+		"勉強する": {"勉強しない", "勉強した", "勉強して", "勉強します", "勉強できる", "勉強しよう"},
+		"くる":   {"こない", "きた", "きて", "きます", "こられる", "こよう"},
+		"赤い":   {"赤くない", "赤かった", "赤くて", "赤く", "赤ければ"},
+		"いい":   {"よくない", "よかった", "よくて"},
+		"静かだ":  {"静か", "静かな", "静かに", "静かだった", "静かじゃない"},
+	}
+
+	for word, expectedTexts := range tests {
+		var texts []string
+		for _, conjugation := range usecase.Conjugate(word, usecase.GuessWordClass(word)) {
+			texts = append(texts, conjugation.Text)
+		}
+
+		for _, expectedText := range expectedTexts {
+			assert.Contains(t, texts, expectedText, word)
+		}
+
+		// word自身は含まない
+		assert.NotContains(t, texts, word, word)
+	}
+
+	// 語幹だけの形は作成しない
+	for _, conjugation := range usecase.Conjugate("走る", usecase.WordClassGodanVerb) {
+		assert.NotEqual(t, "走", conjugation.Text)
+	}
+
+	// 形容詞と推定しない語は、活用形を作成しない
+	for _, word := range []string{"まだ", "ただ", "はい", "お願い"} {
+		assert.Empty(t, usecase.Conjugate(word, usecase.WordClassOf(word, "")), word)
+	}
+
+	// 形容動詞として活用させる場合も、仮名のみの語幹だけの形は作成しない
+	for _, conjugation := range usecase.Conjugate("まだ", usecase.WordClassNaAdjective) {
+		assert.NotEqual(t, "ま", conjugation.Text)
+	}
+}
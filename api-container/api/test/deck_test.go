@@ -0,0 +1,301 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addTestWordToDeck(t *testing.T, deckId, wordId uint64) {
+	_, rec := ExecController(
+		t,
+		"/decks/:deckId/words/:wordId",
+		dc.AddWordToDeck,
+		Params(
+			[]string{"deckId", "wordId"},
+			[]string{strconv.FormatUint(deckId, 10), strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+	)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func addTestSentenceToDeck(t *testing.T, deckId, sentenceId uint64) {
+	_, rec := ExecController(
+		t,
+		"/decks/:deckId/sentences/:sentenceId",
+		dc.AddSentenceToDeck,
+		Params(
+			[]string{"deckId", "sentenceId"},
+			[]string{strconv.FormatUint(deckId, 10), strconv.FormatUint(sentenceId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+	)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestCreateDeck_WithParent(t *testing.T) {
+	// 親のデッキを指定してデッキを追加できることをテスト
+	DeleteAllFromDecks()
+
+	parent := createTestDeck(t, `{"name": "JLPT"}`)
+
+	_, rec := ExecController(
+		t,
+		"/decks",
+		dc.CreateDeck,
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, parent.Id)),
+	)
+	child := toDeckResponse(rec)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"name": "N3",
+			"user_id": 1,
+			"parent_id": %d,
+			"scope_association": false
+		}`,
+		child.Id,
+		parent.Id,
+	)
+	assert.JSONEq(t, expectedResponse, rec.Body.String())
+}
+
+func TestCreateDeck_Duplicate(t *testing.T) {
+	// 同じ親の下に同じ名前のデッキは追加できず、異なる親の下には追加できることをテスト
+	DeleteAllFromDecks()
+
+	existing := createTestDeck(t, `{"name": "N3"}`)
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"code": "conflict",
+			"message": "deck already exists",
+			"details": {"existing_id": %d},
+			"request_id": ""
+		}`,
+		existing.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/decks",
+		dc.CreateDeck,
+		http.StatusConflict,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(`{"name": "N3"}`),
+	)
+
+	parent := createTestDeck(t, `{"name": "JLPT"}`)
+
+	_, rec := ExecController(
+		t,
+		"/decks",
+		dc.CreateDeck,
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, parent.Id)),
+	)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestCreateDeck_WithInvalidParent(t *testing.T) {
+	// 他のUserのデッキは親に指定できないことをテスト
+	DeleteAllFromDecks()
+
+	var otherDeckId uint64
+	db.QueryRow(`
+		INSERT INTO decks
+		(id, name, user_id)
+		VALUES(nextval('deck_id_seq'), 'other', 2)
+		RETURNING id;
+	`).Scan(&otherDeckId)
+
+	DoSimpleTest(
+		t,
+		"/decks",
+		dc.CreateDeck,
+		http.StatusNotFound,
+		notFoundErrorJSON("parent deck"),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, otherDeckId)),
+	)
+}
+
+func TestUpdateDeck_WithDescendantParent(t *testing.T) {
+	// 子孫のデッキを親に指定すると、parent_idが循環するため422が返ることをテスト
+	DeleteAllFromDecks()
+
+	parent := createTestDeck(t, `{"name": "JLPT"}`)
+	child := createTestDeck(t, fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, parent.Id))
+
+	_, rec := ExecController(
+		t,
+		"/decks/:deckId",
+		dc.UpdateDeck,
+		Params(
+			[]string{"deckId"},
+			[]string{strconv.FormatUint(parent.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(fmt.Sprintf(`{"name": "JLPT", "parent_id": %d}`, child.Id)),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestDeleteDeck_WithChildren(t *testing.T) {
+	// デッキを削除すると子孫のデッキも削除され、含まれていたWordは削除されないことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromDecks()
+
+	parent := createTestDeck(t, `{"name": "JLPT"}`)
+	child := createTestDeck(t, fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, parent.Id))
+	word := createTestWord(t, "りんご", "")
+	addTestWordToDeck(t, child.Id, word.Id)
+
+	_, rec := ExecController(
+		t,
+		"/decks/:deckId",
+		dc.DeleteDeck,
+		Params(
+			[]string{"deckId"},
+			[]string{strconv.FormatUint(parent.Id, 10)},
+		),
+		HttpMethod(http.MethodDelete),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/decks/:deckId",
+		dc.GetDeckById,
+		http.StatusNotFound,
+		notFoundErrorJSON("deck"),
+		Params(
+			[]string{"deckId"},
+			[]string{strconv.FormatUint(child.Id, 10)},
+		),
+	)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE id = $1;
+	`,
+		word.Id,
+	).Scan(&count)
+	assert.Equal(t, 1, count)
+}
+
+func TestGetAllWords_WithDeckFilter(t *testing.T) {
+	// deck_idを指定した場合、子孫のデッキを含めたデッキのWordのみが返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromDecks()
+
+	parent := createTestDeck(t, `{"name": "JLPT"}`)
+	child := createTestDeck(t, fmt.Sprintf(`{"name": "N3", "parent_id": %d}`, parent.Id))
+	parentWord := createTestWord(t, "りんご", "")
+	childWord := createTestWord(t, "みかん", "")
+	createTestWord(t, "ぶどう", "")
+	addTestWordToDeck(t, parent.Id, parentWord.Id)
+	addTestWordToDeck(t, child.Id, childWord.Id)
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"deck_id"},
+			[][]string{{strconv.FormatUint(parent.Id, 10)}},
+		),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	wordPage := toWordPageResponse(rec)
+	assert.Equal(t, uint64(2), wordPage.TotalCount)
+	var wordIds []uint64
+	for _, word := range wordPage.Words {
+		wordIds = append(wordIds, word.Id)
+	}
+	assert.ElementsMatch(t, []uint64{parentWord.Id, childWord.Id}, wordIds)
+
+	_, rec = ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"deck_id"},
+			[][]string{{strconv.FormatUint(child.Id, 10)}},
+		),
+	)
+	assert.Equal(t, uint64(1), toWordPageResponse(rec).TotalCount)
+}
+
+func TestScopeAssociation(t *testing.T) {
+	// scope_associationが有効なデッキのSentenceは、同じデッキのWordのみと紐づき、
+	// 無効にすると全Wordと紐づきなおすことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromDecks()
+
+	apple := createTestWord(t, "りんご", "")
+	orange := createTestWord(t, "みかん", "")
+	sentence := createTestSentence(t, "りんごとみかん")
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, apple.Id))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, orange.Id))
+
+	deck := createTestDeck(t, `{"name": "fruits", "scope_association": true}`)
+	addTestSentenceToDeck(t, deck.Id, sentence.Id)
+
+	// デッキにWordが無いため、どのWordとも紐づかない
+	assert.Equal(t, 0, getCountFromSentencesWords(sentence.Id, apple.Id))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentence.Id, orange.Id))
+
+	addTestWordToDeck(t, deck.Id, apple.Id)
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, apple.Id))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentence.Id, orange.Id))
+
+	_, rec := ExecController(
+		t,
+		"/decks/:deckId",
+		dc.UpdateDeck,
+		Params(
+			[]string{"deckId"},
+			[]string{strconv.FormatUint(deck.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"name": "fruits", "scope_association": false}`),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, apple.Id))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, orange.Id))
+}
+
+func TestScopeAssociation_WithChildDeck(t *testing.T) {
+	// 親のデッキのscope_associationは、子のデッキのWordとSentenceにも適用されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromDecks()
+
+	apple := createTestWord(t, "りんご", "")
+	orange := createTestWord(t, "みかん", "")
+	sentence := createTestSentence(t, "りんごとみかん")
+
+	parent := createTestDeck(t, `{"name": "fruits", "scope_association": true}`)
+	child := createTestDeck(t, fmt.Sprintf(`{"name": "red", "parent_id": %d}`, parent.Id))
+	addTestWordToDeck(t, parent.Id, orange.Id)
+	addTestSentenceToDeck(t, child.Id, sentence.Id)
+
+	assert.Equal(t, 0, getCountFromSentencesWords(sentence.Id, apple.Id))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, orange.Id))
+}
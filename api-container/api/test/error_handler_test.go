@@ -0,0 +1,114 @@
+package test
+
+import (
+	"api/controller"
+	"api/usecase"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func execErrorHandler(err error, requestId string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, requestId)
+
+	controller.HTTPErrorHandler(err, c)
+
+	return rec
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	// エラーの種類ごとのHTTPステータスと、共通の形式のレスポンスが返ることをテスト
+	testCases := []struct {
+		err            error
+		expectedStatus int
+		expectedJSON   string
+	}{
+		{
+			err:            usecase.ErrWordNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedJSON:   `{"code": "not_found", "message": "word not found", "request_id": "req-1"}`,
+		},
+		{
+			err:            usecase.NewForbiddenError("not allowed"),
+			expectedStatus: http.StatusForbidden,
+			expectedJSON:   `{"code": "forbidden", "message": "not allowed", "request_id": "req-1"}`,
+		},
+		{
+			err:            usecase.NewValidationError("1 item(s) are invalid", []string{"word"}),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedJSON:   `{"code": "validation_failed", "message": "1 item(s) are invalid", "details": ["word"], "request_id": "req-1"}`,
+		},
+		{
+			err:            usecase.ErrEmailAlreadyUsed,
+			expectedStatus: http.StatusConflict,
+			expectedJSON:   `{"code": "conflict", "message": "email is already used", "request_id": "req-1"}`,
+		},
+		{
+			err:            usecase.ErrUnauthenticated,
+			expectedStatus: http.StatusUnauthorized,
+			expectedJSON:   `{"code": "unauthenticated", "message": "not logged in", "request_id": "req-1"}`,
+		},
+		{
+			err:            echo.NewHTTPError(http.StatusBadRequest, "wordId must be a positive integer"),
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `{"code": "bad_request", "message": "wordId must be a positive integer", "request_id": "req-1"}`,
+		},
+		{
+			err:            echo.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedJSON:   `{"code": "not_found", "message": "Not Found", "request_id": "req-1"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		rec := execErrorHandler(testCase.err, "req-1")
+
+		assert.Equal(t, testCase.expectedStatus, rec.Code)
+		assert.JSONEq(t, testCase.expectedJSON, rec.Body.String())
+	}
+}
+
+func TestHTTPErrorHandler_InternalError(t *testing.T) {
+	// DBのエラーなど内部のエラーは、内容を返さず500が返ることをテスト
+	rec := execErrorHandler(errors.New(`pq: relation "words" does not exist`), "req-2")
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(
+		t,
+		`{"code": "internal_error", "message": "internal server error", "request_id": "req-2"}`,
+		rec.Body.String(),
+	)
+}
+
+func TestHTTPErrorHandler_WithInvalidBody(t *testing.T) {
+	// リクエストボディやパスパラメータが不正な場合、400が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": `),
+	)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "bad_request", toErrorResponse(rec).Code)
+
+	_, rec = ExecController(
+		t,
+		"/words/:wordId",
+		wc.GetWordById,
+		Params(
+			[]string{"wordId"},
+			[]string{"abc"},
+		),
+	)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "wordId must be a positive integer", toErrorResponse(rec).Message)
+}
@@ -185,6 +185,7 @@ func toNotationResponse(rec *httptest.ResponseRecorder) model.NotationResponse {
 	id := fmt.Sprintf("%v", bodyMap["id"])
 	wordId := fmt.Sprintf("%v", bodyMap["word_id"])
 	notation := fmt.Sprintf("%v", bodyMap["notation"])
+	kind := fmt.Sprintf("%v", bodyMap["kind"])
 
 	intId, _ := strconv.ParseUint(id, 10, 32)
 	intWordId, _ := strconv.ParseUint(wordId, 10, 32)
@@ -193,6 +194,7 @@ func toNotationResponse(rec *httptest.ResponseRecorder) model.NotationResponse {
 		Id: intId,
 		WordId: intWordId,
 		Notation: notation,
+		Kind: kind,
 	}
 }
 
@@ -282,6 +284,35 @@ func insertIntoNotations(wordId uint64, notation string) uint64 {
 	return notationId
 }
 
+func insertIntoNotationsWithKind(wordId uint64, notation, kind string) uint64 {
+	var notationId uint64
+	db.QueryRow(`
+		INSERT INTO notations
+		(id, word_id, notation, kind)
+		VALUES(nextval('notation_id_seq'), $1, $2, $3)
+		RETURNING id;
+		`,
+		wordId,
+		notation,
+		kind,
+	).Scan(&notationId)
+
+	return notationId
+}
+
+func getKindFromNotations(notationId uint64) string {
+	var kind string
+
+	db.QueryRow(`
+		SELECT kind FROM notations
+		WHERE id = $1
+		`,
+		notationId,
+	).Scan(&kind)
+
+	return kind
+}
+
 func insertIntoSentences(sentence string, userId uint64) uint64 {
 	var sentenceId uint64
 	db.QueryRow(`
@@ -295,4 +326,268 @@ func insertIntoSentences(sentence string, userId uint64) uint64 {
 	).Scan(&sentenceId)
 
 	return sentenceId
-}
\ No newline at end of file
+}
+func DeleteAllFromJobs() {
+	// jobsテーブルのレコードを全件削除
+	db.Exec("TRUNCATE TABLE jobs;")
+	db.Exec("SELECT setval('job_id_seq', 1);")
+}
+
+func toJobResponse(rec *httptest.ResponseRecorder) model.JobResponse {
+	var jobRes model.JobResponse
+	json.Unmarshal(rec.Body.Bytes(), &jobRes)
+	return jobRes
+}
+
+func DeleteAllFromSessions() {
+	db.Exec("TRUNCATE TABLE sessions;")
+}
+
+func deleteUserByEmail(email string) {
+	// テストで作成したUserを削除
+	db.Exec(`
+		DELETE FROM users
+		WHERE email = $1;
+		`,
+		email,
+	)
+}
+
+func getCountFromSessionsByUserId(userId uint64) int {
+	var count int
+
+	db.QueryRow(`
+		SELECT COUNT(*) FROM sessions
+		WHERE user_id = $1;
+		`,
+		userId,
+	).Scan(&count)
+
+	return count
+}
+
+func toUserResponse(rec *httptest.ResponseRecorder) model.UserResponse {
+	bodyMap := toMap(rec)
+	id := fmt.Sprintf("%v", bodyMap["id"])
+	email := fmt.Sprintf("%v", bodyMap["email"])
+
+	intId, _ := strconv.ParseUint(id, 10, 32)
+
+	return model.UserResponse{
+		Id: intId,
+		Email: email,
+	}
+}
+
+func signUpTestUser(t *testing.T, email, password string) model.UserResponse {
+	// SignUpを呼び出す
+	// 他メソッドのテスト用データを作る用途で使用
+	deleteUserByEmail(email)
+
+	body := fmt.Sprintf(`
+			{
+				"email": "%s",
+				"password": "%s"
+			}
+		`,
+		email,
+		password,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/signup",
+		ac.SignUp,
+		HttpMethod(http.MethodPost),
+		Body(body),
+		LoginUserId(0),
+	)
+
+	return toUserResponse(rec)
+}
+
+func logInTestUser(t *testing.T, email, password string) []*http.Cookie {
+	// LogInを呼び出し、発行されたCookieを返す
+	body := fmt.Sprintf(`
+			{
+				"email": "%s",
+				"password": "%s"
+			}
+		`,
+		email,
+		password,
+	)
+
+	_, rec := ExecController(
+		t,
+		"/auth/login",
+		ac.LogIn,
+		HttpMethod(http.MethodPost),
+		Body(body),
+		LoginUserId(0),
+	)
+
+	return rec.Result().Cookies()
+}
+
+func toTokenResponse(rec *httptest.ResponseRecorder) model.TokenResponse {
+	bodyMap := toMap(rec)
+	expiresIn, _ := strconv.ParseUint(fmt.Sprintf("%v", bodyMap["expires_in"]), 10, 64)
+
+	return model.TokenResponse{
+		AccessToken: fmt.Sprintf("%v", bodyMap["access_token"]),
+		TokenType: fmt.Sprintf("%v", bodyMap["token_type"]),
+		ExpiresIn: expiresIn,
+		RefreshToken: fmt.Sprintf("%v", bodyMap["refresh_token"]),
+	}
+}
+
+func issueTestToken(t *testing.T, body string) (int, model.TokenResponse) {
+	// IssueTokenを呼び出し、ステータスコードと発行されたトークンを返す
+	_, rec := ExecController(
+		t,
+		"/auth/token",
+		ac.IssueToken,
+		HttpMethod(http.MethodPost),
+		Body(body),
+		LoginUserId(0),
+	)
+
+	return rec.Code, toTokenResponse(rec)
+}
+
+func getCountFromValidRefreshTokensByUserId(userId uint64) int {
+	var count int
+
+	db.QueryRow(`
+		SELECT COUNT(*) FROM refresh_tokens
+		WHERE user_id = $1
+			AND revoked_at IS NULL;
+		`,
+		userId,
+	).Scan(&count)
+
+	return count
+}
+
+func toWordPageResponse(rec *httptest.ResponseRecorder) model.WordPageResponse {
+	var wordPageRes model.WordPageResponse
+	json.Unmarshal(rec.Body.Bytes(), &wordPageRes)
+	return wordPageRes
+}
+
+func toErrorResponse(rec *httptest.ResponseRecorder) model.ErrorResponse {
+	var errRes model.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &errRes)
+	return errRes
+}
+
+func notFoundErrorJSON(resource string) string {
+	// ログイン中のUserに紐づくリソースが無い場合のエラーレスポンス
+	// テストではX-Request-Idを付与するミドルウェアを通らないため、request_idは空文字列となる
+	return fmt.Sprintf(`{"code": "not_found", "message": "%s not found", "request_id": ""}`, resource)
+}
+
+func DeleteAllFromTags() {
+	// tagsテーブルのレコードを全件削除
+	// words_tags、sentences_tagsのレコードもCASCADEで削除される
+	db.Exec("TRUNCATE TABLE tags CASCADE;")
+	db.Exec("SELECT setval('tag_id_seq', 1);")
+}
+
+func toTagResponse(rec *httptest.ResponseRecorder) model.TagResponse {
+	var tagRes model.TagResponse
+	json.Unmarshal(rec.Body.Bytes(), &tagRes)
+	return tagRes
+}
+
+func createTestTag(t *testing.T, name string) model.TagResponse {
+	// CreateTagを呼び出す
+	// 他メソッドのテスト用データを作る用途で使用
+	_, rec := ExecController(
+		t,
+		"/tags",
+		tc.CreateTag,
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"name": "%s"}`, name)),
+	)
+
+	return toTagResponse(rec)
+}
+
+func DeleteAllFromDecks() {
+	// decksテーブルのレコードを全件削除
+	// decks_words、decks_sentencesのレコードもCASCADEで削除される
+	db.Exec("TRUNCATE TABLE decks CASCADE;")
+	db.Exec("SELECT setval('deck_id_seq', 1);")
+}
+
+func toDeckResponse(rec *httptest.ResponseRecorder) model.DeckResponse {
+	var deckRes model.DeckResponse
+	json.Unmarshal(rec.Body.Bytes(), &deckRes)
+	return deckRes
+}
+
+func createTestDeck(t *testing.T, body string) model.DeckResponse {
+	// CreateDeckを呼び出す
+	// 他メソッドのテスト用データを作る用途で使用
+	_, rec := ExecController(
+		t,
+		"/decks",
+		dc.CreateDeck,
+		HttpMethod(http.MethodPost),
+		Body(body),
+	)
+
+	return toDeckResponse(rec)
+}
+
+func toWordSenseResponse(rec *httptest.ResponseRecorder) model.WordSenseResponse {
+	var senseRes model.WordSenseResponse
+	json.Unmarshal(rec.Body.Bytes(), &senseRes)
+	return senseRes
+}
+
+func createTestWordSense(t *testing.T, wordId uint64, body string) model.WordSenseResponse {
+	// CreateSenseを呼び出す
+	// 他メソッドのテスト用データを作る用途で使用
+	// word_sensesのレコードは、WordとともにCASCADEで削除される
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/senses",
+		wsc.CreateSense,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(body),
+	)
+
+	return toWordSenseResponse(rec)
+}
+
+func toWordRelationResponse(rec *httptest.ResponseRecorder) model.WordRelationResponse {
+	var relationRes model.WordRelationResponse
+	json.Unmarshal(rec.Body.Bytes(), &relationRes)
+	return relationRes
+}
+
+func createTestWordRelation(t *testing.T, wordId uint64, body string) model.WordRelationResponse {
+	// CreateRelationを呼び出す
+	// 他メソッドのテスト用データを作る用途で使用
+	// word_relationsのレコードは、WordとともにCASCADEで削除される
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/relations",
+		wrc.CreateRelation,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(body),
+	)
+
+	return toWordRelationResponse(rec)
+}
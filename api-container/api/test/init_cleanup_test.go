@@ -33,6 +33,53 @@ var sc controller.ISentenceController
 var nr repository.INotationRepository
 var nc controller.INotationController
 
+// Search
+var seu *usecase.SearchUsecase
+var sec controller.ISearchController
+
+// Review
+var rvr repository.IReviewRepository
+var rvu *usecase.ReviewUsecase
+var rvc controller.IReviewController
+
+// User, Session
+var ur repository.IUserRepository
+var ssr repository.ISessionRepository
+var rtr repository.IRefreshTokenRepository
+var atu *usecase.AuthUsecase
+var ac controller.IAuthController
+
+// UnitOfWork
+var uow repository.IUnitOfWork
+
+// Job
+var jr repository.IJobRepository
+
+// Tag
+var tr repository.ITagRepository
+var tu *usecase.TagUsecase
+var tc controller.ITagController
+
+// Deck
+var dr repository.IDeckRepository
+var du *usecase.DeckUsecase
+var dc controller.IDeckController
+
+// WordSense
+var wsr repository.IWordSenseRepository
+var wsu *usecase.WordSenseUsecase
+var wsc controller.IWordSenseController
+
+// WordRelation
+var wrr repository.IWordRelationRepository
+var wru *usecase.WordRelationUsecase
+var wrc controller.IWordRelationController
+
+// Matcher
+// 既存のテストは部分文字列での紐づけを前提とする
+var matcher usecase.IMatcher = usecase.NewSubstringMatcher()
+var linkResolver = usecase.NewLinkResolver(usecase.DefaultTieBreakRules)
+
 func TestMain(m *testing.M) {
 	db = setupDB()
 
@@ -41,16 +88,41 @@ func TestMain(m *testing.M) {
 	sr = repository.NewSentenceRepository(db)
 	swr = repository.NewSentencesWordsRepository(db)
 	nr = repository.NewNotationRepository(db)
+	ur = repository.NewUserRepository(db)
+	ssr = repository.NewSessionRepository(db)
+	rtr = repository.NewRefreshTokenRepository(db)
+	rvr = repository.NewReviewRepository(db)
+	uow = repository.NewUnitOfWork(db)
+	jr = repository.NewJobRepository(db)
+	tr = repository.NewTagRepository(db)
+	dr = repository.NewDeckRepository(db)
+	wsr = repository.NewWordSenseRepository(db)
+	wrr = repository.NewWordRelationRepository(db)
 
 	// Usecase
-	wu = usecase.NewWordUsecase(wr, sr, swr, nr)
-	su = usecase.NewSentenceUsecase(sr, wr, swr, nr)
-	au = usecase.NewAssociationUsecase(wr, sr, swr, nr)
+	// 既存のテストはリクエスト内での紐づけを前提とする
+	wu = usecase.NewWordUsecase(wr, sr, swr, nr, tr, dr, wsr, wrr, jr, uow, matcher, linkResolver, usecase.AssociationModeSync)
+	su = usecase.NewSentenceUsecase(sr, wr, swr, nr, dr, jr, uow, matcher, linkResolver, usecase.AssociationModeSync)
+	au = usecase.NewAssociationUsecase(wr, sr, swr, nr, dr, uow, matcher, linkResolver)
+	seu = usecase.NewSearchUsecase(wr, sr, nr, au)
+	rvu = usecase.NewReviewUsecase(rvr, wr, swr, uow, usecase.NewSM2Scheduler())
+	atu = usecase.NewAuthUsecase(ur, ssr, rtr, []byte("test-jwt-secret"))
+	tu = usecase.NewTagUsecase(tr, wr, sr)
+	du = usecase.NewDeckUsecase(dr, wr, sr, uow, wu, su)
+	wsu = usecase.NewWordSenseUsecase(wsr, wr, sr)
+	wru = usecase.NewWordRelationUsecase(wrr, wr, nr)
 
 	// Controller
 	wc = controller.NewWordController(wu, au)
 	sc = controller.NewSentenceController(su, au)
 	nc = controller.NewNotationController(wu)
+	ac = controller.NewAuthController(atu)
+	sec = controller.NewSearchController(seu)
+	rvc = controller.NewReviewController(rvu)
+	tc = controller.NewTagController(tu)
+	dc = controller.NewDeckController(du)
+	wsc = controller.NewWordSenseController(wsu)
+	wrc = controller.NewWordRelationController(wru)
 
 	setupUserData()
 
@@ -87,4 +159,7 @@ func setupUserData() {
 				WHERE id = CAST($1 AS INTEGER)
 			);`, i)
 	}
+
+	// idを指定して追加したため、user_id_seqを既存のidの最大値まで進めておく
+	db.Exec("SELECT setval('user_id_seq', (SELECT MAX(id) FROM users));")
 }
\ No newline at end of file
@@ -0,0 +1,330 @@
+package test
+
+import (
+	"api/controller"
+	"api/model"
+	"api/usecase"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type asyncAssociationTestSet struct {
+	wc controller.IWordController
+	sc controller.ISentenceController
+	jc controller.IJobController
+	ju *usecase.JobUsecase
+}
+
+func newAsyncAssociationTestSet() asyncAssociationTestSet {
+	// 紐づけの再構築をジョブとして行うUsecase、Controllerを作成
+	asyncWu := usecase.NewWordUsecase(wr, sr, swr, nr, tr, dr, wsr, wrr, jr, uow, matcher, linkResolver, usecase.AssociationModeAsync)
+	asyncSu := usecase.NewSentenceUsecase(sr, wr, swr, nr, dr, jr, uow, matcher, linkResolver, usecase.AssociationModeAsync)
+	ju := usecase.NewJobUsecase(jr, asyncWu, asyncSu)
+
+	return asyncAssociationTestSet{
+		wc: controller.NewWordController(asyncWu, au),
+		sc: controller.NewSentenceController(asyncSu, au),
+		jc: controller.NewJobController(ju),
+		ju: ju,
+	}
+}
+
+func getTestJob(t *testing.T, jc controller.IJobController, jobId uint64) model.JobResponse {
+	_, rec := ExecController(
+		t,
+		"/jobs/:jobId",
+		jc.GetJobById,
+		Params(
+			[]string{"jobId"},
+			[]string{strconv.FormatUint(jobId, 10)},
+		),
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	return toJobResponse(rec)
+}
+
+func TestCreateWord_AsyncAssociation(t *testing.T) {
+	// Word作成時、紐づけはジョブとして行われ、ジョブの完了までassociation_pendingが返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+	sentenceId := insertIntoSentences("りんごを食べた", 1)
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		ts.wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "りんご", "memo": ""}`),
+	)
+	wordRes := toWordResponse(rec)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.True(t, wordRes.AssociationPending)
+	assert.NotZero(t, wordRes.AssociationJobId)
+
+	// ジョブの実行前は紐づけられていない
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordRes.Id))
+
+	jobRes := getTestJob(t, ts.jc, wordRes.AssociationJobId)
+	assert.Equal(t, model.JobKindReassociateWords, jobRes.Kind)
+	assert.Equal(t, []uint64{wordRes.Id}, jobRes.TargetIds)
+	assert.Equal(t, model.JobStatusPending, jobRes.Status)
+
+	// 取得時も、ジョブの完了まではassociation_pendingが返る
+	_, rec = ExecController(
+		t,
+		"/words/:wordId",
+		ts.wc.GetWordById,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordRes.Id, 10)},
+		),
+	)
+	assert.True(t, toWordResponse(rec).AssociationPending)
+
+	processed, err := ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	jobRes = getTestJob(t, ts.jc, wordRes.AssociationJobId)
+	assert.Equal(t, model.JobStatusSucceeded, jobRes.Status)
+	assert.Equal(t, uint64(1), jobRes.Attempts)
+	assert.NotNil(t, jobRes.FinishedAt)
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordRes.Id))
+
+	_, rec = ExecController(
+		t,
+		"/words/:wordId",
+		ts.wc.GetWordById,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordRes.Id, 10)},
+		),
+	)
+	assert.False(t, toWordResponse(rec).AssociationPending)
+
+	// 実行待ちのジョブが無い場合は何もしない
+	processed, err = ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestCreateMultipleWords_AsyncAssociationWithDuplicate(t *testing.T) {
+	// 複数のWordの作成時、重複して作成されなかった既存のWordには、ジョブのIdが返らないことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+	existingWordId := insertIntoWords("食べる", "", 1)
+
+	_, rec := ExecController(
+		t,
+		"/words/multiple",
+		ts.wc.CreateMultipleWords,
+		HttpMethod(http.MethodPost),
+		Body(`{"words": [{"word": "飲む", "memo": ""}, {"word": "食べる", "memo": ""}]}`),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"skip"}},
+		),
+	)
+	var wordResponses []model.WordResponse
+	json.Unmarshal(rec.Body.Bytes(), &wordResponses)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, len(wordResponses))
+
+	createdWordRes := wordResponses[0]
+	assert.True(t, createdWordRes.AssociationPending)
+	assert.NotZero(t, createdWordRes.AssociationJobId)
+	assert.Equal(t, []uint64{createdWordRes.Id}, getTestJob(t, ts.jc, createdWordRes.AssociationJobId).TargetIds)
+
+	existingWordRes := wordResponses[1]
+	assert.Equal(t, existingWordId, existingWordRes.Id)
+	assert.False(t, existingWordRes.AssociationPending)
+	assert.Zero(t, existingWordRes.AssociationJobId)
+}
+
+func TestCreateSentence_AsyncAssociation(t *testing.T) {
+	// Sentence作成時、紐づけはジョブとして行われることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+	wordId := insertIntoWords("りんご", "", 1)
+
+	_, rec := ExecController(
+		t,
+		"/sentences",
+		ts.sc.CreateSentence,
+		HttpMethod(http.MethodPost),
+		Body(`{"sentence": "りんごを食べた"}`),
+	)
+	sentenceRes := toSentenceResponse(rec)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.True(t, sentenceRes.AssociationPending)
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceRes.Id, wordId))
+
+	processed, err := ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	assert.Equal(t, model.JobStatusSucceeded, getTestJob(t, ts.jc, sentenceRes.AssociationJobId).Status)
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceRes.Id, wordId))
+}
+
+func TestProcessNextJob_DeletedTarget(t *testing.T) {
+	// ジョブの実行前に対象のWordが削除された場合も、ジョブが成功することをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+	wordRes := createTestWord(t, "りんご", "")
+
+	job, err := jr.InsertJob(model.JobCreation{
+		UserId:      1,
+		Kind:        model.JobKindReassociateWords,
+		TargetIds:   []uint64{wordRes.Id},
+		MaxAttempts: 1,
+	})
+	assert.NoError(t, err)
+
+	DeleteAllFromWords()
+
+	processed, err := ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, model.JobStatusSucceeded, getTestJob(t, ts.jc, job.Id).Status)
+}
+
+func TestProcessNextJob_DeadLetter(t *testing.T) {
+	// 最大試行回数に達するまで失敗したジョブが、deadとなり再度実行されないことをテスト
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+
+	job, err := jr.InsertJob(model.JobCreation{
+		UserId:      1,
+		Kind:        "unknown",
+		TargetIds:   []uint64{1},
+		MaxAttempts: 1,
+	})
+	assert.NoError(t, err)
+
+	processed, err := ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	jobRes := getTestJob(t, ts.jc, job.Id)
+	assert.Equal(t, model.JobStatusDead, jobRes.Status)
+	// 失敗の原因は返さず、固定のメッセージを返す
+	assert.Equal(t, "association failed", jobRes.LastError)
+
+	processed, err = ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestProcessNextJob_StaleRunningJob(t *testing.T) {
+	// 実行中のままロックの期限を過ぎたジョブのうち、
+	// 試行回数が残っているものは再度実行され、最大試行回数に達したものはdeadとなることをテスト
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+
+	staleJob, err := jr.InsertJob(model.JobCreation{
+		UserId:      1,
+		Kind:        model.JobKindReassociateWords,
+		TargetIds:   []uint64{},
+		MaxAttempts: 2,
+	})
+	assert.NoError(t, err)
+	exhaustedJob, err := jr.InsertJob(model.JobCreation{
+		UserId:      1,
+		Kind:        model.JobKindReassociateWords,
+		TargetIds:   []uint64{},
+		MaxAttempts: 2,
+	})
+	assert.NoError(t, err)
+
+	db.Exec(`
+		UPDATE jobs
+		SET status = 'running',
+			attempts = CASE WHEN id = $1 THEN 1 ELSE 2 END,
+			locked_at = CURRENT_TIMESTAMP - INTERVAL '1 day'
+		WHERE id IN ($1, $2);
+	`,
+		staleJob.Id,
+		exhaustedJob.Id,
+	)
+
+	processed, err := ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	staleJobRes := getTestJob(t, ts.jc, staleJob.Id)
+	assert.Equal(t, model.JobStatusSucceeded, staleJobRes.Status)
+	assert.Equal(t, uint64(2), staleJobRes.Attempts)
+
+	exhaustedJobRes := getTestJob(t, ts.jc, exhaustedJob.Id)
+	assert.Equal(t, model.JobStatusDead, exhaustedJobRes.Status)
+	assert.Equal(t, uint64(2), exhaustedJobRes.Attempts)
+	assert.Equal(t, "job timed out", exhaustedJobRes.LastError)
+
+	processed, err = ts.ju.ProcessNextJob()
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestGetJobById_OtherUser(t *testing.T) {
+	// 他のUserのジョブは取得できないことをテスト
+	DeleteAllFromJobs()
+
+	ts := newAsyncAssociationTestSet()
+
+	job, err := jr.InsertJob(model.JobCreation{
+		UserId:      2,
+		Kind:        model.JobKindReassociateWords,
+		TargetIds:   []uint64{1},
+		MaxAttempts: 1,
+	})
+	assert.NoError(t, err)
+
+	DoSimpleTest(
+		t,
+		"/jobs/:jobId",
+		ts.jc.GetJobById,
+		http.StatusNotFound,
+		notFoundErrorJSON("job"),
+		Params(
+			[]string{"jobId"},
+			[]string{strconv.FormatUint(job.Id, 10)},
+		),
+	)
+}
+
+func TestParseAssociationMode(t *testing.T) {
+	// 設定値からAssociationModeを作成できることをテスト
+	mode, err := usecase.ParseAssociationMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.AssociationModeAsync, mode)
+
+	mode, err = usecase.ParseAssociationMode("sync")
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.AssociationModeSync, mode)
+
+	_, err = usecase.ParseAssociationMode("later")
+	assert.Error(t, err)
+}
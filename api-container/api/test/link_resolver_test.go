@@ -0,0 +1,106 @@
+package test
+
+import (
+	"api/model"
+	"api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// データベースを使用しないテスト
+
+func newTestLinkCandidate(start, end int, wordId uint64, notationKind string, createdAt time.Time) usecase.LinkCandidate {
+	return usecase.LinkCandidate{
+		Match:        usecase.Match{Start: start, End: end},
+		WordId:       wordId,
+		NotationKind: notationKind,
+		CreatedAt:    createdAt,
+	}
+}
+
+func TestLinkResolver_PrefersLongestMatch(t *testing.T) {
+	// 「日本語を話す」で「日本」と「日本語」が出現した場合、「日本語」が選ばれることをテスト
+	lr := usecase.NewLinkResolver(usecase.DefaultTieBreakRules)
+	now := time.Now()
+
+	japan := newTestLinkCandidate(0, 2, 1, "", now)
+	japanese := newTestLinkCandidate(0, 3, 2, "", now)
+	talk := newTestLinkCandidate(4, 6, 3, "", now)
+
+	// 候補の順序によらず同じ結果になる
+	for _, candidates := range [][]usecase.LinkCandidate{
+		{japan, japanese, talk},
+		{talk, japanese, japan},
+	} {
+		resolution := lr.Resolve(candidates)
+
+		assert.Equal(t, []usecase.LinkCandidate{japanese, talk}, resolution.Selected)
+		assert.Equal(t, 1, len(resolution.Discarded))
+		assert.Equal(t, japan, resolution.Discarded[0].LinkCandidate)
+		assert.Equal(t, usecase.DiscardReasonOverlap, resolution.Discarded[0].Reason)
+		assert.Equal(t, japanese, resolution.Discarded[0].Winner)
+	}
+}
+
+func TestLinkResolver_DiscardsOverlapFromEarlierMatch(t *testing.T) {
+	// 前から始まる候補と重なり合う候補は、より長くても選ばれないことをテスト
+	lr := usecase.NewLinkResolver(usecase.DefaultTieBreakRules)
+	now := time.Now()
+
+	first := newTestLinkCandidate(0, 2, 1, "", now)
+	second := newTestLinkCandidate(1, 5, 2, "", now)
+
+	resolution := lr.Resolve([]usecase.LinkCandidate{second, first})
+
+	assert.Equal(t, []usecase.LinkCandidate{first}, resolution.Selected)
+	assert.Equal(t, usecase.DiscardReasonOverlap, resolution.Discarded[0].Reason)
+}
+
+func TestLinkResolver_TieBreakByNotationKind(t *testing.T) {
+	// 同じ範囲では、ユーザーが入力したNotationが自動生成されたNotationより優先されることをテスト
+	lr := usecase.NewLinkResolver(usecase.DefaultTieBreakRules)
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.AddDate(0, 1, 0)
+
+	// 自動生成されたNotationのWordの方が新しくても、notation_kindが先に比較される
+	stem := newTestLinkCandidate(0, 2, 1, model.NotationKindStem, newer)
+	manual := newTestLinkCandidate(0, 2, 2, model.NotationKindManual, older)
+
+	resolution := lr.Resolve([]usecase.LinkCandidate{stem, manual})
+
+	assert.Equal(t, []usecase.LinkCandidate{manual}, resolution.Selected)
+	assert.Equal(t, stem, resolution.Discarded[0].LinkCandidate)
+	assert.Equal(t, usecase.DiscardReasonTieBreak, resolution.Discarded[0].Reason)
+}
+
+func TestLinkResolver_TieBreakByCreatedAt(t *testing.T) {
+	// 同じ範囲、同じ作成元の場合、規則に応じて新しいWordまたは古いWordが優先されることをテスト
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.AddDate(0, 1, 0)
+
+	olderCandidate := newTestLinkCandidate(0, 2, 1, "", older)
+	newerCandidate := newTestLinkCandidate(0, 2, 2, "", newer)
+	candidates := []usecase.LinkCandidate{olderCandidate, newerCandidate}
+
+	resolution := usecase.NewLinkResolver([]usecase.TieBreakRule{usecase.TieBreakNewer}).Resolve(candidates)
+	assert.Equal(t, []usecase.LinkCandidate{newerCandidate}, resolution.Selected)
+
+	resolution = usecase.NewLinkResolver([]usecase.TieBreakRule{usecase.TieBreakOlder}).Resolve(candidates)
+	assert.Equal(t, []usecase.LinkCandidate{olderCandidate}, resolution.Selected)
+}
+
+func TestParseTieBreakRules(t *testing.T) {
+	// 設定値からTieBreakRuleの列を作成できることをテスト
+	rules, err := usecase.ParseTieBreakRules("")
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.DefaultTieBreakRules, rules)
+
+	rules, err = usecase.ParseTieBreakRules("older, notation_kind")
+	assert.NoError(t, err)
+	assert.Equal(t, []usecase.TieBreakRule{usecase.TieBreakOlder, usecase.TieBreakNotationKind}, rules)
+
+	_, err = usecase.ParseTieBreakRules("newer,unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"api/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubstringMatcher(t *testing.T) {
+	// 部分文字列として含まれる箇所が全てマッチすることをテスト
+	pm := usecase.NewSubstringMatcher().Prepare([]string{"日", "日本", ""})
+
+	assert.Equal(
+		t,
+		[]usecase.Match{
+			{Start: 0, End: 1, TermIndex: 0},
+			{Start: 0, End: 2, TermIndex: 1},
+			{Start: 4, End: 5, TermIndex: 0},
+		},
+		pm.FindAll("日本で毎日"),
+	)
+}
+
+func TestAhoCorasickMatcher(t *testing.T) {
+	// SubstringMatcherと同じ出現箇所を返すことをテスト
+	terms := []string{"日", "日本", "", "本で", "毎日", "日本", "abcab", "bc", "c"}
+	substring := usecase.NewSubstringMatcher().Prepare(terms)
+	ahoCorasick := usecase.NewAhoCorasickMatcher().Prepare(terms)
+
+	for _, sentence := range []string{
+		"日本で毎日",
+		"日日日",
+		"abcabcab",
+		"",
+		"一致しない",
+	} {
+		assert.Equal(t, substring.FindAll(sentence), ahoCorasick.FindAll(sentence), sentence)
+	}
+}
+
+func TestMorphologicalMatcher(t *testing.T) {
+	// 形態素の原形が一致する箇所のみマッチすることをテスト
+	m, err := usecase.NewMorphologicalMatcher()
+	assert.NoError(t, err)
+
+	pm := m.Prepare([]string{"日", "日本", "食べる"})
+
+	// 「日本」「毎日」の中の「日」にはマッチしない
+	assert.Equal(
+		t,
+		[]usecase.Match{
+			{Start: 0, End: 2, TermIndex: 1},
+		},
+		pm.FindAll("日本で毎日"),
+	)
+
+	// 活用した形にもマッチする
+	assert.Equal(
+		t,
+		[]usecase.Match{
+			{Start: 0, End: 1, TermIndex: 0},
+			{Start: 10, End: 12, TermIndex: 2},
+		},
+		pm.FindAll("日が暮れる前にご飯を食べた"),
+	)
+}
+
+func TestNewMatcher_WithUnknownName(t *testing.T) {
+	// 存在しない方式を指定した場合エラーになることをテスト
+	_, err := usecase.NewMatcher("unknown")
+	assert.Error(t, err)
+}
@@ -25,12 +25,14 @@ func TestGetAllNotations(t *testing.T) {
 			{
 				"id": %d,
 				"word_id": %d,
-				"notation": "test notation1"
+				"notation": "test notation1",
+				"kind": "manual"
 			},
 			{
 				"id": %d,
 				"word_id": %d,
-				"notation": "test notation2"
+				"notation": "test notation2",
+				"kind": "manual"
 			}
 		]`,
 		notationId1,
@@ -52,6 +54,61 @@ func TestGetAllNotations(t *testing.T) {
 	)
 }
 
+func TestGetAllNotations_WithKind(t *testing.T) {
+	// クエリパラメータkindを指定した場合、指定した作成元のNotationのみ取得できることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromNotations()
+
+	wordId := insertIntoWords("test word", "test memo", 1)
+	insertIntoNotationsWithKind(wordId, "test notation1", "manual")
+	notationId2 := insertIntoNotationsWithKind(wordId, "test notation2", "conjugation")
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"word_id": %d,
+				"notation": "test notation2",
+				"kind": "conjugation"
+			}
+		]`,
+		notationId2,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/notations",
+		nc.GetAllNotations,
+		http.StatusOK,
+		expectedResponse,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		QueryParams(
+			[]string{"kind"},
+			[][]string{{"conjugation"}},
+		),
+	)
+
+	// 存在しない作成元を指定した場合400が返る
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/notations",
+		nc.GetAllNotations,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		QueryParams(
+			[]string{"kind"},
+			[][]string{{"unknown"}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
 func TestGetAllNotations_WithNoRows(t *testing.T) {
 	// Wordがログイン中のUserに紐づき、
 	// かつWordに紐づくNotationの数が0の場合、nullが返ることをテスト
@@ -76,7 +133,7 @@ func TestGetAllNotations_WithNoRows(t *testing.T) {
 }
 
 func TestGetAllNotations_WithInvalidWordId(t *testing.T) {
-	// Wordがログイン中のUserに紐づかない場合、Wordに紐づくNotationを取得できないことをテスト
+	// Wordがログイン中のUserに紐づかない場合、Wordに紐づくNotationを取得できず404が返ることをテスト
 	// TODO ログイン機能
 	// とりあえずWordに紐づくUserがuser_id=1の場合のみ取得可能とする
 	DeleteAllFromWords()
@@ -92,7 +149,8 @@ func TestGetAllNotations_WithInvalidWordId(t *testing.T) {
 			{
 				"id": %d,
 				"word_id": %d,
-				"notation": "test notation1"
+				"notation": "test notation1",
+				"kind": "manual"
 			}
 		]`,
 		notationIdWithUserId1,
@@ -115,8 +173,8 @@ func TestGetAllNotations_WithInvalidWordId(t *testing.T) {
 		t,		
 		"/words/:wordId/notations",
 		nc.GetAllNotations,
-		http.StatusOK,
-		"null",
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
 		Params(
 			[]string{"wordId"},
 			[]string{strconv.FormatUint(wordIdWithUserId2, 10)},
@@ -144,7 +202,8 @@ func TestCreateNotation(t *testing.T) {
 		{
 			"id": %d,
 			"word_id": %d,
-			"notation": "test notation"
+			"notation": "test notation",
+			"kind": "manual"
 		}`,
 		notationId,
 		wordId,
@@ -197,8 +256,8 @@ func TestCreateNotation_WithInvalidUser(t *testing.T) {
 		t,
 		"/words/:wordId/notations",
 		nc.CreateNotation,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
 		HttpMethod(http.MethodPost),
 		Params(
 			[]string{"wordId"},
@@ -299,18 +358,19 @@ func TestCreateNotation_Duplicate(t *testing.T) {
 	DeleteAllFromNotations()
 
 	wordId := insertIntoWords("りんご", "", 1)
-	insertIntoNotations(wordId, "林檎")
+	notationId := insertIntoNotations(wordId, "林檎")
 
 	reqBody := `{
 		"notation": "林檎"
 	}`
 
+	// 既存のNotationのidが返る
 	DoSimpleTest(
 		t,
 		"/words/:wordId/notations",
 		nc.CreateNotation,
 		http.StatusConflict,
-		"{}",
+		fmt.Sprintf(`{"code": "conflict", "message": "notation already exists", "details": {"existing_id": %d}, "request_id": ""}`, notationId),
 		HttpMethod(http.MethodPost),
 		Body(reqBody),
 		Params(
@@ -333,6 +393,40 @@ func TestCreateNotation_Duplicate(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestUpdateNotation_Duplicate(t *testing.T) {
+	// 同じWordの他のNotationと同じ表記に更新しようとした場合、
+	// 409と既存のNotationのidが返り、更新されないことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromNotations()
+
+	wordId := insertIntoWords("りんご", "", 1)
+	existingId := insertIntoNotations(wordId, "林檎")
+	notationId := insertIntoNotations(wordId, "リンゴ")
+
+	DoSimpleTest(
+		t,
+		"/notations/:notationId",
+		nc.UpdateNotation,
+		http.StatusConflict,
+		fmt.Sprintf(`{"code": "conflict", "message": "notation already exists", "details": {"existing_id": %d}, "request_id": ""}`, existingId),
+		HttpMethod(http.MethodPut),
+		Body(`{"notation": "林檎"}`),
+		Params(
+			[]string{"notationId"},
+			[]string{strconv.FormatUint(notationId, 10)},
+		),
+	)
+
+	var notation string
+	db.QueryRow(`
+		SELECT notation FROM notations
+		WHERE id = $1;
+	`,
+		notationId,
+	).Scan(&notation)
+	assert.Equal(t, "リンゴ", notation)
+}
+
 func TestUpdateNotation(t *testing.T) {
 	// ログイン中のUserに紐づくWordに対し、Notationを更新できることをテスト
 	// TODO ログイン機能
@@ -352,7 +446,8 @@ func TestUpdateNotation(t *testing.T) {
 		{
 			"id": %d,
 			"word_id": %d,
-			"notation": "updated notation"
+			"notation": "updated notation",
+			"kind": "manual"
 		}`,
 		notationId,
 		wordId,
@@ -387,7 +482,7 @@ func TestUpdateNotation(t *testing.T) {
 }
 
 func TestUpdateNotation_WithNoRows(t *testing.T) {
-	// ログイン中のUserに紐づくWordに対し、更新対象のNotationが無い場合、{}が返ることをテスト
+	// ログイン中のUserに紐づくWordに対し、更新対象のNotationが無い場合、404が返ることをテスト
 	// TODO ログイン機能
 	// とりあえずログインUserはuser_id=1とする
 	DeleteAllFromWords()
@@ -403,8 +498,8 @@ func TestUpdateNotation_WithNoRows(t *testing.T) {
 		t,
 		"/notations/:notationId",
 		nc.UpdateNotation,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("notation"),
 		Params(
 			[]string{"notationId"},
 			[]string{"1"},
@@ -496,7 +591,8 @@ func TestDeleteNotation(t *testing.T) {
 		{
 			"id": %d,
 			"word_id": %d,
-			"notation": "test notation"
+			"notation": "test notation",
+			"kind": "manual"
 		}`,
 		notationId,
 		wordId,
@@ -533,8 +629,8 @@ func TestDeleteNotation_WithInvalidUser(t *testing.T) {
 		t,
 		"/notations/:notationId",
 		nc.DeleteNotation,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("notation"),
 		Params(
 			[]string{"notationId"},
 			[]string{strconv.FormatUint(notationId, 10)},
@@ -601,4 +697,31 @@ func TestDeleteNotation_UpdatedAssociation_SomeNotationRemaining(t *testing.T) {
 	// Notation「林檎」を削除しても、Wordは「リンゴ」でマッチし続けるため、
 	// sentences_wordsから削除されない
 	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
-}
\ No newline at end of file
+}
+
+func TestUpdateNotation_GeneratedNotationBecomesManual(t *testing.T) {
+	// 自動生成されたNotationを更新した場合、ユーザーが入力したNotationとして扱われることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromNotations()
+
+	wordId := insertIntoWords("買う", "", 1)
+	notationId := insertIntoNotationsWithKind(wordId, "買わない", "conjugation")
+
+	reqBody := `{
+		"notation": "買わぬ"
+	}`
+
+	ExecController(
+		t,
+		"/notations/:notationId",
+		nc.UpdateNotation,
+		HttpMethod(http.MethodPut),
+		Params(
+			[]string{"notationId"},
+			[]string{strconv.FormatUint(notationId, 10)},
+		),
+		Body(reqBody),
+	)
+
+	assert.Equal(t, "manual", getKindFromNotations(notationId))
+}
@@ -0,0 +1,212 @@
+package test
+
+import (
+	"api/model"
+	"api/usecase"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDueReviews(t *testing.T) {
+	// 1度も復習していないWordが、紐づくSentenceとともに返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := createTestWord(t, "りんご", "").Id
+	sentenceId := createTestSentence(t, "りんごを食べた").Id
+
+	// 他のUserのWordは返らない
+	insertIntoWords("みかん", "", 2)
+
+	_, rec := ExecController(
+		t,
+		"/reviews/due",
+		rvc.GetDueReviews,
+	)
+
+	var dueReviewResponses []model.DueReviewResponse
+	json.Unmarshal(rec.Body.Bytes(), &dueReviewResponses)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, len(dueReviewResponses))
+	assert.Equal(t, wordId, dueReviewResponses[0].Word.Id)
+	assert.Nil(t, dueReviewResponses[0].Review)
+	assert.Equal(t, 1, len(dueReviewResponses[0].Sentences))
+	assert.Equal(t, sentenceId, dueReviewResponses[0].Sentences[0].Id)
+	// 紐づけの作成時に記録した出現箇所が空欄になる
+	assert.Equal(t, "＿＿＿を食べた", dueReviewResponses[0].Sentences[0].Cloze)
+}
+
+func TestGetDueReviews_WithMultipleWords(t *testing.T) {
+	// 複数のWordが、それぞれに紐づくSentenceとともに返り、
+	// 1つのSentenceに複数のWordが含まれる場合も、各Wordの出現箇所のみが空欄になることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	appleWordId := createTestWord(t, "りんご", "").Id
+	orangeWordId := createTestWord(t, "みかん", "").Id
+	bothSentenceId := createTestSentence(t, "りんごとみかん").Id
+	orangeSentenceId := createTestSentence(t, "みかんを食べた").Id
+
+	_, rec := ExecController(
+		t,
+		"/reviews/due",
+		rvc.GetDueReviews,
+	)
+
+	var dueReviewResponses []model.DueReviewResponse
+	json.Unmarshal(rec.Body.Bytes(), &dueReviewResponses)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, len(dueReviewResponses))
+
+	assert.Equal(t, appleWordId, dueReviewResponses[0].Word.Id)
+	assert.Equal(t, 1, len(dueReviewResponses[0].Sentences))
+	assert.Equal(t, bothSentenceId, dueReviewResponses[0].Sentences[0].Id)
+	assert.Equal(t, "＿＿＿とみかん", dueReviewResponses[0].Sentences[0].Cloze)
+
+	assert.Equal(t, orangeWordId, dueReviewResponses[1].Word.Id)
+	assert.Equal(t, 2, len(dueReviewResponses[1].Sentences))
+	assert.Equal(t, bothSentenceId, dueReviewResponses[1].Sentences[0].Id)
+	assert.Equal(t, "りんごと＿＿＿", dueReviewResponses[1].Sentences[0].Cloze)
+	assert.Equal(t, orangeSentenceId, dueReviewResponses[1].Sentences[1].Id)
+	assert.Equal(t, "＿＿＿を食べた", dueReviewResponses[1].Sentences[1].Cloze)
+}
+
+func TestReviewWord(t *testing.T) {
+	// 復習を記録すると次の復習期限が更新され、今日の復習対象から外れることをテスト
+	DeleteAllFromWords()
+
+	wordId := createTestWord(t, "りんご", "").Id
+
+	_, rec := ExecController(
+		t,
+		"/reviews/:wordId",
+		rvc.ReviewWord,
+		HttpMethod(http.MethodPost),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		Body(`{"grade": 5}`),
+	)
+
+	var reviewRes model.ReviewResponse
+	json.Unmarshal(rec.Body.Bytes(), &reviewRes)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, wordId, reviewRes.WordId)
+	assert.Equal(t, uint64(1), reviewRes.IntervalDays)
+	assert.Equal(t, uint64(1), reviewRes.Repetitions)
+	assert.Equal(t, uint64(5), reviewRes.LastGrade)
+
+	// 次の復習期限は明日のため、今日の復習対象には含まれない
+	DoSimpleTest(
+		t,
+		"/reviews/due",
+		rvc.GetDueReviews,
+		http.StatusOK,
+		"[]",
+	)
+}
+
+func TestReviewWord_Concurrent(t *testing.T) {
+	// 同じWordの復習を同時に記録しても、全ての復習が反映されることをテスト
+	DeleteAllFromWords()
+
+	wordId := createTestWord(t, "りんご", "").Id
+
+	const reviewCount = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, reviewCount)
+	for i := 0; i < reviewCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := rvu.ReviewWord(model.ReviewCreation{
+				WordId:      wordId,
+				Grade:       5,
+				LoginUserId: 1,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var repetitions uint64
+	db.QueryRow(`
+		SELECT repetitions FROM reviews
+		WHERE word_id = $1;
+	`,
+		wordId,
+	).Scan(&repetitions)
+	assert.Equal(t, uint64(reviewCount), repetitions)
+}
+
+func TestReviewWord_WithInvalidGrade(t *testing.T) {
+	// 評価が無い、または範囲外の場合422が返ることをテスト
+	DeleteAllFromWords()
+
+	wordId := createTestWord(t, "りんご", "").Id
+
+	for _, body := range []string{`{}`, `{"grade": 6}`} {
+		_, rec := ExecController(
+			t,
+			"/reviews/:wordId",
+			rvc.ReviewWord,
+			HttpMethod(http.MethodPost),
+			Params(
+				[]string{"wordId"},
+				[]string{strconv.FormatUint(wordId, 10)},
+			),
+			Body(body),
+		)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	}
+}
+
+func TestReviewWord_WithInvalidUser(t *testing.T) {
+	// ログイン中のUserに紐づかないWordは復習できないことをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("りんご", "", 2)
+
+	DoSimpleTest(
+		t,
+		"/reviews/:wordId",
+		rvc.ReviewWord,
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
+		HttpMethod(http.MethodPost),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		Body(`{"grade": 5}`),
+	)
+}
+
+func TestRenderCloze(t *testing.T) {
+	// リンクとして選ばれた出現箇所のみが空欄になることをテスト
+	// データベースを使用しない
+	occurrences := []model.SentenceWordOccurrence{
+		{WordId: 1, Start: 0, End: 2, DiscardReason: usecase.DiscardReasonOverlap},
+		{WordId: 1, Start: 4, End: 6},
+		// 範囲外の出現箇所は無視する
+		{WordId: 1, Start: 6, End: 20},
+	}
+
+	assert.Equal(t, "日本語と＿＿＿", usecase.RenderCloze("日本語と日本", occurrences))
+	assert.Equal(t, "日本語", usecase.RenderCloze("日本語", nil))
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"api/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// データベースを使用しないテスト
+
+func TestSM2Scheduler_NewSchedule(t *testing.T) {
+	// 1度も復習していないWordは、すぐに復習期限を迎えることをテスト
+	s := usecase.NewSM2Scheduler()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	schedule := s.NewSchedule(now)
+
+	assert.Equal(t, 2.5, schedule.EaseFactor)
+	assert.Equal(t, uint64(0), schedule.Repetitions)
+	assert.Equal(t, now, schedule.DueAt)
+}
+
+func TestSM2Scheduler_Schedule(t *testing.T) {
+	// 思い出せた場合、復習間隔が1日、6日、以降は易しさ係数倍に伸びることをテスト
+	s := usecase.NewSM2Scheduler()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	schedule := s.NewSchedule(now)
+
+	schedule, err := s.Schedule(schedule, 5, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), schedule.IntervalDays)
+	assert.Equal(t, uint64(1), schedule.Repetitions)
+	assert.InDelta(t, 2.6, schedule.EaseFactor, 0.0001)
+	assert.Equal(t, now.AddDate(0, 0, 1), schedule.DueAt)
+
+	schedule, err = s.Schedule(schedule, 4, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), schedule.IntervalDays)
+	assert.InDelta(t, 2.6, schedule.EaseFactor, 0.0001)
+
+	// 6日 * 2.6 = 15.6 -> 16日
+	schedule, err = s.Schedule(schedule, 3, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), schedule.IntervalDays)
+	assert.Equal(t, uint64(3), schedule.Repetitions)
+	assert.InDelta(t, 2.46, schedule.EaseFactor, 0.0001)
+	assert.Equal(t, now.AddDate(0, 0, 16), schedule.DueAt)
+}
+
+func TestSM2Scheduler_Schedule_WithFailedGrade(t *testing.T) {
+	// 思い出せなかった場合、連続正解数がリセットされ1日後に復習することをテスト
+	s := usecase.NewSM2Scheduler()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	schedule := s.NewSchedule(now)
+	schedule.Repetitions = 4
+	schedule.IntervalDays = 30
+
+	schedule, err := s.Schedule(schedule, 1, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), schedule.Repetitions)
+	assert.Equal(t, uint64(1), schedule.IntervalDays)
+	assert.InDelta(t, 1.96, schedule.EaseFactor, 0.0001)
+}
+
+func TestSM2Scheduler_Schedule_MinEaseFactor(t *testing.T) {
+	// 易しさ係数が1.3未満にならないことをテスト
+	s := usecase.NewSM2Scheduler()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	schedule := s.NewSchedule(now)
+	for i := 0; i < 10; i++ {
+		schedule, _ = s.Schedule(schedule, 0, now)
+	}
+
+	assert.Equal(t, 1.3, schedule.EaseFactor)
+}
+
+func TestSM2Scheduler_Schedule_WithInvalidGrade(t *testing.T) {
+	// 評価が5より大きい場合エラーになることをテスト
+	s := usecase.NewSM2Scheduler()
+	now := time.Now()
+
+	_, err := s.Schedule(s.NewSchedule(now), 6, now)
+	assert.ErrorIs(t, err, usecase.ErrInvalidGrade)
+}
@@ -0,0 +1,197 @@
+package test
+
+import (
+	"api/model"
+	"api/usecase"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func toSearchResponse(rec *httptest.ResponseRecorder) model.SearchResponse {
+	var searchRes model.SearchResponse
+	json.Unmarshal(rec.Body.Bytes(), &searchRes)
+	return searchRes
+}
+
+func TestSearch(t *testing.T) {
+	// Word、Notation、Sentenceから、queryに一致するものを検索できることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	appleWordId := createTestWord(t, "りんご", "赤い果物").Id
+	appleNotationId := createTestNotation(t, appleWordId, "林檎").Id
+	createTestWord(t, "みかん", "")
+	sentenceId := createTestSentence(t, "林檎を食べた").Id
+	createTestSentence(t, "みかんを食べた")
+
+	// 他のUserのWordは検索されない
+	insertIntoWords("林檎ジュース", "", 2)
+
+	_, rec := ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q"},
+			[][]string{{"林檎"}},
+		),
+	)
+	searchRes := toSearchResponse(rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Notationが一致したWordが返る
+	assert.Equal(t, uint64(1), searchRes.WordsTotalCount)
+	assert.Equal(t, appleWordId, searchRes.Words[0].Id)
+	assert.Equal(t, []string{"<mark>林檎</mark>"}, searchRes.Words[0].MatchedNotations)
+
+	// 一致したSentenceが、Wordへのリンクと一致箇所の強調付きで返る
+	assert.Equal(t, uint64(1), searchRes.SentencesTotalCount)
+	assert.Equal(t, sentenceId, searchRes.Sentences[0].Id)
+	assert.Equal(
+		t,
+		[]model.LinkAnnotationResponse{{Start: 0, End: 2, WordId: appleWordId, NotationId: appleNotationId, MatchedText: "林檎"}},
+		searchRes.Sentences[0].Annotations,
+	)
+	// HTMLは ?with-html=true の場合のみ返る
+	assert.Equal(t, "", searchRes.Sentences[0].SentenceWithLink)
+	assert.Equal(t, "<mark>林檎</mark>を食べた", searchRes.Sentences[0].HighlightedSentence)
+}
+
+func TestSearch_RankedAndPaginated(t *testing.T) {
+	// Wordそのものに一致したものが上位に来ること、
+	// limitとoffsetで取得範囲を指定できることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	memoWordId := createTestWord(t, "果物", "apple pie").Id
+	wordWordId := createTestWord(t, "apple", "").Id
+
+	_, rec := ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q", "limit"},
+			[][]string{{"APPLE"}, {"1"}},
+		),
+	)
+	firstPage := toSearchResponse(rec)
+
+	assert.Equal(t, uint64(2), firstPage.WordsTotalCount)
+	assert.Equal(t, 1, len(firstPage.Words))
+	assert.Equal(t, wordWordId, firstPage.Words[0].Id)
+	assert.Equal(t, "<mark>apple</mark>", firstPage.Words[0].HighlightedWord)
+
+	_, rec = ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q", "limit", "offset"},
+			[][]string{{"APPLE"}, {"1"}, {"1"}},
+		),
+	)
+	secondPage := toSearchResponse(rec)
+
+	assert.Equal(t, 1, len(secondPage.Words))
+	assert.Equal(t, memoWordId, secondPage.Words[0].Id)
+	assert.Equal(t, "<mark>apple</mark> pie", secondPage.Words[0].HighlightedMemo)
+}
+
+func TestSearch_MatchedNotationsOfMultipleWords(t *testing.T) {
+	// 複数のWordが一致した場合、それぞれのWordのNotationのうち一致したものだけが返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	appleWordId := createTestWord(t, "りんご", "").Id
+	createTestNotation(t, appleWordId, "林檎")
+	createTestNotation(t, appleWordId, "アップル")
+	juiceWordId := createTestWord(t, "ジュース", "").Id
+	createTestNotation(t, juiceWordId, "林檎ジュース")
+
+	_, rec := ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q"},
+			[][]string{{"林檎"}},
+		),
+	)
+	searchRes := toSearchResponse(rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, len(searchRes.Words))
+
+	matchedNotationsByWordId := map[uint64][]string{}
+	for _, word := range searchRes.Words {
+		matchedNotationsByWordId[word.Id] = word.MatchedNotations
+	}
+	assert.Equal(t, []string{"<mark>林檎</mark>"}, matchedNotationsByWordId[appleWordId])
+	assert.Equal(t, []string{"<mark>林檎</mark>ジュース"}, matchedNotationsByWordId[juiceWordId])
+}
+
+func TestSearch_EscapesHighlightedMemo(t *testing.T) {
+	// memoに含まれるHTMLが、強調の<mark>以外エスケープされて返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := createTestWord(t, "果物", `<script>alert("apple")</script>`).Id
+
+	_, rec := ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q"},
+			[][]string{{"apple"}},
+		),
+	)
+	searchRes := toSearchResponse(rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, wordId, searchRes.Words[0].Id)
+	assert.Equal(t, `<script>alert("apple")</script>`, searchRes.Words[0].Memo)
+	assert.Equal(
+		t,
+		"&lt;script&gt;alert(&#34;<mark>apple</mark>&#34;)&lt;/script&gt;",
+		searchRes.Words[0].HighlightedMemo,
+	)
+}
+
+func TestSearch_WithEmptyQuery(t *testing.T) {
+	// qが空の場合422が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/search",
+		sec.Search,
+		QueryParams(
+			[]string{"q"},
+			[][]string{{" "}},
+		),
+	)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestHighlightMatches(t *testing.T) {
+	// 大文字小文字を区別せず、一致箇所を全て強調することをテスト
+	assert.Equal(t, "<mark>Go</mark> and <mark>go</mark>", usecase.HighlightMatches("Go and go", "GO"))
+	assert.Equal(t, "no match", usecase.HighlightMatches("no match", "xyz"))
+}
+
+func TestHighlightMatches_EscapesHTML(t *testing.T) {
+	// 一致箇所も、一致しない部分も、HTMLとしてエスケープされることをテスト
+	assert.Equal(
+		t,
+		"&lt;script&gt;alert(&#34;<mark>memo</mark>&#34;)&lt;/script&gt;",
+		usecase.HighlightMatches(`<script>alert("memo")</script>`, "memo"),
+	)
+	assert.Equal(t, "<mark>&lt;b&gt;</mark> &amp; b", usecase.HighlightMatches("<b> & b", "<B>"))
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt;", usecase.HighlightMatches("<img src=x onerror=alert(1)>", ""))
+}
@@ -1,9 +1,13 @@
 package test
 
 import (
+	"api/model"
+	"api/usecase"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,7 +56,7 @@ func TestGetAllSentences(t *testing.T) {
 			{
 				"id": %d,
 				"sentence": "test sentence",
-				"sentence_with_link": "test sentence",
+				"annotations": [],
 				"user_id": 1
 			}
 		]`,
@@ -84,7 +88,10 @@ func TestGetAllSentences_IncludingWords(t *testing.T) {
 			{
 				"id": %d,
 				"sentence": "林檎を食べた",
-				"sentence_with_link": "<a href=\"/words/%d\">林檎</a>を<a href=\"/words/%d\">食べた</a>",
+				"annotations": [
+					{"start": 0, "end": 2, "word_id": %d, "matched_text": "林檎"},
+					{"start": 3, "end": 6, "word_id": %d, "matched_text": "食べた"}
+				],
 				"user_id": 1
 			}
 		]`,
@@ -102,6 +109,149 @@ func TestGetAllSentences_IncludingWords(t *testing.T) {
 	)
 }
 
+func TestGetAllSentences_UsesStoredOccurrences(t *testing.T) {
+	// リンクは、Sentenceを再度探索せず、紐づけの作成時に記録した出現箇所から作成されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	appleWordId := createTestWord(t, "りんご", "").Id
+	appleNotationId := createTestNotation(t, appleWordId, "林檎").Id
+	sentenceId := createTestSentence(t, "林檎を食べた").Id
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM sentence_word_occurrences
+		WHERE sentence_id = $1 AND word_id = $2 AND notation_id = $3
+			AND start_offset = 0 AND end_offset = 2;
+		`,
+		sentenceId,
+		appleWordId,
+		appleNotationId,
+	).Scan(&count)
+	assert.Equal(t, 1, count)
+
+	// 出現箇所が無い場合は、紐づけが残っていてもリンクとならない
+	db.Exec("DELETE FROM sentence_word_occurrences WHERE sentence_id = $1;", sentenceId)
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, appleWordId))
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"sentence": "林檎を食べた",
+				"annotations": [],
+				"user_id": 1
+			}
+		]`,
+		sentenceId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/sentences",
+		sc.GetAllSentences,
+		http.StatusOK,
+		expectedResponse,
+	)
+}
+
+func TestGetAllSentences_WithHTML(t *testing.T) {
+	// ?with-html=true の場合、エスケープ済みのリンク付きHTMLが返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	appleWordId := createTestWord(t, "林檎", "").Id
+	// Wordの一部であるNotationは、Wordのリンクの中に入れ子にならない
+	insertIntoNotations(appleWordId, "林")
+	sentenceId := createTestSentence(t, "<script>林檎</script>").Id
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"sentence": "<script>林檎</script>",
+				"sentence_with_link": "&lt;script&gt;<a href=\"/words/%d\">林檎</a>&lt;/script&gt;",
+				"annotations": [
+					{"start": 8, "end": 10, "word_id": %d, "matched_text": "林檎"}
+				],
+				"user_id": 1
+			}
+		]`,
+		sentenceId,
+		appleWordId,
+		appleWordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/sentences",
+		sc.GetAllSentences,
+		http.StatusOK,
+		expectedResponse,
+		QueryParams(
+			[]string{"with-html"},
+			[][]string{{"true"}},
+		),
+	)
+}
+
+func TestGetAllSentences_WithOverlappingWords(t *testing.T) {
+	// 重なり合うWordがある場合、各位置で最も長いWordのみがリンクとなり、
+	// リンクとならなかった箇所が報告されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	japanWordId := createTestWord(t, "日本", "").Id
+	japaneseWordId := createTestWord(t, "日本語", "").Id
+	sentenceId := createTestSentence(t, "日本語と日本").Id
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"sentence": "日本語と日本",
+				"annotations": [
+					{"start": 0, "end": 3, "word_id": %d, "matched_text": "日本語"},
+					{"start": 4, "end": 6, "word_id": %d, "matched_text": "日本"}
+				],
+				"discarded_annotations": [
+					{"start": 0, "end": 2, "word_id": %d, "matched_text": "日本", "reason": "overlap"}
+				],
+				"user_id": 1
+			}
+		]`,
+		sentenceId,
+		japaneseWordId,
+		japanWordId,
+		japanWordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/sentences",
+		sc.GetAllSentences,
+		http.StatusOK,
+		expectedResponse,
+	)
+}
+
+func TestRenderSentenceWithLink(t *testing.T) {
+	// データベースを使用しないテスト
+	// annotationsの範囲のみがリンクになり、それ以外の部分はエスケープされることをテスト
+	annotations := []model.LinkAnnotation{
+		{Start: 0, End: 2, WordId: 1, MatchedText: "林檎"},
+		// 重なり合うannotationは無視される
+		{Start: 1, End: 2, WordId: 2, MatchedText: "檎"},
+		{Start: 6, End: 7, WordId: 3, MatchedText: "&"},
+	}
+
+	assert.Equal(
+		t,
+		`<a href="/words/1">林檎</a>と&lt;b&gt;<a href="/words/3">&amp;</a>`,
+		usecase.RenderSentenceWithLink("林檎と<b>&", annotations),
+	)
+}
+
 func TestGetAllSentences_WithLimit(t *testing.T) {
 	// ログイン中のUserに紐づくSentenceを、LIMIT付きで取得できることをテテスト
 	// TODO ログイン機能
@@ -117,13 +267,13 @@ func TestGetAllSentences_WithLimit(t *testing.T) {
 			{
 				"id": %d,
 				"sentence": "test sentence 1",
-				"sentence_with_link": "test sentence 1",
+				"annotations": [],
 				"user_id": 1
 			},
 			{
 				"id": %d,
 				"sentence": "test sentence 2",
-				"sentence_with_link": "test sentence 2",
+				"annotations": [],
 				"user_id": 1
 			}
 		]`,
@@ -159,13 +309,13 @@ func TestGetAllSentences_WithOffset(t *testing.T) {
 			{
 				"id": %d,
 				"sentence": "test sentence 2",
-				"sentence_with_link": "test sentence 2",
+				"annotations": [],
 				"user_id": 1
 			},
 			{
 				"id": %d,
 				"sentence": "test sentence 3",
-				"sentence_with_link": "test sentence 3",
+				"annotations": [],
 				"user_id": 1
 			}
 		]`,
@@ -201,7 +351,7 @@ func TestGetAllSentences_WithLimitAndOffset(t *testing.T) {
 			{
 				"id": %d,
 				"sentence": "test sentence 2",
-				"sentence_with_link": "test sentence 2",
+				"annotations": [],
 				"user_id": 1
 			}
 		]`,
@@ -275,8 +425,8 @@ func TestGetSentenceById_WithInvalidUser(t *testing.T) {
 		t,
 		"/sentences/:sentenceId",
 		sc.GetSentenceById,
-		http.StatusOK,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("sentence"),
 		Params(
 			[]string{"sentenceId"},
 			[]string{id},
@@ -744,6 +894,92 @@ func TestCreateMultipleSentences(t *testing.T) {
 	assert.Equal(t, "test sentence 2", sentence2)
 }
 
+func TestCreateMultipleSentences_WithInvalidItems(t *testing.T) {
+	// 複数同時に作成するSentenceのうち1件でも不正なものがある場合、
+	// どれも作成されず、不正な項目の一覧が返ることをテスト
+	DeleteAllFromSentences()
+
+	// sentenceはVARCHAR(500)であるため、2件目は不正
+	reqBody := fmt.Sprintf(`{
+		"sentences": [
+				{
+					"sentence": "test sentence 1"
+				},
+				{
+					"sentence": "%s"
+				}
+			]
+		}`,
+		strings.Repeat("a", 501),
+	)
+
+	expectedResponse := `
+		{
+			"code": "validation_failed",
+			"message": "1 field(s) are invalid",
+			"details": [
+				{
+					"field": "sentences[1].sentence",
+					"reason": "must be at most 500 characters"
+				}
+			],
+			"request_id": ""
+		}`
+
+	DoSimpleTest(
+		t,
+		"/sentences/multiple",
+		sc.CreateMultipleSentences,
+		http.StatusUnprocessableEntity,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	// 1件目も追加されない
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM sentences
+		WHERE user_id = 1;
+	`).Scan(&count)
+
+	assert.Equal(t, 0, count)
+}
+
+func TestCreateMultipleSentences_IncludingWords(t *testing.T) {
+	// 複数同時に作成したSentenceに既存のWordが含まれる場合、
+	// sentences_wordsに追加されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("りんご", "", 1)
+
+	sentenceId1 := GetNextSentencesSequenceValue()
+	sentenceId2 := sentenceId1 + 1
+
+	reqBody := `{
+		"sentences": [
+				{
+					"sentence": "赤いりんごを食べた"
+				},
+				{
+					"sentence": "黄色いレモンを食べた"
+				}
+			]
+		}`
+
+	ExecController(
+		t,
+		"/sentences/multiple",
+		sc.CreateMultipleSentences,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId1, wordId))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId2, wordId))
+}
+
 func TestUpdateSentence(t *testing.T) {
 	// ログイン中のUserに紐づくSentenceを更新できることをテスト
 	// TODO ログイン機能
@@ -858,8 +1094,8 @@ func TestUpdateSentence_WithInvalidUser(t *testing.T) {
 		t,
 		"/words/:sentenceId",
 		sc.UpdateSentence,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("sentence"),
 		Params(
 			[]string{"sentenceId"},
 			[]string{id},
@@ -974,8 +1210,8 @@ func TestDeleteSentence_WithInvalidUser(t *testing.T) {
 		t,
 		"/sentences/:sentenceId",
 		sc.DeleteSentence,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("sentence"),
 		Params(
 			[]string{"sentenceId"},
 			[]string{id},
@@ -1071,9 +1307,50 @@ func TestGetAssociatedWords(t *testing.T) {
 	)
 }
 
+func TestGetAssociatedWords_WithPronunciation(t *testing.T) {
+	// Sentenceに紐づくWordの読み、品詞、アクセントも返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "東京", "memo": "", "reading": "とうきょう", "part_of_speech": "noun", "pitch_accent": 0}`),
+	)
+	wordId := toWordResponse(rec).Id
+	sentenceId := createTestSentence(t, "東京に行く").Id
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId/associated-words",
+		sc.GetAssociatedWords,
+		http.StatusOK,
+		fmt.Sprintf(`
+			[
+				{
+					"id": %d,
+					"word": "東京",
+					"memo": "",
+					"reading": "とうきょう",
+					"part_of_speech": "noun",
+					"pitch_accent": 0,
+					"user_id": 1
+				}
+			]`,
+			wordId,
+		),
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentenceId, 10)},
+		),
+	)
+}
+
 func TestGetAssociatedWords_WithInvalidSentenceId(t *testing.T) {
 	// Sentenceがログイン中のuser_idに紐づかない場合、
-	// Sentenceに紐づくWordを取得できないことをテスト
+	// Sentenceに紐づくWordを取得できず404が返ることをテスト
 	// TODO ログイン機能
 	// とりあえずuser_id=1のWordのみ取得可能とする
 	DeleteAllFromWords()
@@ -1110,8 +1387,8 @@ func TestGetAssociatedWords_WithInvalidSentenceId(t *testing.T) {
 		t,
 		"/sentences/:sentenceId/associated-words",
 		sc.GetAssociatedWords,
-		http.StatusOK,
-		"null",
+		http.StatusNotFound,
+		notFoundErrorJSON("sentence"),
 		Params(
 			[]string{"sentenceId"},
 			[]string{sentenceId},
@@ -1201,4 +1478,122 @@ func TestGetSentencesCount(t *testing.T) {
 		http.StatusOK,
 		`{ "count": 3 }`,
 	)
-}
\ No newline at end of file
+}
+func setTestAssociationOverride(t *testing.T, sentenceId, wordId uint64, kind string) *httptest.ResponseRecorder {
+	_, rec := ExecController(
+		t,
+		"/sentences/:sentenceId/word-overrides/:wordId",
+		sc.SetAssociationOverride,
+		HttpMethod(http.MethodPut),
+		Body(fmt.Sprintf(`{"kind": "%s"}`, kind)),
+		Params(
+			[]string{"sentenceId", "wordId"},
+			[]string{strconv.FormatUint(sentenceId, 10), strconv.FormatUint(wordId, 10)},
+		),
+	)
+
+	return rec
+}
+
+func TestSetAssociationOverride_Exclude(t *testing.T) {
+	// excludeを指定したWordは、Sentence中に出現しても紐づけられず、
+	// 紐づけを再構築しても指定が保たれることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("りんご", "", 1)
+	sentenceId := insertIntoSentences("りんごを食べた", 1)
+	assert.NoError(t, su.ReAssociateSentenceWithAllWords(1, sentenceId))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
+
+	rec := setTestAssociationOverride(t, sentenceId, wordId, model.AssociationOverrideKindExclude)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(`{"sentence_id": %d, "word_id": %d, "kind": "exclude"}`, sentenceId, wordId),
+		rec.Body.String(),
+	)
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordId))
+
+	// Word側、Sentence側のどちらから再構築しても紐づけられない
+	assert.NoError(t, wu.ReAssociateWordWithAllSentences(1, wordId))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordId))
+	assert.NoError(t, su.ReAssociateSentenceWithAllWords(1, sentenceId))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordId))
+
+	// リンクも作成されない
+	sentenceWithLink, err := au.GetSentenceWithLinkById(1, sentenceId)
+	assert.NoError(t, err)
+	assert.Empty(t, sentenceWithLink.Annotations)
+
+	// 指定を削除すると、自動での紐づけに戻る
+	_, rec = ExecController(
+		t,
+		"/sentences/:sentenceId/word-overrides/:wordId",
+		sc.DeleteAssociationOverride,
+		HttpMethod(http.MethodDelete),
+		Params(
+			[]string{"sentenceId", "wordId"},
+			[]string{strconv.FormatUint(sentenceId, 10), strconv.FormatUint(wordId, 10)},
+		),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
+}
+
+func TestSetAssociationOverride_Pin(t *testing.T) {
+	// pinを指定したWordは、Sentence中に出現しなくても紐づけられ、
+	// 紐づけを再構築しても指定が保たれることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("果物", "", 1)
+	sentenceId := insertIntoSentences("りんごを食べた", 1)
+
+	rec := setTestAssociationOverride(t, sentenceId, wordId, model.AssociationOverrideKindPin)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
+
+	assert.NoError(t, su.ReAssociateSentenceWithAllWords(1, sentenceId))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
+	assert.NoError(t, wu.ReAssociateWordWithAllSentences(1, wordId))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId))
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId/word-overrides",
+		sc.GetAssociationOverrides,
+		http.StatusOK,
+		fmt.Sprintf(`[{"sentence_id": %d, "word_id": %d, "kind": "pin"}]`, sentenceId, wordId),
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentenceId, 10)},
+		),
+	)
+}
+
+func TestSetAssociationOverride_WithInvalidKind(t *testing.T) {
+	// kindがpin、exclude以外の場合422が返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("りんご", "", 1)
+	sentenceId := insertIntoSentences("りんごを食べた", 1)
+
+	rec := setTestAssociationOverride(t, sentenceId, wordId, "hide")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestSetAssociationOverride_WithInvalidUser(t *testing.T) {
+	// ログイン中のUserに紐づかないWordには指定できないことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("りんご", "", 2)
+	sentenceId := insertIntoSentences("りんごを食べた", 1)
+
+	rec := setTestAssociationOverride(t, sentenceId, wordId, model.AssociationOverrideKindPin)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, notFoundErrorJSON("word"), rec.Body.String())
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordId))
+}
@@ -0,0 +1,224 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSentence_WithTranslationsAndSource(t *testing.T) {
+	// 訳文、出典、メモを付けてSentenceを作成でき、取得時にも返ることをテスト
+	DeleteAllFromSentences()
+
+	_, rec := ExecController(
+		t,
+		"/sentences",
+		sc.CreateSentence,
+		HttpMethod(http.MethodPost),
+		Body(`
+			{
+				"sentence": "りんごを食べた",
+				"translations": [
+					{"language": "fr", "translation": "J'ai mangé une pomme."},
+					{"language": "en", "translation": "I ate an apple."}
+				],
+				"source": {"title": "絵本", "episode": "第1話", "timestamp_seconds": 90},
+				"notes": "過去形"
+			}`,
+		),
+	)
+	sentence := toSentenceResponse(rec)
+
+	// 訳文は言語順に返る
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"sentence": "りんごを食べた",
+			"translations": [
+				{"language": "en", "translation": "I ate an apple."},
+				{"language": "fr", "translation": "J'ai mangé une pomme."}
+			],
+			"source": {"title": "絵本", "episode": "第1話", "timestamp_seconds": 90},
+			"notes": "過去形",
+			"user_id": 1
+		}`,
+		sentence.Id,
+	)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, expectedResponse, rec.Body.String())
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId",
+		sc.GetSentenceById,
+		http.StatusOK,
+		expectedResponse,
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+	)
+}
+
+func TestCreateMultipleSentences_WithTranslations(t *testing.T) {
+	// 一括作成でも、Sentenceごとに訳文と出典を指定できることをテスト
+	DeleteAllFromSentences()
+
+	sentenceId1 := GetNextSentencesSequenceValue()
+	sentenceId2 := sentenceId1 + 1
+
+	reqBody := `{
+		"sentences": [
+				{"sentence": "りんごを食べた", "translations": [{"language": "en", "translation": "I ate an apple."}]},
+				{"sentence": "みかんを食べた", "source": {"url": "https://example.com/oranges"}}
+			]
+		}`
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"sentence": "りんごを食べた",
+				"translations": [{"language": "en", "translation": "I ate an apple."}],
+				"user_id": 1
+			},
+			{
+				"id": %d,
+				"sentence": "みかんを食べた",
+				"source": {"url": "https://example.com/oranges"},
+				"user_id": 1
+			}
+		]`,
+		sentenceId1,
+		sentenceId2,
+	)
+
+	DoSimpleTest(
+		t,
+		"/sentences/multiple",
+		sc.CreateMultipleSentences,
+		http.StatusCreated,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+}
+
+func TestUpdateSentence_ReplacesTranslations(t *testing.T) {
+	// 更新時には訳文がリクエストのもので置き換えられ、省略した出典、メモは消えることをテスト
+	DeleteAllFromSentences()
+
+	_, rec := ExecController(
+		t,
+		"/sentences",
+		sc.CreateSentence,
+		HttpMethod(http.MethodPost),
+		Body(`
+			{
+				"sentence": "りんごを食べた",
+				"translations": [{"language": "en", "translation": "I ate an apple."}],
+				"source": {"title": "絵本"},
+				"notes": "過去形"
+			}`,
+		),
+	)
+	sentence := toSentenceResponse(rec)
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId",
+		sc.UpdateSentence,
+		http.StatusAccepted,
+		fmt.Sprintf(`
+			{
+				"id": %d,
+				"sentence": "りんごを食べた",
+				"translations": [{"language": "de", "translation": "Ich habe einen Apfel gegessen."}],
+				"user_id": 1
+			}`,
+			sentence.Id,
+		),
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"sentence": "りんごを食べた", "translations": [{"language": "de", "translation": "Ich habe einen Apfel gegessen."}]}`),
+	)
+}
+
+func TestGetAllSentences_WithSourceFilter(t *testing.T) {
+	// sourceを指定した場合、出典の名前またはURLが一致するSentenceのみが返ることをテスト
+	DeleteAllFromSentences()
+
+	for _, body := range []string{
+		`{"sentence": "りんごを食べた", "source": {"title": "絵本"}}`,
+		`{"sentence": "みかんを食べた", "source": {"title": "図鑑", "url": "https://example.com/oranges"}}`,
+		`{"sentence": "ぶどうを食べた"}`,
+	} {
+		_, rec := ExecController(
+			t,
+			"/sentences",
+			sc.CreateSentence,
+			HttpMethod(http.MethodPost),
+			Body(body),
+		)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+	firstId := GetCurrentSentencesSequenceValue() - 2
+
+	DoSimpleTest(
+		t,
+		"/sentences",
+		sc.GetAllSentences,
+		http.StatusOK,
+		fmt.Sprintf(`
+			[
+				{"id": %d, "sentence": "りんごを食べた", "annotations": [], "source": {"title": "絵本"}, "user_id": 1}
+			]`,
+			firstId,
+		),
+		QueryParams(
+			[]string{"source"},
+			[][]string{{"絵本"}},
+		),
+	)
+
+	DoSimpleTest(
+		t,
+		"/sentences/count",
+		sc.GetSentencesCount,
+		http.StatusOK,
+		`{"count": 1}`,
+		QueryParams(
+			[]string{"source"},
+			[][]string{{"https://example.com/oranges"}},
+		),
+	)
+}
+
+func TestCreateSentence_WithInvalidSource(t *testing.T) {
+	// 出典のURLが不正な場合、422が返りSentenceは作成されないことをテスト
+	DeleteAllFromSentences()
+
+	_, rec := ExecController(
+		t,
+		"/sentences",
+		sc.CreateSentence,
+		HttpMethod(http.MethodPost),
+		Body(`{"sentence": "りんごを食べた", "source": {"url": "not a url"}}`),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/sentences",
+		sc.GetAllSentences,
+		http.StatusOK,
+		"null",
+	)
+}
@@ -0,0 +1,267 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addTestTagToWord(t *testing.T, wordId, tagId uint64) {
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/tags/:tagId",
+		tc.AddTagToWord,
+		Params(
+			[]string{"wordId", "tagId"},
+			[]string{strconv.FormatUint(wordId, 10), strconv.FormatUint(tagId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+	)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func addTestTagToSentence(t *testing.T, sentenceId, tagId uint64) {
+	_, rec := ExecController(
+		t,
+		"/sentences/:sentenceId/tags/:tagId",
+		tc.AddTagToSentence,
+		Params(
+			[]string{"sentenceId", "tagId"},
+			[]string{strconv.FormatUint(sentenceId, 10), strconv.FormatUint(tagId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+	)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestGetAllTags(t *testing.T) {
+	// ログイン中のUserのタグのみ、名前順に取得できることをテスト
+	DeleteAllFromTags()
+
+	verbTag := createTestTag(t, "verb")
+	jlptTag := createTestTag(t, "JLPT N3")
+	db.Exec(`
+		INSERT INTO tags
+		(id, name, user_id)
+		VALUES(nextval('tag_id_seq'), 'other', 2);
+	`)
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{"id": %d, "name": "JLPT N3", "user_id": 1},
+			{"id": %d, "name": "verb", "user_id": 1}
+		]`,
+		jlptTag.Id,
+		verbTag.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/tags",
+		tc.GetAllTags,
+		http.StatusOK,
+		expectedResponse,
+	)
+}
+
+func TestCreateTag_Duplicate(t *testing.T) {
+	// 同じ名前のタグを追加しようとした場合、409と既存のタグのidが返ることをテスト
+	DeleteAllFromTags()
+
+	tag := createTestTag(t, "verb")
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"code": "conflict",
+			"message": "tag already exists",
+			"details": {"existing_id": %d},
+			"request_id": ""
+		}`,
+		tag.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/tags",
+		tc.CreateTag,
+		http.StatusConflict,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(`{"name": "verb"}`),
+	)
+}
+
+func TestUpdateTag_WithInvalidUser(t *testing.T) {
+	// 他のUserのタグは更新できないことをテスト
+	DeleteAllFromTags()
+
+	tag := createTestTag(t, "verb")
+
+	DoSimpleTest(
+		t,
+		"/tags/:tagId",
+		tc.UpdateTag,
+		http.StatusNotFound,
+		notFoundErrorJSON("tag"),
+		Params(
+			[]string{"tagId"},
+			[]string{strconv.FormatUint(tag.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"name": "noun"}`),
+		LoginUserId(2),
+	)
+}
+
+func TestAddTagToWord(t *testing.T) {
+	// Wordに付けたタグを取得でき、タグの削除でWordからも外れることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromTags()
+
+	word := createTestWord(t, "食べる", "")
+	tag := createTestTag(t, "verb")
+
+	addTestTagToWord(t, word.Id, tag.Id)
+	// 既に付いているタグを付けてもエラーとしない
+	addTestTagToWord(t, word.Id, tag.Id)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/tags",
+		tc.GetWordTags,
+		http.StatusOK,
+		fmt.Sprintf(`[{"id": %d, "name": "verb", "user_id": 1}]`, tag.Id),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+	)
+
+	_, rec := ExecController(
+		t,
+		"/tags/:tagId",
+		tc.DeleteTag,
+		Params(
+			[]string{"tagId"},
+			[]string{strconv.FormatUint(tag.Id, 10)},
+		),
+		HttpMethod(http.MethodDelete),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/tags",
+		tc.GetWordTags,
+		http.StatusOK,
+		`[]`,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+	)
+}
+
+func TestAddTagToWord_WithInvalidUser(t *testing.T) {
+	// 他のUserのタグはWordに付けられないことをテスト
+	DeleteAllFromWords()
+	DeleteAllFromTags()
+
+	word := createTestWord(t, "食べる", "")
+	var otherTagId uint64
+	db.QueryRow(`
+		INSERT INTO tags
+		(id, name, user_id)
+		VALUES(nextval('tag_id_seq'), 'other', 2)
+		RETURNING id;
+	`).Scan(&otherTagId)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/tags/:tagId",
+		tc.AddTagToWord,
+		http.StatusNotFound,
+		notFoundErrorJSON("tag"),
+		Params(
+			[]string{"wordId", "tagId"},
+			[]string{strconv.FormatUint(word.Id, 10), strconv.FormatUint(otherTagId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+	)
+}
+
+func TestGetAllWords_WithTagFilter(t *testing.T) {
+	// tag_idを指定した場合、そのタグが付いたWordのみが返り、total_countも絞り込んだ件数となることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromTags()
+
+	taggedWord := createTestWord(t, "食べる", "")
+	createTestWord(t, "りんご", "")
+	tag := createTestTag(t, "verb")
+	addTestTagToWord(t, taggedWord.Id, tag.Id)
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"words": [
+				{"id": %d, "word": "食べる", "memo": "", "user_id": 1}
+			],
+			"next_cursor": null,
+			"total_count": 1
+		}`,
+		taggedWord.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.GetAllWords,
+		http.StatusOK,
+		expectedResponse,
+		QueryParams(
+			[]string{"tag_id"},
+			[][]string{{strconv.FormatUint(tag.Id, 10)}},
+		),
+	)
+}
+
+func TestGetAllWords_WithInvalidTagFilter(t *testing.T) {
+	// tag_idが正の整数でない場合422が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"tag_id"},
+			[][]string{{"abc"}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestGetSentencesCount_WithTagFilter(t *testing.T) {
+	// tag_idを指定した場合、そのタグが付いたSentenceの件数が返ることをテスト
+	DeleteAllFromSentences()
+	DeleteAllFromTags()
+
+	taggedSentence := createTestSentence(t, "りんごを食べた")
+	createTestSentence(t, "みかんを食べた")
+	tag := createTestTag(t, "fruit")
+	addTestTagToSentence(t, taggedSentence.Id, tag.Id)
+
+	DoSimpleTest(
+		t,
+		"/sentences/count",
+		sc.GetSentencesCount,
+		http.StatusOK,
+		`{ "count": 1 }`,
+		QueryParams(
+			[]string{"tag_id"},
+			[][]string{{strconv.FormatUint(tag.Id, 10)}},
+		),
+	)
+}
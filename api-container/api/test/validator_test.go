@@ -0,0 +1,232 @@
+package test
+
+import (
+	"api/controller"
+	"api/model"
+	"api/usecase"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getFieldValidationErrors(t *testing.T, req interface{}) []model.FieldValidationError {
+	err := controller.NewRequestValidator().Validate(req)
+	if err == nil {
+		return nil
+	}
+
+	var usecaseErr *usecase.Error
+	if !assert.True(t, errors.As(err, &usecaseErr)) {
+		return nil
+	}
+	assert.Equal(t, usecase.ErrorKindValidation, usecaseErr.Kind)
+
+	fieldErrors, ok := usecaseErr.Details.([]model.FieldValidationError)
+	assert.True(t, ok)
+
+	return fieldErrors
+}
+
+func TestRequestValidator_Word(t *testing.T) {
+	// Wordのリクエストが、DBのカラム長や空白、制御文字について検証されることをテスト
+	testCases := []struct {
+		req      model.WordCreationRequest
+		expected []model.FieldValidationError
+	}{
+		{
+			req: model.WordCreationRequest{Word: strings.Repeat("あ", 100), Memo: "1行目\n2行目"},
+		},
+		{
+			req:      model.WordCreationRequest{Word: ""},
+			expected: []model.FieldValidationError{{Field: "word", Reason: "required"}},
+		},
+		{
+			req:      model.WordCreationRequest{Word: " りんご"},
+			expected: []model.FieldValidationError{{Field: "word", Reason: "must not have leading or trailing whitespace"}},
+		},
+		{
+			req:      model.WordCreationRequest{Word: "りん\x00ご"},
+			expected: []model.FieldValidationError{{Field: "word", Reason: "must not contain control characters"}},
+		},
+		{
+			req: model.WordCreationRequest{Word: strings.Repeat("あ", 101), Memo: strings.Repeat("a", 501)},
+			expected: []model.FieldValidationError{
+				{Field: "word", Reason: "must be at most 100 characters"},
+				{Field: "memo", Reason: "must be at most 500 characters"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, getFieldValidationErrors(t, &testCase.req))
+	}
+}
+
+func TestRequestValidator_Pronunciation(t *testing.T) {
+	// Wordの読み、品詞、アクセントが検証されることをテスト
+	pitchAccent := func(n int) *int { return &n }
+
+	testCases := []struct {
+		req      model.WordCreationRequest
+		expected []model.FieldValidationError
+	}{
+		{
+			req: model.WordCreationRequest{Word: "東京", Reading: "とうきょう", PartOfSpeech: "noun", PitchAccent: pitchAccent(0)},
+		},
+		{
+			// 拗音は1モーラとなるため、「とうきょう」は4モーラ
+			req: model.WordCreationRequest{Word: "東京", Reading: "トーキョー", PitchAccent: pitchAccent(4)},
+		},
+		{
+			req:      model.WordCreationRequest{Word: "東京", Reading: "tokyo"},
+			expected: []model.FieldValidationError{{Field: "reading", Reason: "must contain only kana"}},
+		},
+		{
+			req:      model.WordCreationRequest{Word: "東京", Reading: "とうきょう", PitchAccent: pitchAccent(5)},
+			expected: []model.FieldValidationError{{Field: "pitch_accent", Reason: "must be at most the number of morae in reading"}},
+		},
+		{
+			req:      model.WordCreationRequest{Word: "東京", PitchAccent: pitchAccent(0)},
+			expected: []model.FieldValidationError{{Field: "reading", Reason: "required"}},
+		},
+		{
+			req:      model.WordCreationRequest{Word: "東京", PartOfSpeech: "verb"},
+			expected: []model.FieldValidationError{{Field: "part_of_speech", Reason: "must be one of noun, godan_verb, ichidan_verb, suru_verb, kuru_verb, i_adjective, na_adjective, adverb, other"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, getFieldValidationErrors(t, &testCase.req))
+	}
+}
+
+func TestRequestValidator_WordSense(t *testing.T) {
+	// 意味のリクエストで、言語タグと文体が検証されることをテスト
+	assert.Nil(t, getFieldValidationErrors(t, &model.WordSenseRequest{Gloss: "to hang", Language: "en-US", Register: "formal"}))
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{
+			{Field: "language", Reason: "must be a BCP 47 language tag"},
+			{Field: "register", Reason: "must be one of formal, informal, polite, honorific, humble, colloquial, slang, literary, archaic"},
+		},
+		getFieldValidationErrors(t, &model.WordSenseRequest{Gloss: "to hang", Language: "english", Register: "rude"}),
+	)
+}
+
+func TestRequestValidator_SentenceTranslationAndSource(t *testing.T) {
+	// Sentenceの訳文と出典が検証され、同じ言語の訳文が重複する場合は不正となることをテスト
+	timestampSeconds := 90
+	assert.Nil(t, getFieldValidationErrors(t, &model.SentenceCreationRequest{
+		Sentence:     "りんごを食べた",
+		Translations: []model.SentenceTranslationRequest{{Language: "en", Translation: "I ate an apple."}},
+		Source:       &model.SentenceSourceRequest{Title: "絵本", Url: "https://example.com/books/1", TimestampSeconds: &timestampSeconds},
+		Notes:        "1行目\n2行目",
+	}))
+
+	timestampSeconds = -1
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{
+			{Field: "translations", Reason: "must not contain duplicates"},
+			{Field: "source.url", Reason: "must be a valid URL"},
+			{Field: "source.timestamp_seconds", Reason: "must be at least 0"},
+		},
+		getFieldValidationErrors(t, &model.SentenceUpdateRequest{
+			Sentence: "りんごを食べた",
+			Translations: []model.SentenceTranslationRequest{
+				{Language: "en", Translation: "I ate an apple."},
+				{Language: "en", Translation: "I had an apple."},
+			},
+			Source: &model.SentenceSourceRequest{Url: "example.com", TimestampSeconds: &timestampSeconds},
+		}),
+	)
+}
+
+func TestRequestValidator_Multiple(t *testing.T) {
+	// 一括作成のリクエストでは、項目ごとの位置がfieldとして返ることをテスト
+	req := model.MultipleSentencesCreationRequest{
+		Sentences: []model.SentenceCreationRequest{
+			{Sentence: "りんごを食べた"},
+			{Sentence: "  "},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "sentences[1].sentence", Reason: "must not have leading or trailing whitespace"}},
+		getFieldValidationErrors(t, &req),
+	)
+}
+
+func TestRequestValidator_DuplicateWords(t *testing.T) {
+	// 一括作成のリクエスト内で同じWordが重複する場合、不正となることをテスト
+	req := model.MultipleWordsCreationRequest{
+		Words: []model.WordCreationRequest{
+			{Word: "りんご", Memo: "1件目"},
+			{Word: "みかん"},
+			{Word: "りんご", Memo: "3件目"},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "words", Reason: "must not contain duplicates"}},
+		getFieldValidationErrors(t, &req),
+	)
+}
+
+func TestRequestValidator_Others(t *testing.T) {
+	// Word、Sentence以外のリクエストも検証されることをテスト
+	grade := uint64(6)
+
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "notation", Reason: "required"}},
+		getFieldValidationErrors(t, &model.NotationCreationRequest{}),
+	)
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "kind", Reason: "must be one of pin, exclude"}},
+		getFieldValidationErrors(t, &model.AssociationOverrideRequest{Kind: "hide"}),
+	)
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "grade", Reason: "must be at most 5"}},
+		getFieldValidationErrors(t, &model.ReviewRequest{Grade: &grade}),
+	)
+	assert.Equal(
+		t,
+		[]model.FieldValidationError{{Field: "refresh_token", Reason: "required"}},
+		getFieldValidationErrors(t, &model.TokenRequest{GrantType: "refresh_token"}),
+	)
+}
+
+func TestCreateNotation_WithEmptyNotation(t *testing.T) {
+	// 空のNotationは全てのSentenceに一致してしまうため、作成できず422が返ることをテスト
+	DoSimpleTest(
+		t,
+		"/words/:wordId/notations",
+		nc.CreateNotation,
+		http.StatusUnprocessableEntity,
+		`{
+			"code": "validation_failed",
+			"message": "1 field(s) are invalid",
+			"details": [
+				{
+					"field": "notation",
+					"reason": "required"
+				}
+			],
+			"request_id": ""
+		}`,
+		HttpMethod(http.MethodPost),
+		Params(
+			[]string{"wordId"},
+			[]string{"1"},
+		),
+		Body(`{"notation": ""}`),
+	)
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRelation(t *testing.T) {
+	// Word同士の関係を追加でき、bidirectionalを省略した場合は種類ごとの既定値となることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "大きい", "")
+	synonym := createTestWord(t, "巨大", "")
+	seeAlso := createTestWord(t, "大きな", "")
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/relations",
+		wrc.CreateRelation,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym"}`, synonym.Id)),
+	)
+	synonymRelation := toWordRelationResponse(rec)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(
+			`{"id": %d, "word_id": %d, "related_word_id": %d, "kind": "synonym", "bidirectional": true}`,
+			synonymRelation.Id,
+			word.Id,
+			synonym.Id,
+		),
+		rec.Body.String(),
+	)
+
+	seeAlsoRelation := createTestWordRelation(t, seeAlso.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "see_also"}`, word.Id))
+
+	// 関係するWordの側からも、向きを保ったまま取得できる
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations",
+		wrc.GetRelations,
+		http.StatusOK,
+		fmt.Sprintf(`
+			[
+				{"id": %d, "word_id": %d, "related_word_id": %d, "kind": "synonym", "bidirectional": true},
+				{"id": %d, "word_id": %d, "related_word_id": %d, "kind": "see_also", "bidirectional": false}
+			]`,
+			synonymRelation.Id,
+			word.Id,
+			synonym.Id,
+			seeAlsoRelation.Id,
+			seeAlso.Id,
+			word.Id,
+		),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+	)
+}
+
+func TestCreateRelation_Duplicate(t *testing.T) {
+	// 2つのWordの間に、逆向きも含め同じ種類の関係がある場合、409と既存の関係のidが返ることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "大きい", "")
+	antonym := createTestWord(t, "小さい", "")
+	relation := createTestWordRelation(t, word.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "antonym"}`, antonym.Id))
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations",
+		wrc.CreateRelation,
+		http.StatusConflict,
+		fmt.Sprintf(`
+			{
+				"code": "conflict",
+				"message": "word relation already exists",
+				"details": {"existing_id": %d},
+				"request_id": ""
+			}`,
+			relation.Id,
+		),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(antonym.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"related_word_id": %d, "kind": "antonym"}`, word.Id)),
+	)
+}
+
+func TestCreateRelation_WithInvalidRequest(t *testing.T) {
+	// 自身との関係と、他のUserのWordとの関係は追加できないことをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "大きい", "")
+	otherUserWordId := insertIntoWords("小さい", "", 2)
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/relations",
+		wrc.CreateRelation,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym"}`, word.Id)),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations",
+		wrc.CreateRelation,
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"related_word_id": %d, "kind": "antonym"}`, otherUserWordId)),
+	)
+}
+
+func TestUpdateRelation(t *testing.T) {
+	// 関係の種類を変更でき、bidirectionalを省略した場合は変更されないことをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "見る", "")
+	relatedWord := createTestWord(t, "観る", "")
+	relation := createTestWordRelation(t, word.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym", "bidirectional": false}`, relatedWord.Id))
+
+	// 関係するWordの側からも更新できる
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations/:relationId",
+		wrc.UpdateRelation,
+		http.StatusAccepted,
+		fmt.Sprintf(
+			`{"id": %d, "word_id": %d, "related_word_id": %d, "kind": "see_also", "bidirectional": false}`,
+			relation.Id,
+			word.Id,
+			relatedWord.Id,
+		),
+		Params(
+			[]string{"wordId", "relationId"},
+			[]string{strconv.FormatUint(relatedWord.Id, 10), strconv.FormatUint(relation.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"kind": "see_also"}`),
+	)
+}
+
+func TestDeleteRelation_WithOtherWord(t *testing.T) {
+	// 関係に含まれないWordのパスからは、関係を削除できないことをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "大きい", "")
+	relatedWord := createTestWord(t, "巨大", "")
+	otherWord := createTestWord(t, "小さい", "")
+	relation := createTestWordRelation(t, word.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym"}`, relatedWord.Id))
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations/:relationId",
+		wrc.DeleteRelation,
+		http.StatusNotFound,
+		notFoundErrorJSON("word relation"),
+		Params(
+			[]string{"wordId", "relationId"},
+			[]string{strconv.FormatUint(otherWord.Id, 10), strconv.FormatUint(relation.Id, 10)},
+		),
+		HttpMethod(http.MethodDelete),
+	)
+}
+
+func TestGetCompoundSuggestions(t *testing.T) {
+	// Wordに含まれるWordと、Wordを含むWordが、compoundの関係として提案されることをテスト
+	// 既にcompoundの関係があるWordと、Wordに含まれないWordは提案されない
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "日本語", "")
+	component := createTestWord(t, "にほん", "")
+	notation := createTestNotation(t, component.Id, "日本")
+	relatedComponent := createTestWord(t, "語", "")
+	compound := createTestWord(t, "日本語教師", "")
+	createTestWord(t, "にほんご", "")
+	createTestWordRelation(t, word.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "compound"}`, relatedComponent.Id))
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/relations/suggestions",
+		wrc.GetCompoundSuggestions,
+		http.StatusOK,
+		fmt.Sprintf(`
+			[
+				{"word_id": %d, "related_word_id": %d, "kind": "compound", "matched_text": "日本", "notation_id": %d},
+				{"word_id": %d, "related_word_id": %d, "kind": "compound", "matched_text": "日本語"}
+			]`,
+			word.Id,
+			component.Id,
+			notation.Id,
+			compound.Id,
+			word.Id,
+		),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+	)
+}
+
+func TestMergeWords_MovesRelations(t *testing.T) {
+	// Wordを統合した場合、統合元のWordの関係が統合先のWordに付け替えられ、
+	// 統合先のWordと重複する関係と、2つのWordの間の関係は削除されることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "大きい", "")
+	fromWord := createTestWord(t, "おおきい", "")
+	antonym := createTestWord(t, "小さい", "")
+	synonym := createTestWord(t, "巨大", "")
+	createTestWordRelation(t, word.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "antonym"}`, antonym.Id))
+	createTestWordRelation(t, antonym.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "antonym"}`, fromWord.Id))
+	createTestWordRelation(t, fromWord.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym"}`, word.Id))
+	movedRelation := createTestWordRelation(t, synonym.Id, fmt.Sprintf(`{"related_word_id": %d, "kind": "synonym"}`, fromWord.Id))
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, fromWord.Id)),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM word_relations
+		WHERE word_id = $1 OR related_word_id = $1;
+	`,
+		word.Id,
+	).Scan(&count)
+	assert.Equal(t, 2, count)
+
+	var relatedWordId uint64
+	db.QueryRow(`
+		SELECT related_word_id FROM word_relations
+		WHERE id = $1;
+	`,
+		movedRelation.Id,
+	).Scan(&relatedWordId)
+	assert.Equal(t, word.Id, relatedWordId)
+}
@@ -0,0 +1,308 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSense(t *testing.T) {
+	// Wordに意味を追加でき、positionを省略した場合は最後の意味となることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "かける", "")
+	first := createTestWordSense(t, word.Id, `{"gloss": "to hang", "language": "en", "part_of_speech": "ichidan_verb"}`)
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/senses",
+		wsc.CreateSense,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(`{"gloss": "電話をする", "language": "ja", "register": "colloquial"}`),
+	)
+	second := toWordSenseResponse(rec)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"word_id": %d,
+			"gloss": "電話をする",
+			"language": "ja",
+			"register": "colloquial",
+			"position": 2
+		}`,
+		second.Id,
+		word.Id,
+	)
+	assert.JSONEq(t, expectedResponse, rec.Body.String())
+
+	// Wordの取得時に、意味も表示順に返る
+	expectedResponse = fmt.Sprintf(`
+		{
+			"id": %d,
+			"word": "かける",
+			"memo": "",
+			"user_id": 1,
+			"senses": [
+				{"id": %d, "word_id": %d, "gloss": "to hang", "language": "en", "part_of_speech": "ichidan_verb", "position": 1},
+				{"id": %d, "word_id": %d, "gloss": "電話をする", "language": "ja", "register": "colloquial", "position": 2}
+			]
+		}`,
+		word.Id,
+		first.Id,
+		word.Id,
+		second.Id,
+		word.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId",
+		wc.GetWordById,
+		http.StatusOK,
+		expectedResponse,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+	)
+}
+
+func TestCreateSense_WithInvalidRequest(t *testing.T) {
+	// 言語タグや文体が不正な場合、422が返ることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "かける", "")
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/senses",
+		wsc.CreateSense,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(`{"gloss": "to hang", "language": "english", "register": "rude"}`),
+	)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestUpdateSense_WithOtherWord(t *testing.T) {
+	// 他のWordの意味は、パスのWordの意味として更新できないことをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "かける", "")
+	otherWord := createTestWord(t, "はし", "")
+	otherSense := createTestWordSense(t, otherWord.Id, `{"gloss": "bridge", "language": "en"}`)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/senses/:senseId",
+		wsc.UpdateSense,
+		http.StatusNotFound,
+		notFoundErrorJSON("word sense"),
+		Params(
+			[]string{"wordId", "senseId"},
+			[]string{strconv.FormatUint(word.Id, 10), strconv.FormatUint(otherSense.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"gloss": "chopsticks", "language": "en"}`),
+	)
+}
+
+func TestGetSenses_WithInvalidUser(t *testing.T) {
+	// 他のUserのWordの意味は取得できないことをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "かける", "")
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/senses",
+		wsc.GetSenses,
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		LoginUserId(2),
+	)
+}
+
+func TestSetSentenceSense(t *testing.T) {
+	// 紐づいているSentenceに意味を指定でき、意味の削除で指定も外れることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	word := createTestWord(t, "かける", "")
+	sense := createTestWordSense(t, word.Id, `{"gloss": "to hang", "language": "en"}`)
+	sentence := createTestSentence(t, "壁に絵をかける")
+
+	_, rec := ExecController(
+		t,
+		"/sentences/:sentenceId/word-senses/:wordId",
+		wsc.SetSentenceSense,
+		Params(
+			[]string{"sentenceId", "wordId"},
+			[]string{strconv.FormatUint(sentence.Id, 10), strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(fmt.Sprintf(`{"sense_id": %d}`, sense.Id)),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId/word-senses",
+		wsc.GetSentenceSenses,
+		http.StatusOK,
+		fmt.Sprintf(`[{"sentence_id": %d, "word_id": %d, "sense_id": %d}]`, sentence.Id, word.Id, sense.Id),
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+	)
+
+	_, rec = ExecController(
+		t,
+		"/words/:wordId/senses/:senseId",
+		wsc.DeleteSense,
+		Params(
+			[]string{"wordId", "senseId"},
+			[]string{strconv.FormatUint(word.Id, 10), strconv.FormatUint(sense.Id, 10)},
+		),
+		HttpMethod(http.MethodDelete),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId/word-senses",
+		wsc.GetSentenceSenses,
+		http.StatusOK,
+		`[]`,
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+	)
+}
+
+func TestSetSentenceSense_WithUnassociatedSentence(t *testing.T) {
+	// Wordと紐づいていないSentenceには意味を指定できず、422が返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	word := createTestWord(t, "かける", "")
+	sense := createTestWordSense(t, word.Id, `{"gloss": "to hang", "language": "en"}`)
+	sentence := createTestSentence(t, "橋を渡る")
+
+	_, rec := ExecController(
+		t,
+		"/sentences/:sentenceId/word-senses/:wordId",
+		wsc.SetSentenceSense,
+		Params(
+			[]string{"sentenceId", "wordId"},
+			[]string{strconv.FormatUint(sentence.Id, 10), strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(fmt.Sprintf(`{"sense_id": %d}`, sense.Id)),
+	)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestSetSentenceSense_RemainsAfterReAssociation(t *testing.T) {
+	// Sentenceの紐づけが再構築されても、指定した意味が残ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	word := createTestWord(t, "かける", "")
+	sense := createTestWordSense(t, word.Id, `{"gloss": "to hang", "language": "en"}`)
+	sentence := createTestSentence(t, "壁に絵をかける")
+
+	_, rec := ExecController(
+		t,
+		"/sentences/:sentenceId/word-senses/:wordId",
+		wsc.SetSentenceSense,
+		Params(
+			[]string{"sentenceId", "wordId"},
+			[]string{strconv.FormatUint(sentence.Id, 10), strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(fmt.Sprintf(`{"sense_id": %d}`, sense.Id)),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Sentenceを更新し、紐づけを再構築する
+	_, rec = ExecController(
+		t,
+		"/sentences/:sentenceId",
+		sc.UpdateSentence,
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"sentence": "壁に時計をかける"}`),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	DoSimpleTest(
+		t,
+		"/sentences/:sentenceId/word-senses",
+		wsc.GetSentenceSenses,
+		http.StatusOK,
+		fmt.Sprintf(`[{"sentence_id": %d, "word_id": %d, "sense_id": %d}]`, sentence.Id, word.Id, sense.Id),
+		Params(
+			[]string{"sentenceId"},
+			[]string{strconv.FormatUint(sentence.Id, 10)},
+		),
+	)
+}
+
+func TestMergeWords_MovesSenses(t *testing.T) {
+	// Wordを統合した場合、統合元のWordの意味が統合先のWordの意味の後ろに移ることをテスト
+	DeleteAllFromWords()
+
+	word := createTestWord(t, "掛ける", "")
+	fromWord := createTestWord(t, "かける", "")
+	createTestWordSense(t, word.Id, `{"gloss": "to hang", "language": "en"}`)
+	fromSense := createTestWordSense(t, fromWord.Id, `{"gloss": "to multiply", "language": "en"}`)
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, fromWord.Id)),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var wordId uint64
+	var position int
+	db.QueryRow(`
+		SELECT word_id, position FROM word_senses
+		WHERE id = $1;
+	`,
+		fromSense.Id,
+	).Scan(&wordId, &position)
+
+	assert.Equal(t, word.Id, wordId)
+	assert.Equal(t, 2, position)
+}
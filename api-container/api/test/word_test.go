@@ -4,24 +4,29 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetAllWords_WithNoRows(t *testing.T) {
-	// ログイン中のUserに紐づくWordが1つも無い場合nullが返ることをテスト
+	// ログイン中のUserに紐づくWordが1つも無い場合、空のページが返ることをテスト
 	// TODO ログイン機能
 	// とりあえずuser_id=1のWordのみ取得可能とする
 	DeleteAllFromWords()
 
-	// レコードが1つも無い場合、[]ではなくnullが返る
+	// レコードが1つも無い場合、wordsはnullではなく[]が返る
 	DoSimpleTest(
 		t,
 		"/words",
 		wc.GetAllWords,
 		http.StatusOK,
-		"null",
+		`{
+			"words": [],
+			"next_cursor": null,
+			"total_count": 0
+		}`,
 	)
 }
 
@@ -48,14 +53,18 @@ func TestGetAllWords(t *testing.T) {
 	`).Scan(&idWithUserId2)
 
 	expectedResponse := fmt.Sprintf(`
-		[
-			{
-				"id": %d,
-				"word": "testword",
-				"memo": "testmemo",
-				"user_id": 1
-			}
-		]`,
+		{
+			"words": [
+				{
+					"id": %d,
+					"word": "testword",
+					"memo": "testmemo",
+					"user_id": 1
+				}
+			],
+			"next_cursor": null,
+			"total_count": 1
+		}`,
 		idWithUserId1,
 	)
 
@@ -68,6 +77,129 @@ func TestGetAllWords(t *testing.T) {
 	)
 }
 
+func TestGetAllWords_WithCursor(t *testing.T) {
+	// limitとsortを指定した場合、next_cursorで続きのページを取得できることをテスト
+	DeleteAllFromWords()
+
+	insertIntoWords("c", "", 1)
+	insertIntoWords("a", "", 1)
+	insertIntoWords("b", "", 1)
+	insertIntoWords("d", "", 2)
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"limit", "sort", "order"},
+			[][]string{{"2"}, {"word"}, {"asc"}},
+		),
+	)
+	firstPage := toWordPageResponse(rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, len(firstPage.Words))
+	assert.Equal(t, "a", firstPage.Words[0].Word)
+	assert.Equal(t, "b", firstPage.Words[1].Word)
+	assert.NotNil(t, firstPage.NextCursor)
+	// total_countはlimitによらず、ログイン中のUserのWordの総数
+	assert.Equal(t, uint64(3), firstPage.TotalCount)
+
+	_, rec = ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"limit", "sort", "order", "cursor"},
+			[][]string{{"2"}, {"word"}, {"asc"}, {*firstPage.NextCursor}},
+		),
+	)
+	secondPage := toWordPageResponse(rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, len(secondPage.Words))
+	assert.Equal(t, "c", secondPage.Words[0].Word)
+	// 最後のページの場合、next_cursorはnull
+	assert.Nil(t, secondPage.NextCursor)
+}
+
+func TestGetAllWords_WithInvalidCursor(t *testing.T) {
+	// 不正なカーソルや、異なる並び順のカーソルを指定した場合422が返ることをテスト
+	DeleteAllFromWords()
+
+	insertIntoWords("a", "", 1)
+	insertIntoWords("b", "", 1)
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"cursor"},
+			[][]string{{"invalid"}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "validation_failed", toErrorResponse(rec).Code)
+
+	_, rec = ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"limit", "sort"},
+			[][]string{{"1"}, {"word"}},
+		),
+	)
+	nextCursor := toWordPageResponse(rec).NextCursor
+	assert.NotNil(t, nextCursor)
+
+	_, rec = ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"limit", "sort", "cursor"},
+			[][]string{{"1"}, {"created_at"}, {*nextCursor}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestGetAllWords_WithInvalidLimit(t *testing.T) {
+	// 数値でないlimitを指定した場合422が返ることをテスト
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.GetAllWords,
+		http.StatusUnprocessableEntity,
+		`
+		{
+			"code": "validation_failed",
+			"message": "invalid limit",
+			"request_id": ""
+		}`,
+		QueryParams(
+			[]string{"limit"},
+			[][]string{{"ten"}},
+		),
+	)
+}
+
+func TestGetAllWords_WithInvalidSort(t *testing.T) {
+	// 並び替えできない列を指定した場合422が返ることをテスト
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.GetAllWords,
+		QueryParams(
+			[]string{"sort"},
+			[][]string{{"memo"}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
 func TestGetWordById(t *testing.T) {
 	// ログイン中のUserに紐づくWordを取得できることをテスト
 	// TODO ログイン機能
@@ -123,8 +255,8 @@ func TestGetWordById_WithInvalidUser(t *testing.T) {
 		t,
 		"/words/:wordId",
 		wc.GetWordById,
-		http.StatusOK,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
 		Params(
 			[]string{"wordId"},
 			[]string{id},
@@ -180,6 +312,195 @@ func TestCreateWord(t *testing.T) {
 	assert.Equal(t, "testmemo", memo)
 }
 
+func TestCreateWord_Duplicate(t *testing.T) {
+	// 同じUserに同じWordが既に存在する場合、409と既存のWordのidが返り、
+	// 新規追加されないことをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("食べる", "既存のメモ", 1)
+	// 他のUserのWordとは重複しない
+	insertIntoWords("testword", "", 2)
+
+	reqBody := `{
+		"word": "食べる",
+		"memo": "新しいメモ"
+	}`
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"code": "conflict",
+			"message": "word already exists",
+			"details": {"existing_id": %d},
+			"request_id": ""
+		}`,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.CreateWord,
+		http.StatusConflict,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE user_id = 1;
+	`).Scan(&count)
+	assert.Equal(t, 1, count)
+
+	// 他のUserと同じWordは追加できる
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "testword"}`),
+	)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestCreateWord_DuplicateWithSkip(t *testing.T) {
+	// on_duplicate=skipの場合、既存のWordが変更されずに返ることをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("食べる", "既存のメモ", 1)
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"word": "食べる",
+			"memo": "既存のメモ",
+			"user_id": 1,
+			"already_existed": true
+		}`,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.CreateWord,
+		http.StatusOK,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "食べる", "memo": "新しいメモ"}`),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"skip"}},
+		),
+	)
+}
+
+func TestCreateWord_DuplicateWithUpsert(t *testing.T) {
+	// on_duplicate=upsertの場合、既存のWordのメモが更新されて返ることをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("食べる", "既存のメモ", 1)
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"word": "食べる",
+			"memo": "新しいメモ",
+			"user_id": 1,
+			"already_existed": true
+		}`,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.CreateWord,
+		http.StatusOK,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "食べる", "memo": "新しいメモ"}`),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"upsert"}},
+		),
+	)
+
+	var memo string
+	db.QueryRow(`
+		SELECT memo FROM words
+		WHERE id = $1;
+	`,
+		wordId,
+	).Scan(&memo)
+	assert.Equal(t, "新しいメモ", memo)
+
+	// 不正なon_duplicateは422となる
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "食べる"}`),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"replace"}},
+		),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestCreateWord_DuplicateWithUpsertKeepsOmittedFields(t *testing.T) {
+	// on_duplicate=upsertで読み、品詞、アクセントを省略した場合、既存の値が残ることをテスト
+	DeleteAllFromWords()
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "東京", "memo": "", "reading": "とうきょう", "part_of_speech": "noun", "pitch_accent": 0}`),
+	)
+	wordId := toWordResponse(rec).Id
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.CreateWord,
+		http.StatusOK,
+		fmt.Sprintf(`
+			{
+				"id": %d,
+				"word": "東京",
+				"memo": "首都",
+				"reading": "とうきょう",
+				"part_of_speech": "noun",
+				"pitch_accent": 0,
+				"user_id": 1,
+				"already_existed": true
+			}`,
+			wordId,
+		),
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "東京", "memo": "首都"}`),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"upsert"}},
+		),
+	)
+
+	var reading, partOfSpeech string
+	db.QueryRow(`
+		SELECT reading, part_of_speech FROM words
+		WHERE id = $1;
+	`,
+		wordId,
+	).Scan(&reading, &partOfSpeech)
+	assert.Equal(t, "とうきょう", reading)
+	assert.Equal(t, "noun", partOfSpeech)
+}
+
 func TestCreateMultipleWords(t *testing.T) {
 	// ログイン中のUserに紐づくWordを複数同時に作成できることをテスト
 	// TODO ログイン機能
@@ -254,8 +575,208 @@ func TestCreateMultipleWords(t *testing.T) {
 		wordId2,
 	).Scan(&word2, &memo2)
 
-	assert.Equal(t, "test word 2", word2)
-	assert.Equal(t, "test memo 2", memo2)
+	assert.Equal(t, "test word 2", word2)
+	assert.Equal(t, "test memo 2", memo2)
+}
+
+func TestCreateMultipleWords_WithInvalidItems(t *testing.T) {
+	// 複数同時に作成するWordのうち1件でも不正なものがある場合、
+	// どれも作成されず、不正な項目の一覧が返ることをテスト
+	DeleteAllFromWords()
+
+	// wordはVARCHAR(100)であるため、2件目は不正
+	// 3件目はwordが空のため不正
+	reqBody := fmt.Sprintf(`{
+		"words": [
+			{
+				"word": "test word 1",
+				"memo": "test memo 1"
+			},
+			{
+				"word": "%s",
+				"memo": "test memo 2"
+			},
+			{
+				"word": "",
+				"memo": "test memo 3"
+			}
+		]
+	}`,
+		strings.Repeat("あ", 101),
+	)
+
+	expectedResponse := `
+		{
+			"code": "validation_failed",
+			"message": "2 field(s) are invalid",
+			"details": [
+				{
+					"field": "words[1].word",
+					"reason": "must be at most 100 characters"
+				},
+				{
+					"field": "words[2].word",
+					"reason": "required"
+				}
+			],
+			"request_id": ""
+		}`
+
+	DoSimpleTest(
+		t,
+		"/words/multiple",
+		wc.CreateMultipleWords,
+		http.StatusUnprocessableEntity,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	// 1件目も追加されない
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE user_id = 1;
+	`).Scan(&count)
+
+	assert.Equal(t, 0, count)
+}
+
+func TestCreateMultipleWords_Duplicate(t *testing.T) {
+	// 複数同時に作成するWordのうち1件でも既存のWordと重複する場合、
+	// どれも作成されず、重複した項目と既存のWordのidが返ることをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("食べる", "", 1)
+
+	reqBody := `{
+		"words": [
+			{"word": "飲む"},
+			{"word": "食べる"}
+		]
+	}`
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"code": "conflict",
+			"message": "word already exists",
+			"details": {"existing_id": %d, "field": "words[1].word"},
+			"request_id": ""
+		}`,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/multiple",
+		wc.CreateMultipleWords,
+		http.StatusConflict,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE user_id = 1;
+	`).Scan(&count)
+	assert.Equal(t, 1, count)
+}
+
+func TestCreateMultipleWords_DuplicateWithSkip(t *testing.T) {
+	// on_duplicate=skipの場合、重複しないWordのみ作成され、
+	// 重複したWordは既存のものが返ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := insertIntoWords("食べる", "既存のメモ", 1)
+	sentenceId := insertIntoSentences("水を飲む", 1)
+
+	nextId := GetNextWordsSequenceValue()
+
+	reqBody := `{
+		"words": [
+			{"word": "飲む", "memo": "memo 1"},
+			{"word": "食べる", "memo": "memo 2"}
+		]
+	}`
+
+	expectedResponse := fmt.Sprintf(`
+		[
+			{
+				"id": %d,
+				"word": "飲む",
+				"memo": "memo 1",
+				"user_id": 1
+			},
+			{
+				"id": %d,
+				"word": "食べる",
+				"memo": "既存のメモ",
+				"user_id": 1,
+				"already_existed": true
+			}
+		]`,
+		nextId,
+		wordId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/multiple",
+		wc.CreateMultipleWords,
+		http.StatusCreated,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+		QueryParams(
+			[]string{"on_duplicate"},
+			[][]string{{"skip"}},
+		),
+	)
+
+	// 作成したWordはSentenceと紐づけられる
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, nextId))
+}
+
+func TestCreateMultipleWords_InSentences(t *testing.T) {
+	// 複数同時に作成したWordを含むSentenceがある場合、
+	// sentences_wordsに追加されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	sentenceId := insertIntoSentences("赤いりんごと黄色いレモンを食べた", 1)
+	otherSentenceId := insertIntoSentences("青いバナナを食べた", 1)
+
+	wordId1 := GetNextWordsSequenceValue()
+	wordId2 := wordId1 + 1
+
+	reqBody := `{
+		"words": [
+			{
+				"word": "りんご",
+				"memo": ""
+			},
+			{
+				"word": "レモン",
+				"memo": ""
+			}
+		]
+	}`
+
+	ExecController(
+		t,
+		"/words/multiple",
+		wc.CreateMultipleWords,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId1))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, wordId2))
+	assert.Equal(t, 0, getCountFromSentencesWords(otherSentenceId, wordId1))
+	assert.Equal(t, 0, getCountFromSentencesWords(otherSentenceId, wordId2))
 }
 
 func TestCreateWord_InSentences(t *testing.T) {
@@ -373,17 +894,140 @@ func TestCreateWord_InInvalidSentences(t *testing.T) {
 	assert.Equal(t, 0, count)
 }
 
-func TestCreateWord_RootWordCreated(t *testing.T) {
-	// 新規Wordを追加したとき、語幹のNotationも追加されることをテスト
+func TestCreateWord_ConjugationCreated(t *testing.T) {
+	// 新規Wordを追加したとき、活用形のNotationも追加されることをテスト
 
 	// TODO ログイン機能
 	// とりあえずuser_id=1のSentenceのみ作成可能とする
 	DeleteAllFromWords()
 	DeleteAllFromSentences()
 
-	// 「買う」の語幹「買」が追加される
+	// 「買う」の活用形「買わない」「買った」が追加される
 	wordId := createTestWord(t, "買う", "").Id
-	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "買"))
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "買わない"))
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "買った"))
+
+	// 語幹「買」は追加されない
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買"))
+}
+
+func TestCreateWord_NoConjugationForKanaWords(t *testing.T) {
+	// 仮名のみの語や「お」で始まる語は、語尾が「だ」「い」でも活用形のNotationが追加されないことをテスト
+	DeleteAllFromWords()
+
+	for _, word := range []string{"まだ", "ただ", "はい", "お願い"} {
+		wordId := createTestWord(t, word, "").Id
+
+		var count int
+		db.QueryRow(`
+			SELECT COUNT(*) FROM notations
+			WHERE word_id = $1
+				AND kind = 'conjugation';
+		`,
+			wordId,
+		).Scan(&count)
+		assert.Equal(t, 0, count, word)
+	}
+}
+
+func TestCreateWord_WithPronunciation(t *testing.T) {
+	// 読み、品詞、アクセントを指定してWordを作成できることをテスト
+	DeleteAllFromWords()
+
+	nextId := GetNextWordsSequenceValue()
+
+	reqBody := `{
+		"word": "東京",
+		"memo": "",
+		"reading": "とうきょう",
+		"part_of_speech": "noun",
+		"pitch_accent": 0
+	}`
+
+	// アクセントの0は平板型を表すため、省略されずに返る
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"word": "東京",
+			"memo": "",
+			"reading": "とうきょう",
+			"part_of_speech": "noun",
+			"pitch_accent": 0,
+			"user_id": 1
+		}`,
+		nextId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words",
+		wc.CreateWord,
+		http.StatusCreated,
+		expectedResponse,
+		HttpMethod(http.MethodPost),
+		Body(reqBody),
+	)
+
+	var reading string
+	var partOfSpeech string
+	var pitchAccent int
+	db.QueryRow(`
+		SELECT reading, part_of_speech, pitch_accent FROM words
+		WHERE id = $1;
+	`,
+		nextId,
+	).Scan(&reading, &partOfSpeech, &pitchAccent)
+
+	assert.Equal(t, "とうきょう", reading)
+	assert.Equal(t, "noun", partOfSpeech)
+	assert.Equal(t, 0, pitchAccent)
+}
+
+func TestCreateWord_WithInvalidPitchAccent(t *testing.T) {
+	// アクセントが読みのモーラ数を超える場合、422が返りWordが作成されないことをテスト
+	DeleteAllFromWords()
+
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "東京", "reading": "とうきょう", "pitch_accent": 5}`),
+	)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var count int
+	db.QueryRow(`SELECT COUNT(*) FROM words;`).Scan(&count)
+	assert.Equal(t, 0, count)
+}
+
+func TestCreateWord_ConjugationByPartOfSpeech(t *testing.T) {
+	// 品詞を指定した場合、語尾からの推定ではなく品詞に従って活用形のNotationが追加されることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	// 語尾からは形容動詞と推定されるが、名詞のため活用形は追加されない
+	_, rec := ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "きれい", "part_of_speech": "noun"}`),
+	)
+	wordId := toWordResponse(rec).Id
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "きれいな"))
+
+	// 語尾からは一段動詞と推定されるが、五段動詞として活用する
+	_, rec = ExecController(
+		t,
+		"/words",
+		wc.CreateWord,
+		HttpMethod(http.MethodPost),
+		Body(`{"word": "しめる", "part_of_speech": "godan_verb"}`),
+	)
+	wordId = toWordResponse(rec).Id
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "しめらない"))
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "しめない"))
 }
 
 func TestUpdateWord(t *testing.T) {
@@ -444,6 +1088,62 @@ func TestUpdateWord(t *testing.T) {
 	assert.Equal(t, "updated memo", memo)
 }
 
+func TestUpdateWord_Duplicate(t *testing.T) {
+	// 同じUserの他のWordと同じ語に更新しようとした場合、
+	// 409と既存のWordのidが返り、更新されないことをテスト
+	DeleteAllFromWords()
+
+	existingId := insertIntoWords("食べる", "", 1)
+	wordId := insertIntoWords("たべる", "", 1)
+
+	expectedResponse := fmt.Sprintf(`
+		{
+			"code": "conflict",
+			"message": "word already exists",
+			"details": {"existing_id": %d},
+			"request_id": ""
+		}`,
+		existingId,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId",
+		wc.UpdateWord,
+		http.StatusConflict,
+		expectedResponse,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"word": "食べる", "memo": ""}`),
+	)
+
+	var word string
+	db.QueryRow(`
+		SELECT word FROM words
+		WHERE id = $1;
+	`,
+		wordId,
+	).Scan(&word)
+	assert.Equal(t, "たべる", word)
+
+	// 自身と同じ語のままメモのみ更新することはできる
+	_, rec := ExecController(
+		t,
+		"/words/:wordId",
+		wc.UpdateWord,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPut),
+		Body(`{"word": "たべる", "memo": "updated memo"}`),
+	)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
 func TestUpdateWord_UpdatedAssociation(t *testing.T) {
 	// ログイン中のUserに紐づくWordを更新した時、
 	// sentences_wordsが正常に再構築されることをテスト
@@ -506,8 +1206,8 @@ func TestUpdateWord_WithInvalidUser(t *testing.T) {
 		t,
 		"/words/:wordId",
 		wc.UpdateWord,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
 		HttpMethod(http.MethodPut),
 		Params(
 			[]string{"wordId"},
@@ -530,17 +1230,17 @@ func TestUpdateWord_WithInvalidUser(t *testing.T) {
 	assert.Equal(t, "memo", memo)
 }
 
-func TestUpdateWord_RootWordCreated(t *testing.T) {
-	// Wordを更新したとき、語幹のNotationも更新されることをテスト
+func TestUpdateWord_ConjugationCreated(t *testing.T) {
+	// Wordを更新したとき、活用形のNotationも更新されることをテスト
 
 	// TODO ログイン機能
 	// とりあえずuser_id=1のSentenceのみ作成可能とする
 	DeleteAllFromWords()
 	DeleteAllFromSentences()
 
-	// 「買う」の語幹「買」が追加される
+	// 「買う」の活用形「買わない」が追加される
 	wordId := createTestWord(t, "買う", "").Id
-	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "買"))
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "買わない"))
 
 	reqBody := `{
 		"word": "赤い",
@@ -559,14 +1259,15 @@ func TestUpdateWord_RootWordCreated(t *testing.T) {
 		HttpMethod(http.MethodPut),
 	)
 
-	// 「買う」の語幹「買」が削除される
-	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買"))
-	// 「赤い」の語幹「赤」が追加される
-	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "赤"))
+	// 「買う」の活用形「買わない」が削除される
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買わない"))
+	// 「赤い」の活用形「赤くない」「赤かった」が追加される
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "赤くない"))
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "赤かった"))
 }
 
-func TestUpdateWord_RootWordCreated_OldRootWordNotExists(t *testing.T) {
-	// Wordを更新したとき、更新前のWordの語幹のNotationが存在しなくても、正常に更新されることをテスト
+func TestUpdateWord_ConjugationCreated_OldConjugationNotExists(t *testing.T) {
+	// Wordを更新したとき、更新前のWordの活用形のNotationが存在しなくても、正常に更新されることをテスト
 
 	// TODO ログイン機能
 	// とりあえずuser_id=1のSentenceのみ作成可能とする
@@ -574,7 +1275,7 @@ func TestUpdateWord_RootWordCreated_OldRootWordNotExists(t *testing.T) {
 	DeleteAllFromSentences()
 
 	wordId := insertIntoWords("買う", "", 1)
-	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買"))
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買わない"))
 
 	reqBody := `{
 		"word": "赤い",
@@ -593,8 +1294,223 @@ func TestUpdateWord_RootWordCreated_OldRootWordNotExists(t *testing.T) {
 		HttpMethod(http.MethodPut),
 	)
 
-	// 「赤い」の語幹「赤」が追加される
-	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "赤"))
+	// 「赤い」の活用形「赤くない」が追加される
+	assert.Equal(t, 1, getCountFromNotationsByNotation(wordId, "赤くない"))
+}
+
+func TestMergeWords(t *testing.T) {
+	// 統合元のWordのNotationとメモが統合先のWordに移り、
+	// 統合元のWordが紐づいていたSentenceが統合先のWordに紐づけなおされることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+	DeleteAllFromNotations()
+
+	sentence := createTestSentence(t, "きれいな花")
+	fromWord := createTestWord(t, "きれい", "memo 2")
+	word := createTestWord(t, "綺麗", "memo 1")
+	insertIntoNotations(fromWord.Id, "奇麗")
+	// 統合先のWordと同じNotationは移さない
+	insertIntoNotations(fromWord.Id, "綺麗")
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, fromWord.Id))
+	assert.Equal(t, 0, getCountFromSentencesWords(sentence.Id, word.Id))
+
+	// 既定ではメモは改行で連結される
+	expectedResponse := fmt.Sprintf(`
+		{
+			"id": %d,
+			"word": "綺麗",
+			"memo": "memo 1\nmemo 2",
+			"user_id": 1
+		}`,
+		word.Id,
+	)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		http.StatusOK,
+		expectedResponse,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, fromWord.Id)),
+	)
+
+	// 統合元のWordは削除される
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE id = $1;
+	`,
+		fromWord.Id,
+	).Scan(&count)
+	assert.Equal(t, 0, count)
+
+	// 統合元のWordとそのNotationが、統合先のWordのNotationとなる
+	assert.Equal(t, 1, getCountFromNotationsByNotation(word.Id, "きれい"))
+	assert.Equal(t, 1, getCountFromNotationsByNotation(word.Id, "奇麗"))
+	assert.Equal(t, 0, getCountFromNotationsByNotation(word.Id, "綺麗"))
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentence.Id, word.Id))
+}
+
+func TestMergeWords_MovesTagsAndDecks(t *testing.T) {
+	// 統合元のWordに付けられたTagと、統合元のWordを含むDeckが、統合先のWordに引き継がれることをテスト
+	// 統合先のWordに既に付けられたTag、統合先のWordを既に含むDeckは重複しない
+	DeleteAllFromWords()
+	DeleteAllFromTags()
+	DeleteAllFromDecks()
+
+	word := createTestWord(t, "綺麗", "")
+	fromWord := createTestWord(t, "きれい", "")
+
+	sharedTag := createTestTag(t, "adjective")
+	movedTag := createTestTag(t, "JLPT N4")
+	addTestTagToWord(t, word.Id, sharedTag.Id)
+	addTestTagToWord(t, fromWord.Id, sharedTag.Id)
+	addTestTagToWord(t, fromWord.Id, movedTag.Id)
+
+	sharedDeck := createTestDeck(t, `{"name": "N4"}`)
+	movedDeck := createTestDeck(t, `{"name": "N5"}`)
+	addTestWordToDeck(t, sharedDeck.Id, word.Id)
+	addTestWordToDeck(t, sharedDeck.Id, fromWord.Id)
+	addTestWordToDeck(t, movedDeck.Id, fromWord.Id)
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(word.Id, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, fromWord.Id)),
+	)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var tagIds []uint64
+	rows, _ := db.Query(`
+		SELECT tag_id FROM words_tags
+		WHERE word_id = $1
+		ORDER BY tag_id;
+	`,
+		word.Id,
+	)
+	for rows.Next() {
+		var tagId uint64
+		rows.Scan(&tagId)
+		tagIds = append(tagIds, tagId)
+	}
+	rows.Close()
+	assert.Equal(t, []uint64{sharedTag.Id, movedTag.Id}, tagIds)
+
+	var deckIds []uint64
+	rows, _ = db.Query(`
+		SELECT deck_id FROM decks_words
+		WHERE word_id = $1
+		ORDER BY deck_id;
+	`,
+		word.Id,
+	)
+	for rows.Next() {
+		var deckId uint64
+		rows.Scan(&deckId)
+		deckIds = append(deckIds, deckId)
+	}
+	rows.Close()
+	assert.Equal(t, []uint64{sharedDeck.Id, movedDeck.Id}, deckIds)
+}
+
+func TestMergeWords_WithMemoStrategy(t *testing.T) {
+	// memo_strategyで、統合後のメモを選べることをテスト
+	testCases := []struct {
+		memoStrategy string
+		expectedMemo string
+	}{
+		{memoStrategy: "keep", expectedMemo: "memo 1"},
+		{memoStrategy: "source", expectedMemo: "memo 2"},
+	}
+
+	for _, testCase := range testCases {
+		DeleteAllFromWords()
+
+		wordId := insertIntoWords("綺麗", "memo 1", 1)
+		fromWordId := insertIntoWords("きれい", "memo 2", 1)
+
+		_, rec := ExecController(
+			t,
+			"/words/:wordId/merge",
+			wc.MergeWords,
+			Params(
+				[]string{"wordId"},
+				[]string{strconv.FormatUint(wordId, 10)},
+			),
+			HttpMethod(http.MethodPost),
+			Body(fmt.Sprintf(`{"from_word_id": %d, "memo_strategy": "%s"}`, fromWordId, testCase.memoStrategy)),
+		)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, testCase.expectedMemo, toWordResponse(rec).Memo)
+	}
+}
+
+func TestMergeWords_WithInvalidUser(t *testing.T) {
+	// 統合元のWordがログイン中のUserに紐づかない場合、404が返り、
+	// どちらのWordも変更されないことをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("綺麗", "memo 1", 1)
+	fromWordId := insertIntoWords("きれい", "memo 2", 2)
+
+	DoSimpleTest(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, fromWordId)),
+	)
+
+	var count int
+	db.QueryRow(`
+		SELECT COUNT(*) FROM words
+		WHERE id = $1;
+	`,
+		fromWordId,
+	).Scan(&count)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "きれい"))
+}
+
+func TestMergeWords_WithSameWord(t *testing.T) {
+	// 統合元と統合先が同じWordの場合、422が返ることをテスト
+	DeleteAllFromWords()
+
+	wordId := insertIntoWords("綺麗", "", 1)
+
+	_, rec := ExecController(
+		t,
+		"/words/:wordId/merge",
+		wc.MergeWords,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		HttpMethod(http.MethodPost),
+		Body(fmt.Sprintf(`{"from_word_id": %d}`, wordId)),
+	)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 }
 
 func TestDeleteWord(t *testing.T) {
@@ -665,8 +1581,8 @@ func TestDeleteWord_WithInvalidUser(t *testing.T) {
 		t,
 		"/words/:wordId",
 		wc.DeleteWord,
-		http.StatusUnauthorized,
-		"{}",
+		http.StatusNotFound,
+		notFoundErrorJSON("word"),
 		HttpMethod(http.MethodDelete),
 		Params(
 			[]string{"wordId"},
@@ -714,6 +1630,48 @@ func TestDeleteWord_UpdatedAssociation(t *testing.T) {
 	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, wordId))
 }
 
+func TestCreateWord_OverlappingWord(t *testing.T) {
+	// 既存のWordを含むより長いWordを追加した時、
+	// Sentence中でリンクとならなくなった既存のWordの紐づけが削除されることをテスト
+	DeleteAllFromSentences()
+	DeleteAllFromWords()
+
+	japanWordId := createTestWord(t, "日本", "").Id
+	sentenceId := createTestSentence(t, "日本語を話す").Id
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, japanWordId))
+
+	japaneseWordId := createTestWord(t, "日本語", "").Id
+
+	// 「日本語を話す」中の「日本」は「日本語」の一部であるため、「日本語」のみが紐づく
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, japanWordId))
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, japaneseWordId))
+}
+
+func TestDeleteWord_RestoresOverlappedWord(t *testing.T) {
+	// より長いWordを削除した時、
+	// そのWordと重なり合っていたWordがSentenceに紐づくことをテスト
+	DeleteAllFromSentences()
+	DeleteAllFromWords()
+
+	japanWordId := createTestWord(t, "日本", "").Id
+	japaneseWordId := createTestWord(t, "日本語", "").Id
+	sentenceId := createTestSentence(t, "日本語を話す").Id
+	assert.Equal(t, 0, getCountFromSentencesWords(sentenceId, japanWordId))
+
+	ExecController(
+		t,
+		"/words/:wordId",
+		wc.DeleteWord,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(japaneseWordId, 10)},
+		),
+		HttpMethod(http.MethodDelete),
+	)
+
+	assert.Equal(t, 1, getCountFromSentencesWords(sentenceId, japanWordId))
+}
+
 func TestGetAssociatedSentencesWithLink(t *testing.T) {
 	// WordとSentenceがどちらもログイン中のuser_idに紐づく場合、
 	// Sentenceを、WordとNotationがaタグに変換された状態で取得できることをテスト
@@ -738,14 +1696,16 @@ func TestGetAssociatedSentencesWithLink(t *testing.T) {
 		RETURNING id;
 	`).Scan(&appleWordId)
 
+	var appleNotationId string
 	db.QueryRow(`
 		INSERT INTO notations
 		(id, word_id, notation)
 		VALUES
-		(nextval('word_id_seq'), $1, '林檎');
+		(nextval('word_id_seq'), $1, '林檎')
+		RETURNING id;
 		`,
 		appleWordId,
-	)
+	).Scan(&appleNotationId)
 
 	var lemonWordId string
 	db.QueryRow(`
@@ -755,26 +1715,21 @@ func TestGetAssociatedSentencesWithLink(t *testing.T) {
 		RETURNING id;
 	`).Scan(&lemonWordId)
 
+	var lemonNotationId string
 	db.QueryRow(`
 		INSERT INTO notations
 		(id, word_id, notation)
 		VALUES
-		(nextval('word_id_seq'), $1, '檸檬');
+		(nextval('word_id_seq'), $1, '檸檬')
+		RETURNING id;
 		`,
 		lemonWordId,
-	)
+	).Scan(&lemonNotationId)
 
-	db.QueryRow(`
-		INSERT INTO sentences_words
-		(sentence_id, word_id)
-		VALUES
-		($1, $2),
-		($1, $3);
-		`,
-		sentenceId,
-		appleWordId,
-		lemonWordId,
-	)
+	// リンクは紐づけの作成時に記録した出現箇所から作成されるため、usecaseで紐づける
+	parsedSentenceId, _ := strconv.ParseUint(sentenceId, 10, 64)
+	err := su.ReAssociateSentenceWithAllWords(1, parsedSentenceId)
+	assert.NoError(t, err)
 
 	// :wordId == appleWordId では、紐づくSentenceとして「リンゴと林檎、レモンと檸檬が同一であるとみなす」が取得される
 	// このうち「レモン」「檸檬」のwordIdはappleWordIdと同値でないが、
@@ -784,15 +1739,22 @@ func TestGetAssociatedSentencesWithLink(t *testing.T) {
 			{
 				"id": %s,
 				"sentence": "リンゴと林檎、レモンと檸檬が同一であるとみなす",
-				"sentence_with_link": "<a href=\"/words/%s\">リンゴ</a>と<a href=\"/words/%s\">林檎</a>、<a href=\"/words/%s\">レモン</a>と<a href=\"/words/%s\">檸檬</a>が同一であるとみなす",
+				"annotations": [
+					{"start": 0, "end": 3, "word_id": %s, "matched_text": "リンゴ"},
+					{"start": 4, "end": 6, "word_id": %s, "notation_id": %s, "matched_text": "林檎"},
+					{"start": 7, "end": 10, "word_id": %s, "matched_text": "レモン"},
+					{"start": 11, "end": 13, "word_id": %s, "notation_id": %s, "matched_text": "檸檬"}
+				],
 				"user_id": 1
 			}
 		]`,
 		sentenceId,
 		appleWordId,
 		appleWordId,
+		appleNotationId,
 		lemonWordId,
 		lemonWordId,
+		lemonNotationId,
 	)
 
 	DoSimpleTest(
@@ -806,4 +1768,36 @@ func TestGetAssociatedSentencesWithLink(t *testing.T) {
 			[]string{appleWordId},
 		),
 	)
-}
\ No newline at end of file
+}
+func TestUpdateWord_ManualNotationRemains(t *testing.T) {
+	// Wordを更新したとき、自動生成したNotationのみ削除され、
+	// ユーザーが入力したNotationは残ることをテスト
+	DeleteAllFromWords()
+	DeleteAllFromSentences()
+
+	wordId := createTestWord(t, "買う", "").Id
+	manualNotationId := createTestNotation(t, wordId, "かう").Id
+
+	reqBody := `{
+		"word": "赤い",
+		"memo": ""
+	}`
+
+	ExecController(
+		t,
+		"/words/:wordeId",
+		wc.UpdateWord,
+		Params(
+			[]string{"wordId"},
+			[]string{strconv.FormatUint(wordId, 10)},
+		),
+		Body(reqBody),
+		HttpMethod(http.MethodPut),
+	)
+
+	// 自動生成した「買わない」は削除される
+	assert.Equal(t, 0, getCountFromNotationsByNotation(wordId, "買わない"))
+	// ユーザーが入力した「かう」は削除されない
+	assert.Equal(t, 1, getCountFromNotations(manualNotationId))
+	assert.Equal(t, "manual", getKindFromNotations(manualNotationId))
+}
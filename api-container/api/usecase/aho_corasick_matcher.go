@@ -0,0 +1,117 @@
+package usecase
+
+// Aho-Corasick法により、部分文字列として含まれるかで判定するIMatcher
+// SubstringMatcherと同じ結果を返すが、termsの件数によらず文の長さに比例する時間で探索する
+// Prepareで全termsから1つのオートマトンを作成するため、多数のWordとNotationを多数の文から探索する場合に使用する
+type AhoCorasickMatcher struct{}
+
+func NewAhoCorasickMatcher() IMatcher {
+	return &AhoCorasickMatcher{}
+}
+
+// オートマトンの状態
+type ahoCorasickNode struct {
+	children map[rune]int
+	// この状態で一致しなかった場合に遷移する状態
+	fail int
+	// この状態に到達した時点で一致するtermのインデックス
+	// failを辿った先の状態で一致するものも含む
+	outputs []int
+}
+
+type preparedAhoCorasickMatcher struct {
+	nodes []ahoCorasickNode
+	// termごとのルーン数
+	termLengths []int
+}
+
+func (m *AhoCorasickMatcher) Prepare(terms []string) IPreparedMatcher {
+	pm := &preparedAhoCorasickMatcher{
+		nodes:       []ahoCorasickNode{{children: map[rune]int{}}},
+		termLengths: make([]int, len(terms)),
+	}
+
+	// 全termsからトライ木を作成
+	for termIndex, term := range terms {
+		// 空文字列は全ての文にマッチしてしまうため探索しない
+		if term == "" {
+			continue
+		}
+
+		state := 0
+		for _, r := range term {
+			next, ok := pm.nodes[state].children[r]
+			if !ok {
+				next = len(pm.nodes)
+				pm.nodes = append(pm.nodes, ahoCorasickNode{children: map[rune]int{}})
+				pm.nodes[state].children[r] = next
+			}
+			state = next
+			pm.termLengths[termIndex]++
+		}
+		pm.nodes[state].outputs = append(pm.nodes[state].outputs, termIndex)
+	}
+
+	// 幅優先探索で、浅い状態から順にfailを決める
+	queue := []int{}
+	for _, child := range pm.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for r, child := range pm.nodes[state].children {
+			fail := pm.nodes[state].fail
+			for {
+				if next, ok := pm.nodes[fail].children[r]; ok {
+					pm.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					pm.nodes[child].fail = 0
+					break
+				}
+				fail = pm.nodes[fail].fail
+			}
+
+			// failの先で一致するtermも、この状態で一致する
+			pm.nodes[child].outputs = append(pm.nodes[child].outputs, pm.nodes[pm.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return pm
+}
+
+func (pm *preparedAhoCorasickMatcher) FindAll(sentence string) []Match {
+	var matches []Match
+	state := 0
+	i := 0
+	for _, r := range sentence {
+		for {
+			if next, ok := pm.nodes[state].children[r]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = pm.nodes[state].fail
+		}
+
+		// i番目のルーンで終わるtermを全て記録
+		for _, termIndex := range pm.nodes[state].outputs {
+			matches = append(matches, Match{
+				Start:     i + 1 - pm.termLengths[termIndex],
+				End:       i + 1,
+				TermIndex: termIndex,
+			})
+		}
+		i++
+	}
+
+	sortMatches(matches)
+
+	return matches
+}
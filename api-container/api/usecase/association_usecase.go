@@ -4,6 +4,7 @@ import (
 	"api/model"
 	"api/repository"
 	"fmt"
+	"html"
 	"strings"
 )
 
@@ -12,8 +13,11 @@ type AssociationUsecase struct {
 	sr  repository.ISentenceRepository
 	swr repository.ISentencesWordsRepository
 	nr  repository.INotationRepository
+	dr  repository.IDeckRepository
 	wu  *WordUsecase
 	su  *SentenceUsecase
+	m   IMatcher
+	lr  *LinkResolver
 }
 
 func NewAssociationUsecase(
@@ -21,20 +25,26 @@ func NewAssociationUsecase(
 	sr repository.ISentenceRepository,
 	swr repository.ISentencesWordsRepository,
 	nr repository.INotationRepository,
+	dr repository.IDeckRepository,
+	uow repository.IUnitOfWork,
+	m IMatcher,
+	lr *LinkResolver,
 ) *AssociationUsecase {
-	wu := NewWordUsecase(wr, sr, swr, nr)
-	su := NewSentenceUsecase(sr, wr, swr, nr)
-	return &AssociationUsecase{wr, sr, swr, nr, wu, su}
+	// wu、suは取得のみに使用し、紐づけの再構築は行わないため、ジョブは扱わない
+	// Wordの意味、関係も扱わない
+	wu := NewWordUsecase(wr, sr, swr, nr, nil, dr, nil, nil, nil, uow, m, lr, AssociationModeSync)
+	su := NewSentenceUsecase(sr, wr, swr, nr, dr, nil, uow, m, lr, AssociationModeSync)
+	return &AssociationUsecase{wr, sr, swr, nr, dr, wu, su, m, lr}
 }
 
 func (au *AssociationUsecase) GetAssociatedSentencesByWordId(loginUserId, wordId uint64) ([]model.Sentence, error) {
-	// wordIdの所有者がloginUserIdでない場合ゼロ値を返す
+	// wordIdの所有者がloginUserIdでない場合エラーを返す
 	isWordOwner, err := au.wr.IsWordOwner(wordId, loginUserId)
 	if err != nil {
 		return []model.Sentence{}, err
 	}
 	if !isWordOwner {
-		return []model.Sentence{}, nil
+		return []model.Sentence{}, ErrWordNotFound
 	}
 
 	associatedUserSentences, err := au.swr.GetUserAssociatedSentencesByWordId(wordId)
@@ -51,17 +61,7 @@ func (au *AssociationUsecase) GetAssociatedSentencesWithLinkByWordId(loginUserId
 		return []model.SentenceWithLink{}, err
 	}
 
-	sentenceWithLinks := []model.SentenceWithLink{}
-	for _, sentence := range userAssociatedSentences {
-		sentenceWithLink, err := au.toSentenceWithLink(loginUserId, sentence)
-		if err != nil {
-			return []model.SentenceWithLink{}, err
-		}
-
-		sentenceWithLinks = append(sentenceWithLinks, sentenceWithLink)
-	}
-
-	return sentenceWithLinks, nil
+	return au.toSentencesWithLink(userAssociatedSentences)
 }
 
 func (au *AssociationUsecase) GetSentenceWithLinkById(loginUserId, sentenceId uint64) (model.SentenceWithLink, error) {
@@ -70,78 +70,131 @@ func (au *AssociationUsecase) GetSentenceWithLinkById(loginUserId, sentenceId ui
 		return model.SentenceWithLink{}, err
 	}
 
-	sentenceWithLink, err := au.toSentenceWithLink(loginUserId, sentence)
+	sentenceWithLinks, err := au.toSentencesWithLink([]model.Sentence{sentence})
 	if err != nil {
 		return model.SentenceWithLink{}, err
 	}
 
-	return sentenceWithLink, nil
+	return sentenceWithLinks[0], nil
+}
+
+func (au *AssociationUsecase) GetAllSentencesWithLink(loginUserId uint64, filter model.ListFilter, limit, offset uint64) ([]model.SentenceWithLink, error) {
+	sentences, err := au.su.GetAllSentences(loginUserId, filter, limit, offset)
+	if err != nil {
+		return []model.SentenceWithLink{}, err
+	}
+
+	return au.toSentencesWithLink(sentences)
 }
 
-func (au *AssociationUsecase) GetAllSentencesWithLink(loginUserId, limit, offset uint64) ([]model.SentenceWithLink, error) {
-	sentences, err := au.su.GetAllSentences(loginUserId, limit, offset)
+func (au *AssociationUsecase) toSentencesWithLink(sentences []model.Sentence) ([]model.SentenceWithLink, error) {
+	// 紐づけの作成時に記録したWordの出現箇所から、各Sentence中のリンクとなる箇所を求める
+	// Sentenceの再度の探索は行わず、出現箇所の取得はSentenceの件数によらず1回のクエリで行う
+	var sentenceIds []uint64
+	for _, sentence := range sentences {
+		sentenceIds = append(sentenceIds, sentence.Id)
+	}
+
+	occurrences, err := au.swr.GetOccurrencesBySentenceIds(sentenceIds)
 	if err != nil {
 		return []model.SentenceWithLink{}, err
 	}
 
+	occurrencesBySentenceId := map[uint64][]model.SentenceWordOccurrence{}
+	for _, occurrence := range occurrences {
+		occurrencesBySentenceId[occurrence.SentenceId] = append(occurrencesBySentenceId[occurrence.SentenceId], occurrence)
+	}
+
+	// 訳文も、Sentenceの件数によらず1回のクエリで取得する
+	translations, err := au.sr.GetTranslationsBySentenceIds(sentenceIds)
+	if err != nil {
+		return []model.SentenceWithLink{}, err
+	}
+
+	translationsBySentenceId := map[uint64][]model.SentenceTranslation{}
+	for _, translation := range translations {
+		translationsBySentenceId[translation.SentenceId] = append(translationsBySentenceId[translation.SentenceId], translation)
+	}
+
 	sentenceWithLinks := []model.SentenceWithLink{}
 	for _, sentence := range sentences {
-		sentenceWithLink, err := au.toSentenceWithLink(loginUserId, sentence)
-		if err != nil {
-			return []model.SentenceWithLink{}, err
-		}
+		annotations, discardedAnnotations := toLinkAnnotations(sentence.Sentence, occurrencesBySentenceId[sentence.Id])
 
+		sentenceWithLink := model.SentenceWithLink{
+			Id:                   sentence.Id,
+			Sentence:             sentence.Sentence,
+			Translations:         translationsBySentenceId[sentence.Id],
+			Source:               sentence.Source,
+			Notes:                sentence.Notes,
+			Annotations:          annotations,
+			DiscardedAnnotations: discardedAnnotations,
+			UserId:               sentence.UserId,
+			CreatedAt:            sentence.CreatedAt,
+			UpdatedAt:            sentence.UpdatedAt,
+		}
 		sentenceWithLinks = append(sentenceWithLinks, sentenceWithLink)
 	}
 
 	return sentenceWithLinks, nil
 }
 
-func (au *AssociationUsecase) toSentenceWithLink(loginUserId uint64, sentence model.Sentence) (model.SentenceWithLink, error) {
-	// sentenceに紐づくWordを全件取得し、sentence中におけるそのWordの出現箇所をリンクに変換
-	sentenceText := sentence.Sentence
+func toLinkAnnotations(sentence string, occurrences []model.SentenceWordOccurrence) ([]model.LinkAnnotation, []model.DiscardedLinkAnnotation) {
+	// occurrencesを、リンクとして選ばれた箇所と選ばれなかった箇所に分ける
+	// occurrencesは出現箇所の前から順に並んでいることを前提とする
+	runes := []rune(sentence)
 
-	words, err := au.swr.GetUserAssociatedWordsBySentenceId(sentence.Id)
-	if err != nil {
-		return model.SentenceWithLink{}, err
+	annotations := []model.LinkAnnotation{}
+	discardedAnnotations := []model.DiscardedLinkAnnotation{}
+	for _, occurrence := range occurrences {
+		// 範囲外の出現箇所は無視する
+		if occurrence.Start < 0 || occurrence.End > len(runes) || occurrence.Start >= occurrence.End {
+			continue
+		}
+		matchedText := string(runes[occurrence.Start:occurrence.End])
+
+		if occurrence.DiscardReason != "" {
+			discardedAnnotations = append(discardedAnnotations, model.DiscardedLinkAnnotation{
+				Start:       occurrence.Start,
+				End:         occurrence.End,
+				WordId:      occurrence.WordId,
+				MatchedText: matchedText,
+				Reason:      occurrence.DiscardReason,
+			})
+			continue
+		}
+
+		annotations = append(annotations, model.LinkAnnotation{
+			Start:       occurrence.Start,
+			End:         occurrence.End,
+			WordId:      occurrence.WordId,
+			NotationId:  occurrence.NotationId,
+			MatchedText: matchedText,
+		})
 	}
 
-	for _, word := range words {
-		// sentenceText中に含まれるword.Wordをaタグに置換
-		sentenceText = strings.Replace(
-			sentenceText,
-			word.Word,
-			createWordLink(word.Id, word.Word),
-			-1,
-		)
+	return annotations, discardedAnnotations
+}
 
-		// sentenceText中に含まれるnotation.Notationをaタグに置換
-		notations, err := au.nr.GetAllNotations(word.Id)
-		if err != nil {
-			return model.SentenceWithLink{}, err
-		}
+func RenderSentenceWithLink(sentence string, annotations []model.LinkAnnotation) string {
+	// annotationsの範囲をWordに遷移する<a>に変換したHTMLを作成
+	// sentenceはユーザーの入力のため、リンク以外の部分も含めて全てエスケープする
+	runes := []rune(sentence)
 
-		for _, notation := range notations {
-			sentenceText = strings.Replace(
-				sentenceText,
-				notation.Notation,
-				createWordLink(word.Id, notation.Notation),
-				-1,
-			)
+	var b strings.Builder
+	pos := 0
+	for _, annotation := range annotations {
+		// 重なり合う、または範囲外のannotationは無視する
+		if annotation.Start < pos || annotation.End > len(runes) || annotation.Start >= annotation.End {
+			continue
 		}
-	}
 
-	// 置換後のsentenceTextからSentenceWithLinkを作成
-	sentenceWithLink := model.SentenceWithLink{
-		Id:               sentence.Id,
-		Sentence:         sentence.Sentence,
-		SentenceWithLink: sentenceText,
-		UserId:           sentence.UserId,
-		CreatedAt:        sentence.CreatedAt,
-		UpdatedAt:        sentence.UpdatedAt,
+		b.WriteString(html.EscapeString(string(runes[pos:annotation.Start])))
+		b.WriteString(createWordLink(annotation.WordId, string(runes[annotation.Start:annotation.End])))
+		pos = annotation.End
 	}
+	b.WriteString(html.EscapeString(string(runes[pos:])))
 
-	return sentenceWithLink, nil
+	return b.String()
 }
 
 func createWordLink(wordId uint64, notation string) string {
@@ -149,6 +202,229 @@ func createWordLink(wordId uint64, notation string) string {
 	return fmt.Sprintf(
 		"<a href=\"/words/%d\">%s</a>",
 		wordId,
-		notation,
+		html.EscapeString(notation),
 	)
-}
\ No newline at end of file
+}
+
+// 複数のWordの、WordまたはNotationの文中での出現をまとめて探索する
+// 重なり合う出現はLinkResolverで解決し、リンクの作成とsentences_wordsへの紐づけで同じ結果を使う
+type wordFinder struct {
+	words []model.Word
+	// IPreparedMatcherに渡したtermごとの、wordsにおけるインデックス
+	wordIndexes []int
+	// IPreparedMatcherに渡したtermごとの、NotationのId
+	// Word自体の場合は0
+	notationIds []uint64
+	// IPreparedMatcherに渡したtermごとの、Notationの作成元
+	// Word自体の場合は空文字列
+	notationKinds []string
+	pm            IPreparedMatcher
+	lr            *LinkResolver
+	// scope_associationが有効なデッキに含まれるSentenceごとの、紐づけてよいWordのId
+	// 含まれないSentenceは、すべてのWordと紐づける
+	scopedWordIdsBySentenceId map[uint64]map[uint64]bool
+}
+
+func newWordFinder(m IMatcher, lr *LinkResolver, words []model.Word, notationsByWordId map[uint64][]model.Notation) *wordFinder {
+	// wordsの全WordとNotationを1つのIPreparedMatcherにまとめる
+	var terms []string
+	var wordIndexes []int
+	var notationIds []uint64
+	var notationKinds []string
+	for wordIndex, word := range words {
+		terms = append(terms, word.Word)
+		wordIndexes = append(wordIndexes, wordIndex)
+		notationIds = append(notationIds, 0)
+		notationKinds = append(notationKinds, "")
+
+		for _, notation := range notationsByWordId[word.Id] {
+			terms = append(terms, notation.Notation)
+			wordIndexes = append(wordIndexes, wordIndex)
+			notationIds = append(notationIds, notation.Id)
+			notationKinds = append(notationKinds, notation.Kind)
+		}
+	}
+
+	return &wordFinder{words, wordIndexes, notationIds, notationKinds, m.Prepare(terms), lr, nil}
+}
+
+func newUserWordFinder(
+	m IMatcher,
+	lr *LinkResolver,
+	wr repository.IWordRepository,
+	nr repository.INotationRepository,
+	dr repository.IDeckRepository,
+	userId uint64,
+) (*wordFinder, error) {
+	// userIdの全WordとNotationをまとめて探索するwordFinderを作成
+	// WordとNotationの取得は、Wordの件数によらずそれぞれ1回のクエリで行う
+	userWords, err := wr.GetAllWords(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	notations, err := nr.GetAllNotationsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	notationsByWordId := map[uint64][]model.Notation{}
+	for _, notation := range notations {
+		notationsByWordId[notation.WordId] = append(notationsByWordId[notation.WordId], notation)
+	}
+
+	wf := newWordFinder(m, lr, userWords, notationsByWordId)
+
+	// デッキの件数によらず1回のクエリで、紐づけの範囲を限定するデッキを取得
+	scopes, err := dr.GetAssociationScopes(userId)
+	if err != nil {
+		return nil, err
+	}
+	wf.scopedWordIdsBySentenceId = getScopedWordIdsBySentenceId(scopes)
+
+	return wf, nil
+}
+
+func getScopedWordIdsBySentenceId(scopes []model.AssociationScope) map[uint64]map[uint64]bool {
+	// Sentenceが複数のデッキに含まれる場合は、いずれかのデッキのWordと紐づけてよい
+	scopedWordIdsBySentenceId := map[uint64]map[uint64]bool{}
+	for _, scope := range scopes {
+		for _, sentenceId := range scope.SentenceIds {
+			wordIds, ok := scopedWordIdsBySentenceId[sentenceId]
+			if !ok {
+				wordIds = map[uint64]bool{}
+				scopedWordIdsBySentenceId[sentenceId] = wordIds
+			}
+			for _, wordId := range scope.WordIds {
+				wordIds[wordId] = true
+			}
+		}
+	}
+
+	return scopedWordIdsBySentenceId
+}
+
+func (wf *wordFinder) resolve(sentence string, excludedWordIds, allowedWordIds map[uint64]bool) LinkResolution {
+	// sentence中のWordまたはNotationの出現箇所から、リンクとするものを決める
+	// excludedWordIdsのWordの出現箇所は、リンクの候補としない
+	// allowedWordIdsがnilでない場合、allowedWordIdsに無いWordの出現箇所もリンクの候補としない
+	var candidates []LinkCandidate
+	for _, match := range wf.pm.FindAll(sentence) {
+		word := wf.words[wf.wordIndexes[match.TermIndex]]
+		if excludedWordIds[word.Id] {
+			continue
+		}
+		if allowedWordIds != nil && !allowedWordIds[word.Id] {
+			continue
+		}
+
+		candidates = append(candidates, LinkCandidate{
+			Match:        match,
+			WordId:       word.Id,
+			NotationKind: wf.notationKinds[match.TermIndex],
+			CreatedAt:    word.CreatedAt,
+		})
+	}
+
+	return wf.lr.Resolve(candidates)
+}
+
+// 1つのSentenceに対する、sentences_wordsに記録する紐づけ
+type sentenceAssociation struct {
+	resolution LinkResolution
+	// Sentenceと紐づけるWordのId
+	wordIds map[uint64]bool
+	// 紐づけとともに記録する出現箇所
+	occurrences []model.SentenceWordOccurrence
+}
+
+func (wf *wordFinder) associate(sentence model.Sentence, overrides []model.AssociationOverride) sentenceAssociation {
+	// sentenceと紐づけるWordを、ユーザーの手動の指定を優先して決める
+	// excludeが指定されたWordは、出現箇所をリンクの候補とせず、他のWordがリンクとなれるようにする
+	// pinが指定されたWordは、出現しない、またはリンクとならない場合や、デッキの範囲外の場合も紐づける
+	excludedWordIds := map[uint64]bool{}
+	pinnedWordIds := map[uint64]bool{}
+	for _, override := range overrides {
+		switch override.Kind {
+		case model.AssociationOverrideKindExclude:
+			excludedWordIds[override.WordId] = true
+		case model.AssociationOverrideKindPin:
+			pinnedWordIds[override.WordId] = true
+		}
+	}
+
+	resolution := wf.resolve(sentence.Sentence, excludedWordIds, wf.scopedWordIdsBySentenceId[sentence.Id])
+
+	wordIds := map[uint64]bool{}
+	for _, candidate := range resolution.Selected {
+		wordIds[candidate.WordId] = true
+	}
+	for _, word := range wf.words {
+		if pinnedWordIds[word.Id] {
+			wordIds[word.Id] = true
+		}
+	}
+
+	return sentenceAssociation{
+		resolution:  resolution,
+		wordIds:     wordIds,
+		occurrences: wf.findOccurrences(sentence.Id, resolution, wordIds),
+	}
+}
+
+func (wf *wordFinder) findOccurrences(sentenceId uint64, resolution LinkResolution, associatedWordIds map[uint64]bool) []model.SentenceWordOccurrence {
+	// resolutionから、sentences_wordsの紐づけとともに記録する出現箇所を作成
+	// リンクとして選ばれた箇所と、紐づけられたWordが他のWordと重なり合うため選ばれなかった箇所を記録する
+	var occurrences []model.SentenceWordOccurrence
+	for _, candidate := range resolution.Selected {
+		occurrences = append(occurrences, model.SentenceWordOccurrence{
+			SentenceId: sentenceId,
+			WordId:     candidate.WordId,
+			NotationId: wf.notationIds[candidate.TermIndex],
+			Start:      candidate.Start,
+			End:        candidate.End,
+		})
+	}
+
+	for _, discarded := range resolution.Discarded {
+		// 紐づけられないWordの出現箇所は記録しない
+		if !associatedWordIds[discarded.WordId] {
+			continue
+		}
+		// 同じWordのNotation同士の重なり（「食べ」と「食べた」など）は記録しない
+		if discarded.WordId == discarded.Winner.WordId {
+			continue
+		}
+
+		occurrences = append(occurrences, model.SentenceWordOccurrence{
+			SentenceId:    sentenceId,
+			WordId:        discarded.WordId,
+			NotationId:    wf.notationIds[discarded.TermIndex],
+			Start:         discarded.Start,
+			End:           discarded.End,
+			DiscardReason: discarded.Reason,
+		})
+	}
+
+	return occurrences
+}
+
+func getOverridesBySentenceId(swr repository.ISentencesWordsRepository, sentences []model.Sentence) (map[uint64][]model.AssociationOverride, error) {
+	// sentencesの各Sentenceに対する手動の指定を、1回のクエリでまとめて取得
+	var sentenceIds []uint64
+	for _, sentence := range sentences {
+		sentenceIds = append(sentenceIds, sentence.Id)
+	}
+
+	overrides, err := swr.GetOverridesBySentenceIds(sentenceIds)
+	if err != nil {
+		return nil, err
+	}
+
+	overridesBySentenceId := map[uint64][]model.AssociationOverride{}
+	for _, override := range overrides {
+		overridesBySentenceId[override.SentenceId] = append(overridesBySentenceId[override.SentenceId], override)
+	}
+
+	return overridesBySentenceId, nil
+}
@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Sessionの有効期間
+const SessionLifetime = 7 * 24 * time.Hour
+
+// アクセストークン、リフレッシュトークンの有効期間
+const AccessTokenLifetime = 15 * time.Minute
+const RefreshTokenLifetime = 30 * 24 * time.Hour
+
+// アクセストークンのissクレーム
+const accessTokenIssuer = "vocamana-api"
+
+// パスワードの最小文字数
+const minPasswordLength = 8
+
+var (
+	ErrInvalidSignUp      = NewValidationError("email and password (at least 8 characters) are required", nil)
+	ErrEmailAlreadyUsed   = NewConflictError("email is already used")
+	ErrInvalidCredentials = NewUnauthenticatedError("email or password is incorrect")
+	ErrUnauthenticated    = NewUnauthenticatedError("not logged in")
+	ErrInvalidToken       = NewUnauthenticatedError("token is invalid or expired")
+	ErrUnsupportedGrant   = NewValidationError("grant_type must be password or refresh_token", nil)
+	// 設定の不備のため、内容をクライアントに返さない
+	ErrJwtSecretNotSet = errors.New("jwt secret is not configured")
+)
+
+type AuthUsecase struct {
+	ur        repository.IUserRepository
+	ssr       repository.ISessionRepository
+	rtr       repository.IRefreshTokenRepository
+	jwtSecret []byte
+}
+
+func NewAuthUsecase(
+	ur repository.IUserRepository,
+	ssr repository.ISessionRepository,
+	rtr repository.IRefreshTokenRepository,
+	jwtSecret []byte,
+) *AuthUsecase {
+	return &AuthUsecase{ur, ssr, rtr, jwtSecret}
+}
+
+func (atu *AuthUsecase) SignUp(userSignUp model.UserSignUp) (model.User, error) {
+	email := strings.TrimSpace(userSignUp.Email)
+	if email == "" || len([]rune(userSignUp.Password)) < minPasswordLength {
+		return model.User{}, ErrInvalidSignUp
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userSignUp.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	createdUser, err := atu.ur.InsertUser(model.UserCreation{
+		Email:          email,
+		HashedPassword: string(hashedPassword),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// emailが既に使われている場合
+			return model.User{}, ErrEmailAlreadyUsed
+		}
+
+		return model.User{}, err
+	}
+
+	return createdUser, nil
+}
+
+func (atu *AuthUsecase) LogIn(userLogIn model.UserLogIn) (model.IssuedSession, error) {
+	user, err := atu.authenticate(userLogIn)
+	if err != nil {
+		return model.IssuedSession{}, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return model.IssuedSession{}, err
+	}
+
+	// 期限切れのSessionが溜まり続けないよう、ログインの度に削除する
+	err = atu.ssr.DeleteExpiredSessions()
+	if err != nil {
+		return model.IssuedSession{}, err
+	}
+
+	session, err := atu.ssr.InsertSession(model.SessionCreation{
+		Id:        hashToken(token),
+		UserId:    user.Id,
+		ExpiresAt: time.Now().Add(SessionLifetime),
+	})
+	if err != nil {
+		return model.IssuedSession{}, err
+	}
+
+	issuedSession := model.IssuedSession{
+		Token:     token,
+		UserId:    session.UserId,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	return issuedSession, nil
+}
+
+func (atu *AuthUsecase) LogOut(token string) error {
+	return atu.ssr.DeleteSessionById(hashToken(token))
+}
+
+func (atu *AuthUsecase) GetUserById(userId uint64) (model.User, error) {
+	user, err := atu.ur.GetUserById(userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.User{}, ErrUnauthenticated
+		}
+
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func (atu *AuthUsecase) GetLoginUserIdBySessionToken(token string) (uint64, error) {
+	session, err := atu.ssr.GetValidSessionById(hashToken(token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Sessionが存在しない、または有効期限切れの場合
+			return 0, ErrUnauthenticated
+		}
+
+		return 0, err
+	}
+
+	return session.UserId, nil
+}
+
+func (atu *AuthUsecase) IssueTokens(tokenRequest model.TokenRequest) (model.IssuedTokens, error) {
+	// grant_typeに応じてアクセストークンとリフレッシュトークンを発行する
+	switch tokenRequest.GrantType {
+	case "password":
+		userLogIn := model.UserLogIn{
+			Email:    tokenRequest.Email,
+			Password: tokenRequest.Password,
+		}
+		return atu.issueTokensByPassword(userLogIn)
+	case "refresh_token":
+		return atu.rotateRefreshToken(tokenRequest.RefreshToken)
+	default:
+		return model.IssuedTokens{}, ErrUnsupportedGrant
+	}
+}
+
+func (atu *AuthUsecase) RevokeRefreshToken(refreshToken string) error {
+	// リフレッシュトークンを、同じログインから発行されたものも含めて失効させる
+	storedToken, err := atu.rtr.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	return atu.rtr.RevokeRefreshTokenFamily(storedToken.FamilyId)
+}
+
+func (atu *AuthUsecase) GetLoginUserIdByAccessToken(accessToken string) (uint64, error) {
+	if len(atu.jwtSecret) == 0 {
+		return 0, ErrJwtSecretNotSet
+	}
+
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(
+		accessToken,
+		&claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return atu.jwtSecret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(accessTokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	loginUserId, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	return loginUserId, nil
+}
+
+func (atu *AuthUsecase) issueTokensByPassword(userLogIn model.UserLogIn) (model.IssuedTokens, error) {
+	user, err := atu.authenticate(userLogIn)
+	if err != nil {
+		return model.IssuedTokens{}, err
+	}
+
+	// ログインごとに新しいfamilyを作成する
+	familyId, err := generateToken()
+	if err != nil {
+		return model.IssuedTokens{}, err
+	}
+
+	issuedTokens, _, err := atu.issueTokens(user.Id, familyId)
+	if err != nil {
+		return model.IssuedTokens{}, err
+	}
+
+	return issuedTokens, nil
+}
+
+func (atu *AuthUsecase) rotateRefreshToken(refreshToken string) (model.IssuedTokens, error) {
+	// 使用されたリフレッシュトークンを失効させ、新しいトークンを発行する
+
+	storedToken, err := atu.rtr.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.IssuedTokens{}, ErrInvalidToken
+		}
+
+		return model.IssuedTokens{}, err
+	}
+
+	if storedToken.RevokedAt != nil {
+		// 失効済みのトークンが再利用された場合、漏洩したとみなし
+		// 同じfamilyのトークンを全て失効させる
+		err = atu.rtr.RevokeRefreshTokenFamily(storedToken.FamilyId)
+		if err != nil {
+			return model.IssuedTokens{}, err
+		}
+
+		return model.IssuedTokens{}, ErrInvalidToken
+	}
+
+	if !storedToken.ExpiresAt.After(time.Now()) {
+		return model.IssuedTokens{}, ErrInvalidToken
+	}
+
+	issuedTokens, createdToken, err := atu.issueTokens(storedToken.UserId, storedToken.FamilyId)
+	if err != nil {
+		return model.IssuedTokens{}, err
+	}
+
+	isRevoked, err := atu.rtr.RevokeRefreshToken(storedToken.Id, &createdToken.Id)
+	if err != nil {
+		return model.IssuedTokens{}, err
+	}
+	if !isRevoked {
+		// 同じトークンで同時にリフレッシュされ、先に失効されていた場合
+		err = atu.rtr.RevokeRefreshTokenFamily(storedToken.FamilyId)
+		if err != nil {
+			return model.IssuedTokens{}, err
+		}
+
+		return model.IssuedTokens{}, ErrInvalidToken
+	}
+
+	return issuedTokens, nil
+}
+
+func (atu *AuthUsecase) issueTokens(userId uint64, familyId string) (model.IssuedTokens, model.RefreshToken, error) {
+	if len(atu.jwtSecret) == 0 {
+		return model.IssuedTokens{}, model.RefreshToken{}, ErrJwtSecretNotSet
+	}
+
+	now := time.Now()
+	accessTokenExpiresAt := now.Add(AccessTokenLifetime)
+
+	claims := jwt.RegisteredClaims{
+		Issuer:    accessTokenIssuer,
+		Subject:   strconv.FormatUint(userId, 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(accessTokenExpiresAt),
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(atu.jwtSecret)
+	if err != nil {
+		return model.IssuedTokens{}, model.RefreshToken{}, err
+	}
+
+	refreshToken, err := generateToken()
+	if err != nil {
+		return model.IssuedTokens{}, model.RefreshToken{}, err
+	}
+
+	createdToken, err := atu.rtr.InsertRefreshToken(model.RefreshTokenCreation{
+		UserId:    userId,
+		TokenHash: hashToken(refreshToken),
+		FamilyId:  familyId,
+		ExpiresAt: now.Add(RefreshTokenLifetime),
+	})
+	if err != nil {
+		return model.IssuedTokens{}, model.RefreshToken{}, err
+	}
+
+	issuedTokens := model.IssuedTokens{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessTokenExpiresAt,
+		RefreshToken:         refreshToken,
+		UserId:               userId,
+	}
+
+	return issuedTokens, createdToken, nil
+}
+
+func (atu *AuthUsecase) authenticate(userLogIn model.UserLogIn) (model.User, error) {
+	// emailとpasswordが一致するUserを返す
+
+	user, err := atu.ur.GetUserByEmail(strings.TrimSpace(userLogIn.Email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.User{}, ErrInvalidCredentials
+		}
+
+		return model.User{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(userLogIn.Password))
+	if err != nil {
+		return model.User{}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+func generateToken() (string, error) {
+	// 推測不可能なランダムなトークンを生成
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	// DBにはトークンそのものではなくハッシュ値を保存する
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
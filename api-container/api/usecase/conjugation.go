@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"api/model"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 活用の種類
+type WordClass string
+
+const (
+	WordClassUnknown     WordClass = ""
+	WordClassGodanVerb   WordClass = "godan_verb"   // 五段動詞 (買う, 書く, 泳ぐ, 死ぬ, 遊ぶ, 走る)
+	WordClassIchidanVerb WordClass = "ichidan_verb" // 一段動詞 (食べる, 見る)
+	WordClassSuruVerb    WordClass = "suru_verb"    // サ行変格活用 (する, 勉強する)
+	WordClassKuruVerb    WordClass = "kuru_verb"    // カ行変格活用 (来る)
+	WordClassIAdjective  WordClass = "i_adjective"  // 形容詞 (赤い)
+	WordClassNaAdjective WordClass = "na_adjective" // 形容動詞 (静かだ, きれい)
+)
+
+// 活用形
+type ConjugationForm string
+
+const (
+	ConjugationFormNegative       ConjugationForm = "negative"        // 買わない
+	ConjugationFormNegativePast   ConjugationForm = "negative_past"   // 買わなかった
+	ConjugationFormPast           ConjugationForm = "past"            // 買った
+	ConjugationFormTe             ConjugationForm = "te"              // 買って
+	ConjugationFormPolite         ConjugationForm = "polite"          // 買います
+	ConjugationFormPolitePast     ConjugationForm = "polite_past"     // 買いました
+	ConjugationFormPoliteNegative ConjugationForm = "polite_negative" // 買いません
+	ConjugationFormDesiderative   ConjugationForm = "desiderative"    // 買いたい
+	ConjugationFormPotential      ConjugationForm = "potential"       // 買える
+	ConjugationFormVolitional     ConjugationForm = "volitional"      // 買おう
+	ConjugationFormConditional    ConjugationForm = "conditional"     // 買えば
+	ConjugationFormPassive        ConjugationForm = "passive"         // 買われる
+	ConjugationFormCausative      ConjugationForm = "causative"       // 買わせる
+	ConjugationFormAdverbial      ConjugationForm = "adverbial"       // 赤く, 静かに
+	ConjugationFormAttributive    ConjugationForm = "attributive"     // 静かな
+	ConjugationFormStem           ConjugationForm = "stem"            // 静か
+)
+
+// 活用した語
+type Conjugation struct {
+	Form ConjugationForm
+	Text string
+}
+
+// 五段動詞の語尾ごとの、ア段・イ段・エ段・オ段の語尾と、て形・た形の語尾
+type godanEnding struct {
+	a, i, e, o string
+	te, ta     string
+}
+
+var godanEndings = map[string]godanEnding{
+	"う": {"わ", "い", "え", "お", "って", "った"},
+	"く": {"か", "き", "け", "こ", "いて", "いた"},
+	"ぐ": {"が", "ぎ", "げ", "ご", "いで", "いだ"},
+	"す": {"さ", "し", "せ", "そ", "して", "した"},
+	"つ": {"た", "ち", "て", "と", "って", "った"},
+	"ぬ": {"な", "に", "ね", "の", "んで", "んだ"},
+	"ぶ": {"ば", "び", "べ", "ぼ", "んで", "んだ"},
+	"む": {"ま", "み", "め", "も", "んで", "んだ"},
+	"る": {"ら", "り", "れ", "ろ", "って", "った"},
+}
+
+// 語尾が「iる」「eる」だが五段活用する動詞
+var godanVerbExceptions = []string{
+	"はいる", "しゃべる", "すべる", "へる", "しる", "ける", "あせる", "まじる", "混じる",
+}
+
+// 「漢字+る」だが一段活用する動詞
+var ichidanVerbExceptions = []string{
+	"見る", "着る", "寝る", "出る", "居る", "似る", "煮る", "干る", "得る", "経る", "射る", "診る",
+}
+
+// 語尾が「い」だが形容動詞である語
+var naAdjectiveExceptions = []string{
+	"きれい", "綺麗", "嫌い", "きらい", "有名", "同じ",
+}
+
+// 仮名のみで書かれることが多い形容詞
+// これ以外の仮名のみの語は、語尾が「い」でも形容詞と推定しない（「はい」「あい」など）
+var kanaIAdjectives = []string{
+	"いい", "よい", "すごい", "おいしい", "かわいい", "うるさい",
+}
+
+// 送り仮名とみなす、語尾の平仮名の最大の文字数（終わる、動かす）
+const maxOkuriganaLength = 2
+
+func GuessWordClass(word string) WordClass {
+	// 語尾からwordの活用の種類を推定する
+	// 動詞と推定するのは、漢字の語幹に送り仮名が続く語か、仮名のみでも動詞に特有の語尾を持つ語に限る
+	// （「ありがとう」などの、たまたまウ段で終わる語を五段動詞としない）
+	// 形容詞も、漢字の語幹を持つ語か、仮名のみで書かれることが多い語に限る
+	// （「まだ」「はい」「お願い」などを形容詞としない）
+	// 推定できない場合はWordClassUnknownを返す
+	switch {
+	case strings.HasSuffix(word, "する"):
+		return WordClassSuruVerb
+	case strings.HasSuffix(word, "来る") || word == "くる":
+		return WordClassKuruVerb
+	case contains(naAdjectiveExceptions, word):
+		return WordClassNaAdjective
+	case strings.HasSuffix(word, "だ"):
+		if isKanjiNaAdjectiveStem(strings.TrimSuffix(word, "だ")) {
+			return WordClassNaAdjective
+		}
+		return WordClassUnknown
+	case strings.HasSuffix(word, "い"):
+		if contains(kanaIAdjectives, word) || hasKanjiStem(word) {
+			return WordClassIAdjective
+		}
+		return WordClassUnknown
+	case strings.HasSuffix(word, "る"):
+		return guessRuVerbClass(word)
+	}
+
+	if _, ok := godanEndings[lastRune(word)]; ok && hasKanjiStem(word) {
+		return WordClassGodanVerb
+	}
+
+	return WordClassUnknown
+}
+
+// 活用の種類を持つ品詞
+var wordClassesByPartOfSpeech = map[string]WordClass{
+	model.PartOfSpeechGodanVerb:   WordClassGodanVerb,
+	model.PartOfSpeechIchidanVerb: WordClassIchidanVerb,
+	model.PartOfSpeechSuruVerb:    WordClassSuruVerb,
+	model.PartOfSpeechKuruVerb:    WordClassKuruVerb,
+	model.PartOfSpeechIAdjective:  WordClassIAdjective,
+	model.PartOfSpeechNaAdjective: WordClassNaAdjective,
+}
+
+// 活用の種類ごとの、活用させるために必要な語尾
+var wordClassEndings = map[WordClass]string{
+	WordClassIchidanVerb: "る",
+	WordClassSuruVerb:    "する",
+	WordClassKuruVerb:    "る",
+	WordClassIAdjective:  "い",
+}
+
+func WordClassOf(word, partOfSpeech string) WordClass {
+	// 品詞からwordの活用の種類を決める
+	// 品詞が未入力の場合のみ、語尾から推定する
+	// 名詞、副詞などの活用しない品詞や、語尾が品詞に合わない場合はWordClassUnknownを返す
+	if partOfSpeech == "" {
+		return GuessWordClass(word)
+	}
+
+	wordClass, ok := wordClassesByPartOfSpeech[partOfSpeech]
+	if !ok {
+		return WordClassUnknown
+	}
+
+	if ending, ok := wordClassEndings[wordClass]; ok && !strings.HasSuffix(word, ending) {
+		return WordClassUnknown
+	}
+	if wordClass == WordClassGodanVerb {
+		if _, ok := godanEndings[lastRune(word)]; !ok {
+			return WordClassUnknown
+		}
+	}
+
+	return wordClass
+}
+
+func guessRuVerbClass(word string) WordClass {
+	// 「る」で終わる動詞が一段動詞か五段動詞かを推定する
+	// 「る」の直前がイ段・エ段の仮名であれば一段動詞とする
+	if contains(godanVerbExceptions, word) {
+		return WordClassGodanVerb
+	}
+	if contains(ichidanVerbExceptions, word) {
+		return WordClassIchidanVerb
+	}
+
+	stem := strings.TrimSuffix(word, "る")
+	if stem == "" {
+		return WordClassUnknown
+	}
+	if strings.Contains("いきぎしじちぢにひびぴみりえけげせぜてでねへべぺめれ", lastRune(stem)) {
+		return WordClassIchidanVerb
+	}
+
+	// 仮名のみの語は、一段動詞の語尾でなければ動詞と推定しない
+	if !hasKanjiStem(word) {
+		return WordClassUnknown
+	}
+
+	return WordClassGodanVerb
+}
+
+func isKanjiNaAdjectiveStem(stem string) bool {
+	// 形容動詞の語幹が、漢字のみ（有名）か、漢字の語幹に送り仮名が続く（静か）かを判定する
+	if stem == "" {
+		return false
+	}
+	for _, r := range stem {
+		if !unicode.Is(unicode.Han, r) {
+			return hasKanjiStem(stem)
+		}
+	}
+
+	return true
+}
+
+func hasKanjiStem(word string) bool {
+	// wordが漢字で始まり、最後の漢字の後に1～maxOkuriganaLength文字の平仮名が続くかを判定する
+	runes := []rune(word)
+	if len(runes) == 0 || !unicode.Is(unicode.Han, runes[0]) {
+		return false
+	}
+
+	lastKanjiIndex := 0
+	for i, r := range runes {
+		if unicode.Is(unicode.Han, r) {
+			lastKanjiIndex = i
+		}
+	}
+
+	okurigana := runes[lastKanjiIndex+1:]
+	if len(okurigana) == 0 || len(okurigana) > maxOkuriganaLength {
+		return false
+	}
+	for _, r := range okurigana {
+		if !unicode.Is(unicode.Hiragana, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func Conjugate(word string, wordClass WordClass) []Conjugation {
+	// wordClassとして活用させたwordの活用形を全て返す
+	// word自身は含まない
+	var conjugations []Conjugation
+	switch wordClass {
+	case WordClassGodanVerb:
+		conjugations = conjugateGodanVerb(word)
+	case WordClassIchidanVerb:
+		conjugations = conjugateIchidanVerb(word)
+	case WordClassSuruVerb:
+		conjugations = conjugateSuruVerb(word)
+	case WordClassKuruVerb:
+		conjugations = conjugateKuruVerb(word)
+	case WordClassIAdjective:
+		conjugations = conjugateIAdjective(word)
+	case WordClassNaAdjective:
+		conjugations = conjugateNaAdjective(word)
+	}
+
+	// 空文字列、word自身、重複する活用形を除く
+	var uniqueConjugations []Conjugation
+	seen := map[string]bool{word: true, "": true}
+	for _, conjugation := range conjugations {
+		if seen[conjugation.Text] {
+			continue
+		}
+		seen[conjugation.Text] = true
+		uniqueConjugations = append(uniqueConjugations, conjugation)
+	}
+
+	return uniqueConjugations
+}
+
+func conjugateGodanVerb(word string) []Conjugation {
+	ending := lastRune(word)
+	e, ok := godanEndings[ending]
+	if !ok {
+		return nil
+	}
+	stem := strings.TrimSuffix(word, ending)
+
+	// 「行く」のて形・た形は「行いて」ではなく「行って」
+	if word == "行く" || word == "いく" {
+		e.te, e.ta = "って", "った"
+	}
+
+	return []Conjugation{
+		{ConjugationFormNegative, stem + e.a + "ない"},
+		{ConjugationFormNegativePast, stem + e.a + "なかった"},
+		{ConjugationFormPast, stem + e.ta},
+		{ConjugationFormTe, stem + e.te},
+		{ConjugationFormPolite, stem + e.i + "ます"},
+		{ConjugationFormPolitePast, stem + e.i + "ました"},
+		{ConjugationFormPoliteNegative, stem + e.i + "ません"},
+		{ConjugationFormDesiderative, stem + e.i + "たい"},
+		{ConjugationFormPotential, stem + e.e + "る"},
+		{ConjugationFormVolitional, stem + e.o + "う"},
+		{ConjugationFormConditional, stem + e.e + "ば"},
+		{ConjugationFormPassive, stem + e.a + "れる"},
+		{ConjugationFormCausative, stem + e.a + "せる"},
+	}
+}
+
+func conjugateIchidanVerb(word string) []Conjugation {
+	stem := strings.TrimSuffix(word, "る")
+
+	return []Conjugation{
+		{ConjugationFormNegative, stem + "ない"},
+		{ConjugationFormNegativePast, stem + "なかった"},
+		{ConjugationFormPast, stem + "た"},
+		{ConjugationFormTe, stem + "て"},
+		{ConjugationFormPolite, stem + "ます"},
+		{ConjugationFormPolitePast, stem + "ました"},
+		{ConjugationFormPoliteNegative, stem + "ません"},
+		{ConjugationFormDesiderative, stem + "たい"},
+		{ConjugationFormPotential, stem + "られる"},
+		// いわゆる「ら抜き言葉」
+		{ConjugationFormPotential, stem + "れる"},
+		{ConjugationFormVolitional, stem + "よう"},
+		{ConjugationFormConditional, stem + "れば"},
+		{ConjugationFormPassive, stem + "られる"},
+		{ConjugationFormCausative, stem + "させる"},
+	}
+}
+
+func conjugateSuruVerb(word string) []Conjugation {
+	stem := strings.TrimSuffix(word, "する")
+
+	return []Conjugation{
+		{ConjugationFormNegative, stem + "しない"},
+		{ConjugationFormNegativePast, stem + "しなかった"},
+		{ConjugationFormPast, stem + "した"},
+		{ConjugationFormTe, stem + "して"},
+		{ConjugationFormPolite, stem + "します"},
+		{ConjugationFormPolitePast, stem + "しました"},
+		{ConjugationFormPoliteNegative, stem + "しません"},
+		{ConjugationFormDesiderative, stem + "したい"},
+		{ConjugationFormPotential, stem + "できる"},
+		{ConjugationFormVolitional, stem + "しよう"},
+		{ConjugationFormConditional, stem + "すれば"},
+		{ConjugationFormPassive, stem + "される"},
+		{ConjugationFormCausative, stem + "させる"},
+	}
+}
+
+func conjugateKuruVerb(word string) []Conjugation {
+	// 「来る」は漢字の場合は表記が変わらないため、ひらがなの場合のみ語幹が変化する
+	if strings.HasSuffix(word, "来る") {
+		stem := strings.TrimSuffix(word, "る")
+
+		return []Conjugation{
+			{ConjugationFormNegative, stem + "ない"},
+			{ConjugationFormNegativePast, stem + "なかった"},
+			{ConjugationFormPast, stem + "た"},
+			{ConjugationFormTe, stem + "て"},
+			{ConjugationFormPolite, stem + "ます"},
+			{ConjugationFormPolitePast, stem + "ました"},
+			{ConjugationFormPoliteNegative, stem + "ません"},
+			{ConjugationFormDesiderative, stem + "たい"},
+			{ConjugationFormPotential, stem + "られる"},
+			{ConjugationFormPotential, stem + "れる"},
+			{ConjugationFormVolitional, stem + "よう"},
+			{ConjugationFormConditional, stem + "れば"},
+			{ConjugationFormPassive, stem + "られる"},
+			{ConjugationFormCausative, stem + "させる"},
+		}
+	}
+
+	prefix := strings.TrimSuffix(word, "くる")
+
+	return []Conjugation{
+		{ConjugationFormNegative, prefix + "こない"},
+		{ConjugationFormNegativePast, prefix + "こなかった"},
+		{ConjugationFormPast, prefix + "きた"},
+		{ConjugationFormTe, prefix + "きて"},
+		{ConjugationFormPolite, prefix + "きます"},
+		{ConjugationFormPolitePast, prefix + "きました"},
+		{ConjugationFormPoliteNegative, prefix + "きません"},
+		{ConjugationFormDesiderative, prefix + "きたい"},
+		{ConjugationFormPotential, prefix + "こられる"},
+		{ConjugationFormPotential, prefix + "これる"},
+		{ConjugationFormVolitional, prefix + "こよう"},
+		{ConjugationFormConditional, prefix + "くれば"},
+		{ConjugationFormPassive, prefix + "こられる"},
+		{ConjugationFormCausative, prefix + "こさせる"},
+	}
+}
+
+func conjugateIAdjective(word string) []Conjugation {
+	// 「いい」は「よい」として活用する
+	if word == "いい" {
+		word = "よい"
+	}
+	stem := strings.TrimSuffix(word, "い")
+
+	return []Conjugation{
+		{ConjugationFormNegative, stem + "くない"},
+		{ConjugationFormNegativePast, stem + "くなかった"},
+		{ConjugationFormPast, stem + "かった"},
+		{ConjugationFormTe, stem + "くて"},
+		{ConjugationFormAdverbial, stem + "く"},
+		{ConjugationFormConditional, stem + "ければ"},
+		{ConjugationFormVolitional, stem + "かろう"},
+	}
+}
+
+func conjugateNaAdjective(word string) []Conjugation {
+	stem := strings.TrimSuffix(word, "だ")
+
+	conjugations := []Conjugation{}
+	// 1文字の語幹や仮名のみの語幹は、多くのSentenceに一致してしまうため活用形としない
+	if utf8.RuneCountInString(stem) >= 2 && !IsKana(stem) {
+		conjugations = append(conjugations, Conjugation{ConjugationFormStem, stem})
+	}
+
+	return append(conjugations, []Conjugation{
+		{ConjugationFormAttributive, stem + "な"},
+		{ConjugationFormAdverbial, stem + "に"},
+		{ConjugationFormTe, stem + "で"},
+		{ConjugationFormPast, stem + "だった"},
+		{ConjugationFormPolite, stem + "です"},
+		{ConjugationFormPolitePast, stem + "でした"},
+		{ConjugationFormNegative, stem + "じゃない"},
+		{ConjugationFormNegative, stem + "ではない"},
+		{ConjugationFormNegativePast, stem + "じゃなかった"},
+		{ConjugationFormConditional, stem + "なら"},
+	}...)
+}
+
+func lastRune(s string) string {
+	r, size := utf8.DecodeLastRuneInString(s)
+	if r == utf8.RuneError {
+		return ""
+	}
+
+	return s[len(s)-size:]
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"database/sql"
+	"sort"
+)
+
+type DeckUsecase struct {
+	dr  repository.IDeckRepository
+	wr  repository.IWordRepository
+	sr  repository.ISentenceRepository
+	uow repository.IUnitOfWork
+	// デッキの変更により紐づけの範囲が変わった場合の、紐づけの再構築に使用
+	wu *WordUsecase
+	su *SentenceUsecase
+}
+
+func NewDeckUsecase(
+	dr repository.IDeckRepository,
+	wr repository.IWordRepository,
+	sr repository.ISentenceRepository,
+	uow repository.IUnitOfWork,
+	wu *WordUsecase,
+	su *SentenceUsecase,
+) *DeckUsecase {
+	return &DeckUsecase{dr, wr, sr, uow, wu, su}
+}
+
+func (du *DeckUsecase) withRepositories(repos repository.Repositories) *DeckUsecase {
+	// トランザクション内のreposを使うDeckUsecaseを作成
+	return NewDeckUsecase(
+		repos.Deck,
+		repos.Word,
+		repos.Sentence,
+		repository.NewTransactionalUnitOfWork(repos),
+		du.wu.withRepositories(repos),
+		du.su.withRepositories(repos),
+	)
+}
+
+func (du *DeckUsecase) GetAllDecks(loginUserId uint64) ([]model.Deck, error) {
+	return du.dr.GetAllDecks(loginUserId)
+}
+
+func (du *DeckUsecase) GetDeckById(loginUserId, deckId uint64) (model.Deck, error) {
+	deck, err := du.dr.GetDeckById(loginUserId, deckId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// マッチするレコードが無い場合
+			return model.Deck{}, ErrDeckNotFound
+		}
+
+		return model.Deck{}, err
+	}
+
+	return deck, nil
+}
+
+func (du *DeckUsecase) CreateDeck(deckCreation model.DeckCreation) (model.Deck, error) {
+	err := du.checkParentDeck(deckCreation.LoginUserId, deckCreation.ParentId)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	createdDeck, err := du.dr.InsertDeck(deckCreation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 同じ親の下に同じ名前のデッキが既に存在する場合
+			return model.Deck{}, du.newDuplicateDeckError(deckCreation.LoginUserId, deckCreation.ParentId, deckCreation.Name)
+		}
+
+		return model.Deck{}, err
+	}
+
+	// 追加したデッキはWordとSentenceを含まないため、紐づけは変わらない
+	return createdDeck, nil
+}
+
+func (du *DeckUsecase) UpdateDeck(deckUpdate model.DeckUpdate) (model.Deck, error) {
+	// デッキの更新と、紐づけの範囲が変わるSentenceのsentences_wordsの再構築をトランザクション内で実行
+	var updatedDeck model.Deck
+	err := du.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		updatedDeck, err = du.withRepositories(repos).updateDeck(deckUpdate)
+		return err
+	})
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	return updatedDeck, nil
+}
+
+func (du *DeckUsecase) updateDeck(deckUpdate model.DeckUpdate) (model.Deck, error) {
+	loginUserId := deckUpdate.LoginUserId
+
+	previousDeck, err := du.GetDeckById(loginUserId, deckUpdate.Id)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	err = du.checkParentDeck(loginUserId, deckUpdate.ParentId)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	if deckUpdate.ParentId != nil {
+		// 自身または子孫のデッキを親にすると、parent_idが循環する
+		treeIds, err := du.dr.GetDeckTreeIds(deckUpdate.Id)
+		if err != nil {
+			return model.Deck{}, err
+		}
+		for _, treeId := range treeIds {
+			if treeId == *deckUpdate.ParentId {
+				return model.Deck{}, NewValidationError("parent deck must not be the deck itself or its descendant", nil)
+			}
+		}
+	}
+
+	// 同じ親の下の他のデッキと同じ名前には更新できない
+	duplicateDeck, err := du.dr.GetDeckByName(loginUserId, deckUpdate.ParentId, deckUpdate.Name)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Deck{}, err
+	}
+	if err == nil && duplicateDeck.Id != deckUpdate.Id {
+		return model.Deck{}, NewDuplicateError("deck", model.DuplicateErrorDetails{ExistingId: duplicateDeck.Id})
+	}
+
+	// 名前のみの変更では、紐づけの範囲は変わらない
+	scopeChanged := previousDeck.ScopeAssociation != deckUpdate.ScopeAssociation ||
+		!equalParentIds(previousDeck.ParentId, deckUpdate.ParentId)
+
+	var previousScopes []model.AssociationScope
+	if scopeChanged {
+		previousScopes, err = du.dr.GetAssociationScopes(loginUserId)
+		if err != nil {
+			return model.Deck{}, err
+		}
+	}
+
+	updatedDeck, err := du.dr.UpdateDeck(deckUpdate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// レコードが更新されなかった場合
+			return model.Deck{}, ErrDeckNotFound
+		}
+
+		return model.Deck{}, err
+	}
+
+	if scopeChanged {
+		updatedDeck.AssociationJobId, err = du.reAssociateScopedSentencesLater(loginUserId, previousScopes)
+		if err != nil {
+			return model.Deck{}, err
+		}
+	}
+
+	return updatedDeck, nil
+}
+
+func (du *DeckUsecase) DeleteDeck(loginUserId, deckId uint64) (model.Deck, error) {
+	// デッキの削除と、紐づけの範囲が変わるSentenceのsentences_wordsの再構築をトランザクション内で実行
+	var deletedDeck model.Deck
+	err := du.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		deletedDeck, err = du.withRepositories(repos).deleteDeck(loginUserId, deckId)
+		return err
+	})
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	return deletedDeck, nil
+}
+
+func (du *DeckUsecase) deleteDeck(loginUserId, deckId uint64) (model.Deck, error) {
+	// 子孫のデッキも削除されるため、削除前の紐づけの範囲を取得しておく
+	// デッキに含まれていたWordとSentence自体は削除しない
+	previousScopes, err := du.dr.GetAssociationScopes(loginUserId)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	deletedDeck, err := du.dr.DeleteDeckById(loginUserId, deckId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// レコードが削除されなかった場合
+			return model.Deck{}, ErrDeckNotFound
+		}
+
+		return model.Deck{}, err
+	}
+
+	deletedDeck.AssociationJobId, err = du.reAssociateScopedSentencesLater(loginUserId, previousScopes)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	return deletedDeck, nil
+}
+
+func (du *DeckUsecase) AddWordToDeck(loginUserId, deckId, wordId uint64) (model.DeckItem, error) {
+	return du.changeDeckWord(loginUserId, deckId, wordId, repository.IDeckRepository.AddWordToDeck)
+}
+
+func (du *DeckUsecase) RemoveWordFromDeck(loginUserId, deckId, wordId uint64) (model.DeckItem, error) {
+	return du.changeDeckWord(loginUserId, deckId, wordId, repository.IDeckRepository.RemoveWordFromDeck)
+}
+
+func (du *DeckUsecase) AddSentenceToDeck(loginUserId, deckId, sentenceId uint64) (model.DeckItem, error) {
+	return du.changeDeckSentence(loginUserId, deckId, sentenceId, repository.IDeckRepository.AddSentenceToDeck)
+}
+
+func (du *DeckUsecase) RemoveSentenceFromDeck(loginUserId, deckId, sentenceId uint64) (model.DeckItem, error) {
+	return du.changeDeckSentence(loginUserId, deckId, sentenceId, repository.IDeckRepository.RemoveSentenceFromDeck)
+}
+
+func (du *DeckUsecase) changeDeckWord(loginUserId, deckId, wordId uint64, change func(dr repository.IDeckRepository, deckId, wordId uint64) error) (model.DeckItem, error) {
+	// デッキへのWordの追加または削除と、紐づけの再構築をトランザクション内で実行
+	var deckItem model.DeckItem
+	err := du.uow.Do(func(repos repository.Repositories) error {
+		tx := du.withRepositories(repos)
+
+		err := tx.checkDeckItemOwner(loginUserId, deckId, wordId, 0)
+		if err != nil {
+			return err
+		}
+
+		// changeはトランザクション内のtx.drで実行する
+		err = change(tx.dr, deckId, wordId)
+		if err != nil {
+			return err
+		}
+
+		deckItem = model.DeckItem{DeckId: deckId, WordId: wordId}
+
+		// 紐づけの範囲を限定しないデッキでは、紐づけは変わらない
+		isScoped, err := tx.dr.IsAssociationScoped(deckId)
+		if err != nil {
+			return err
+		}
+		if !isScoped {
+			return nil
+		}
+
+		deckItem.AssociationJobId, err = tx.wu.reAssociateWordLater(loginUserId, wordId)
+		return err
+	})
+	if err != nil {
+		return model.DeckItem{}, err
+	}
+
+	return deckItem, nil
+}
+
+func (du *DeckUsecase) changeDeckSentence(loginUserId, deckId, sentenceId uint64, change func(dr repository.IDeckRepository, deckId, sentenceId uint64) error) (model.DeckItem, error) {
+	// デッキへのSentenceの追加または削除と、紐づけの再構築をトランザクション内で実行
+	var deckItem model.DeckItem
+	err := du.uow.Do(func(repos repository.Repositories) error {
+		tx := du.withRepositories(repos)
+
+		err := tx.checkDeckItemOwner(loginUserId, deckId, 0, sentenceId)
+		if err != nil {
+			return err
+		}
+
+		err = change(tx.dr, deckId, sentenceId)
+		if err != nil {
+			return err
+		}
+
+		deckItem = model.DeckItem{DeckId: deckId, SentenceId: sentenceId}
+
+		// 紐づけの範囲を限定しないデッキでは、紐づけは変わらない
+		isScoped, err := tx.dr.IsAssociationScoped(deckId)
+		if err != nil {
+			return err
+		}
+		if !isScoped {
+			return nil
+		}
+
+		deckItem.AssociationJobId, err = tx.su.reAssociateSentenceLater(loginUserId, sentenceId)
+		return err
+	})
+	if err != nil {
+		return model.DeckItem{}, err
+	}
+
+	return deckItem, nil
+}
+
+func (du *DeckUsecase) reAssociateScopedSentencesLater(loginUserId uint64, previousScopes []model.AssociationScope) (uint64, error) {
+	// 変更前または変更後に、紐づけの範囲を限定するデッキに含まれていたSentenceのsentences_wordsを再構築
+	// どちらにも含まれないSentenceは、変更前後とも全Wordと紐づけるため変わらない
+	scopes, err := du.dr.GetAssociationScopes(loginUserId)
+	if err != nil {
+		return 0, err
+	}
+
+	sentenceIdSet := map[uint64]bool{}
+	for _, scope := range append(previousScopes, scopes...) {
+		for _, sentenceId := range scope.SentenceIds {
+			sentenceIdSet[sentenceId] = true
+		}
+	}
+	if len(sentenceIdSet) == 0 {
+		return 0, nil
+	}
+
+	var sentenceIds []uint64
+	for sentenceId := range sentenceIdSet {
+		sentenceIds = append(sentenceIds, sentenceId)
+	}
+	sort.Slice(sentenceIds, func(i, j int) bool {
+		return sentenceIds[i] < sentenceIds[j]
+	})
+
+	return du.su.reAssociateSentencesLater(loginUserId, sentenceIds)
+}
+
+func (du *DeckUsecase) checkParentDeck(loginUserId uint64, parentId *uint64) error {
+	// 親のデッキは、同じUserのデッキのみ指定できる
+	if parentId == nil {
+		return nil
+	}
+
+	_, err := du.dr.GetDeckById(loginUserId, *parentId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return NewNotFoundError("parent deck")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (du *DeckUsecase) checkDeckItemOwner(loginUserId, deckId, wordId, sentenceId uint64) error {
+	// デッキと、追加または削除するWordまたはSentenceが、いずれもloginUserIdのものであるかを確認
+	_, err := du.GetDeckById(loginUserId, deckId)
+	if err != nil {
+		return err
+	}
+
+	if wordId != 0 {
+		isWordOwner, err := du.wr.IsWordOwner(wordId, loginUserId)
+		if err != nil {
+			return err
+		}
+		if !isWordOwner {
+			return ErrWordNotFound
+		}
+	}
+
+	if sentenceId != 0 {
+		isSentenceOwner, err := du.sr.IsSentenceOwner(sentenceId, loginUserId)
+		if err != nil {
+			return err
+		}
+		if !isSentenceOwner {
+			return ErrSentenceNotFound
+		}
+	}
+
+	return nil
+}
+
+func (du *DeckUsecase) newDuplicateDeckError(loginUserId uint64, parentId *uint64, name string) error {
+	existingDeck, err := du.dr.GetDeckByName(loginUserId, parentId, name)
+	if err != nil {
+		return err
+	}
+
+	return NewDuplicateError("deck", model.DuplicateErrorDetails{ExistingId: existingDeck.Id})
+}
+
+func equalParentIds(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
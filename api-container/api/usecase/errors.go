@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"api/model"
+	"fmt"
+)
+
+// Usecaseが返すエラーの種類
+// ControllerはこのKindでHTTPステータスを決める
+type ErrorKind string
+
+const (
+	ErrorKindNotFound        ErrorKind = "not_found"
+	ErrorKindForbidden       ErrorKind = "forbidden"
+	ErrorKindValidation      ErrorKind = "validation_failed"
+	ErrorKindConflict        ErrorKind = "conflict"
+	ErrorKindUnauthenticated ErrorKind = "unauthenticated"
+)
+
+// クライアントにそのまま返してよい、種類付きのエラー
+// これ以外のエラー（DBのエラーなど）は、内容をクライアントに返さない
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	// 項目ごとのエラーなど、Messageに含めきれない情報
+	Details interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewNotFoundError(resource string) *Error {
+	return &Error{
+		Kind:    ErrorKindNotFound,
+		Message: fmt.Sprintf("%s not found", resource),
+	}
+}
+
+func NewForbiddenError(message string) *Error {
+	return &Error{
+		Kind:    ErrorKindForbidden,
+		Message: message,
+	}
+}
+
+func NewValidationError(message string, details interface{}) *Error {
+	return &Error{
+		Kind:    ErrorKindValidation,
+		Message: message,
+		Details: details,
+	}
+}
+
+func NewConflictError(message string) *Error {
+	return &Error{
+		Kind:    ErrorKindConflict,
+		Message: message,
+	}
+}
+
+// 既に存在するリソースと重複した場合のエラー
+// クライアントが既存のリソースを参照できるよう、detailsにidを含める
+func NewDuplicateError(resource string, details model.DuplicateErrorDetails) *Error {
+	return &Error{
+		Kind:    ErrorKindConflict,
+		Message: fmt.Sprintf("%s already exists", resource),
+		Details: details,
+	}
+}
+
+func NewUnauthenticatedError(message string) *Error {
+	return &Error{
+		Kind:    ErrorKindUnauthenticated,
+		Message: message,
+	}
+}
+
+// 所有者でないリソースも、他のUserのリソースの有無が分からないよう存在しないものとして扱う
+var (
+	ErrWordNotFound              = NewNotFoundError("word")
+	ErrSentenceNotFound          = NewNotFoundError("sentence")
+	ErrNotationNotFound          = NewNotFoundError("notation")
+	ErrJobNotFound               = NewNotFoundError("job")
+	ErrOverrideNotFound          = NewNotFoundError("association override")
+	ErrTagNotFound               = NewNotFoundError("tag")
+	ErrDeckNotFound              = NewNotFoundError("deck")
+	ErrWordSenseNotFound         = NewNotFoundError("word sense")
+	ErrSentenceWordSenseNotFound = NewNotFoundError("sentence word sense")
+	ErrWordRelationNotFound      = NewNotFoundError("word relation")
+)
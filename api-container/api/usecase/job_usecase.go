@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// WordとSentenceの紐づけの再構築を、いつ行うか
+type AssociationMode string
+
+const (
+	// Word、Notation、Sentenceを変更したリクエスト内で行う
+	AssociationModeSync AssociationMode = "sync"
+	// ジョブを追加し、ワーカーがリクエストとは非同期に行う
+	AssociationModeAsync AssociationMode = "async"
+)
+
+func ParseAssociationMode(value string) (AssociationMode, error) {
+	// 設定値からAssociationModeを作成
+	switch AssociationMode(value) {
+	case "", AssociationModeAsync:
+		return AssociationModeAsync, nil
+	case AssociationModeSync:
+		return AssociationModeSync, nil
+	default:
+		return "", fmt.Errorf("unknown association mode: %s", value)
+	}
+}
+
+const (
+	// ジョブの最大試行回数
+	// この回数失敗したジョブはdeadとし、再度実行しない
+	defaultJobMaxAttempts = 5
+	// 失敗したジョブを再度実行するまでの待ち時間の上限
+	maxJobRetryDelay = 5 * time.Minute
+	// 実行中のままこの時間を過ぎたジョブは、ワーカーが停止したものとみなし再度実行する
+	jobLockTimeout = 10 * time.Minute
+)
+
+func enqueueAssociationJob(jr repository.IJobRepository, userId uint64, kind string, targetIds []uint64) (uint64, error) {
+	// 紐づけを再構築するジョブを追加し、そのIdを返す
+	// 対象が無い場合はジョブを追加せず0を返す
+	if len(targetIds) == 0 {
+		return 0, nil
+	}
+
+	jobCreation := model.JobCreation{
+		UserId:      userId,
+		Kind:        kind,
+		TargetIds:   targetIds,
+		MaxAttempts: defaultJobMaxAttempts,
+	}
+
+	job, err := jr.InsertJob(jobCreation)
+	if err != nil {
+		return 0, err
+	}
+
+	return job.Id, nil
+}
+
+func getUnfinishedAssociationJobId(jr repository.IJobRepository, userId uint64, kind string, targetId uint64) (uint64, error) {
+	// targetIdの紐づけを再構築する、完了していないジョブのIdを返す
+	// そのようなジョブが無い場合は0を返す
+	job, err := jr.GetUnfinishedJobByTargetId(userId, kind, targetId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return job.Id, nil
+}
+
+type JobUsecase struct {
+	jr repository.IJobRepository
+	wu *WordUsecase
+	su *SentenceUsecase
+	// 現在時刻を取得する関数
+	now func() time.Time
+}
+
+func NewJobUsecase(
+	jr repository.IJobRepository,
+	wu *WordUsecase,
+	su *SentenceUsecase,
+) *JobUsecase {
+	return &JobUsecase{jr, wu, su, time.Now}
+}
+
+func (ju *JobUsecase) GetJobById(loginUserId, jobId uint64) (model.Job, error) {
+	job, err := ju.jr.GetJobById(loginUserId, jobId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// マッチするレコードが無い場合
+			return model.Job{}, ErrJobNotFound
+		}
+
+		return model.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (ju *JobUsecase) RunWorker(ctx context.Context, pollInterval time.Duration) {
+	// ctxがキャンセルされるまで、実行待ちのジョブを1件ずつ実行する
+	// 実行待ちのジョブが無い場合は、pollIntervalだけ待ってから再度確認する
+	// 実行待ちのジョブが続く場合も、ジョブごとにキャンセルを確認する
+	for ctx.Err() == nil {
+		processed, err := ju.ProcessNextJob()
+		if err != nil {
+			log.Printf("job worker: %v", err)
+		}
+
+		if processed && err == nil {
+			// 続けて実行待ちのジョブがある可能性があるため、待たずに確認する
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (ju *JobUsecase) ProcessNextJob() (bool, error) {
+	// 実行待ちのジョブを1件実行し、実行したかを返す
+	// ジョブの失敗は再試行またはdeadとして記録し、エラーとしては返さない
+	// 実行中に最大試行回数を使い切ったままワーカーが停止したジョブは、取り出さずにdeadにする
+	err := ju.jr.DeadLetterStaleJobs(jobLockTimeout, "job timed out")
+	if err != nil {
+		return false, err
+	}
+
+	job, err := ju.jr.ClaimNextJob(jobLockTimeout)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	runErr := ju.runJob(job)
+	if runErr == nil {
+		return true, ju.jr.CompleteJob(job.Id)
+	}
+
+	// DBのエラーなどの内容はログにのみ出力し、ジョブにはクライアントに返してよいメッセージを記録する
+	log.Printf("job %d failed (attempt %d/%d): %v", job.Id, job.Attempts, job.MaxAttempts, runErr)
+	lastError := jobErrorMessage(runErr)
+
+	if job.Attempts >= job.MaxAttempts {
+		return true, ju.jr.DeadLetterJob(job.Id, lastError)
+	}
+
+	return true, ju.jr.RetryJob(job.Id, lastError, ju.now().Add(jobRetryDelay(job.Attempts)))
+}
+
+func jobErrorMessage(err error) string {
+	// ジョブの失敗として記録し、GET /jobs/:id で返すメッセージ
+	// 種類付きのエラーはそのメッセージを、それ以外は固定のメッセージを返す
+	var usecaseErr *Error
+	if errors.As(err, &usecaseErr) {
+		return usecaseErr.Message
+	}
+
+	return "association failed"
+}
+
+func (ju *JobUsecase) runJob(job model.Job) error {
+	switch job.Kind {
+	case model.JobKindReassociateWords:
+		return ju.wu.ReAssociateWordsWithAllSentences(job.UserId, job.TargetIds)
+	case model.JobKindReassociateSentences:
+		return ju.su.ReAssociateSentencesWithAllWords(job.UserId, job.TargetIds)
+	default:
+		return fmt.Errorf("unknown job kind: %s", job.Kind)
+	}
+}
+
+func jobRetryDelay(attempts uint64) time.Duration {
+	// 失敗した回数に応じて、1秒、2秒、4秒...と待ち時間を延ばす
+	delay := time.Second
+	for i := uint64(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxJobRetryDelay {
+			return maxJobRetryDelay
+		}
+	}
+
+	return delay
+}
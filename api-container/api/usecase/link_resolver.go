@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"api/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 同じ範囲に出現する複数の語のうち、どれをリンクとするかを決める規則
+type TieBreakRule string
+
+const (
+	// ユーザーが入力したWord、Notationを、自動生成されたNotationより優先する
+	TieBreakNotationKind TieBreakRule = "notation_kind"
+	// 新しく作成されたWordを優先する
+	TieBreakNewer TieBreakRule = "newer"
+	// 古くに作成されたWordを優先する
+	TieBreakOlder TieBreakRule = "older"
+)
+
+// 規則が指定されなかった場合に使用する規則
+var DefaultTieBreakRules = []TieBreakRule{TieBreakNotationKind, TieBreakNewer}
+
+// リンクの候補が選ばれなかった理由
+const (
+	// より前から始まる、またはより長い候補と重なり合う
+	DiscardReasonOverlap = "overlap"
+	// 同じ範囲の候補があり、TieBreakRuleにより選ばれなかった
+	DiscardReasonTieBreak = "tie_break"
+)
+
+// 文中に出現した語の、リンクの候補
+type LinkCandidate struct {
+	Match
+	WordId uint64
+	// 出現した語がNotationの場合はそのNotationの作成元
+	// Word自体の場合は空文字列
+	NotationKind string
+	// 出現した語のWordの作成日時
+	CreatedAt time.Time
+}
+
+type DiscardedLinkCandidate struct {
+	LinkCandidate
+	Reason string
+	// この候補の代わりに選ばれた候補
+	Winner LinkCandidate
+}
+
+type LinkResolution struct {
+	// 選ばれた候補。重なり合わず、Startの昇順に並ぶ
+	Selected []LinkCandidate
+	// 選ばれなかった候補
+	Discarded []DiscardedLinkCandidate
+}
+
+// 文中の重なり合うリンクの候補から、リンクとするものを決める
+// 各位置では最も長い候補を優先し、同じ範囲の候補はTieBreakRuleの順に比較する
+type LinkResolver struct {
+	rules []TieBreakRule
+}
+
+func NewLinkResolver(rules []TieBreakRule) *LinkResolver {
+	return &LinkResolver{rules}
+}
+
+func ParseTieBreakRules(value string) ([]TieBreakRule, error) {
+	// 設定値 "notation_kind,newer" のようなカンマ区切りの文字列からTieBreakRuleの列を作成
+	if strings.TrimSpace(value) == "" {
+		return DefaultTieBreakRules, nil
+	}
+
+	var rules []TieBreakRule
+	for _, name := range strings.Split(value, ",") {
+		rule := TieBreakRule(strings.TrimSpace(name))
+		switch rule {
+		case TieBreakNotationKind, TieBreakNewer, TieBreakOlder:
+			rules = append(rules, rule)
+		default:
+			return nil, fmt.Errorf("unknown tie break rule: %s", name)
+		}
+	}
+
+	return rules, nil
+}
+
+func (lr *LinkResolver) Resolve(candidates []LinkCandidate) LinkResolution {
+	// 先頭から1回の走査で、重なり合わない候補を選ぶ
+	sorted := make([]LinkCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			// 同じ位置から始まる場合は、より長いものを優先
+			return a.End > b.End
+		}
+		return lr.prefers(a, b)
+	})
+
+	resolution := LinkResolution{}
+	for _, candidate := range sorted {
+		if len(resolution.Selected) > 0 {
+			last := resolution.Selected[len(resolution.Selected)-1]
+			if candidate.Start < last.End {
+				reason := DiscardReasonOverlap
+				if candidate.Start == last.Start && candidate.End == last.End {
+					reason = DiscardReasonTieBreak
+				}
+
+				resolution.Discarded = append(resolution.Discarded, DiscardedLinkCandidate{
+					LinkCandidate: candidate,
+					Reason:        reason,
+					Winner:        last,
+				})
+				continue
+			}
+		}
+
+		resolution.Selected = append(resolution.Selected, candidate)
+	}
+
+	return resolution
+}
+
+func (lr *LinkResolver) prefers(a, b LinkCandidate) bool {
+	// 同じ範囲の候補a、bについて、aを優先する場合にtrueを返す
+	for _, rule := range lr.rules {
+		switch rule {
+		case TieBreakNotationKind:
+			if notationKindPriority(a.NotationKind) != notationKindPriority(b.NotationKind) {
+				return notationKindPriority(a.NotationKind) < notationKindPriority(b.NotationKind)
+			}
+		case TieBreakNewer:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			if a.WordId != b.WordId {
+				return a.WordId > b.WordId
+			}
+		case TieBreakOlder:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			if a.WordId != b.WordId {
+				return a.WordId < b.WordId
+			}
+		}
+	}
+
+	// どの規則でも決まらない場合も、結果が常に同じになるようにする
+	if a.WordId != b.WordId {
+		return a.WordId < b.WordId
+	}
+	return a.TermIndex < b.TermIndex
+}
+
+func notationKindPriority(kind string) int {
+	// 値が小さいほど優先する
+	switch kind {
+	case "", model.NotationKindManual:
+		return 0
+	case model.NotationKindImport:
+		return 1
+	case model.NotationKindConjugation:
+		return 2
+	case model.NotationKindStem:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ikawaha/kagome-dict/ipa"
+	"github.com/ikawaha/kagome/v2/tokenizer"
+)
+
+// 文中における語の出現箇所
+type Match struct {
+	// ルーン単位のオフセット
+	// sentence中の[Start, End)の範囲に出現する
+	Start int
+	End   int
+	// IMatcher.Prepareに渡したtermsのうち、出現した語のインデックス
+	TermIndex int
+}
+
+// 文中に語が含まれるかを判定する方式
+type IMatcher interface {
+	// termsを探索するIPreparedMatcherを作成
+	// 同じtermsで複数の文を探索する場合、語の解析を1回で済ませるために使用する
+	Prepare(terms []string) IPreparedMatcher
+}
+
+type IPreparedMatcher interface {
+	// sentence中におけるtermsの出現箇所を、Startの昇順で全て返す
+	FindAll(sentence string) []Match
+}
+
+func NewMatcher(name string) (IMatcher, error) {
+	// 設定値からIMatcherを作成
+	switch name {
+	case "", "morphological":
+		return NewMorphologicalMatcher()
+	case "substring":
+		return NewSubstringMatcher(), nil
+	case "aho_corasick":
+		return NewAhoCorasickMatcher(), nil
+	default:
+		return nil, fmt.Errorf("unknown matcher: %s", name)
+	}
+}
+
+// 部分文字列として含まれるかで判定するIMatcher
+// 「日」は「日本」「毎日」にもマッチする
+type SubstringMatcher struct{}
+
+func NewSubstringMatcher() IMatcher {
+	return &SubstringMatcher{}
+}
+
+func (m *SubstringMatcher) Prepare(terms []string) IPreparedMatcher {
+	return &preparedSubstringMatcher{terms}
+}
+
+type preparedSubstringMatcher struct {
+	terms []string
+}
+
+func (pm *preparedSubstringMatcher) FindAll(sentence string) []Match {
+	var matches []Match
+	for termIndex, term := range pm.terms {
+		// 空文字列は全ての文にマッチしてしまうため探索しない
+		if term == "" {
+			continue
+		}
+
+		for byteOffset := 0; byteOffset < len(sentence); {
+			i := strings.Index(sentence[byteOffset:], term)
+			if i < 0 {
+				break
+			}
+
+			byteStart := byteOffset + i
+			start := utf8.RuneCountInString(sentence[:byteStart])
+			matches = append(matches, Match{
+				Start:     start,
+				End:       start + utf8.RuneCountInString(term),
+				TermIndex: termIndex,
+			})
+
+			// 重なり合う出現箇所も探索するため、1文字だけ進める
+			_, size := utf8.DecodeRuneInString(sentence[byteStart:])
+			byteOffset = byteStart + size
+		}
+	}
+
+	sortMatches(matches)
+
+	return matches
+}
+
+// 形態素解析を行い、形態素の原形が一致するかで判定するIMatcher
+// 「日」は「日本」「毎日」にマッチせず、「食べる」は「食べた」にマッチする
+type MorphologicalMatcher struct {
+	t *tokenizer.Tokenizer
+}
+
+func NewMorphologicalMatcher() (IMatcher, error) {
+	t, err := tokenizer.New(ipa.Dict(), tokenizer.OmitBosEos())
+	if err != nil {
+		return nil, err
+	}
+
+	return &MorphologicalMatcher{t}, nil
+}
+
+func (m *MorphologicalMatcher) Prepare(terms []string) IPreparedMatcher {
+	// 各termを形態素の原形の列に変換し、先頭の形態素ごとにまとめておく
+	termIndexesByHead := map[string][]int{}
+	termForms := make([][]string, len(terms))
+	for termIndex, term := range terms {
+		if term == "" {
+			continue
+		}
+
+		forms := m.baseForms(term)
+		if len(forms) == 0 {
+			continue
+		}
+
+		termForms[termIndex] = forms
+		termIndexesByHead[forms[0]] = append(termIndexesByHead[forms[0]], termIndex)
+	}
+
+	return &preparedMorphologicalMatcher{m, termForms, termIndexesByHead}
+}
+
+func (m *MorphologicalMatcher) baseForms(text string) []string {
+	var forms []string
+	for _, token := range m.t.Tokenize(text) {
+		forms = append(forms, baseForm(token))
+	}
+
+	return forms
+}
+
+func baseForm(token tokenizer.Token) string {
+	// 辞書に原形が無い形態素は表層形を原形とみなす
+	form, ok := token.BaseForm()
+	if !ok || form == "" || form == "*" {
+		return token.Surface
+	}
+
+	return form
+}
+
+type preparedMorphologicalMatcher struct {
+	m                 *MorphologicalMatcher
+	termForms         [][]string
+	termIndexesByHead map[string][]int
+}
+
+func (pm *preparedMorphologicalMatcher) FindAll(sentence string) []Match {
+	tokens := pm.m.t.Tokenize(sentence)
+	forms := make([]string, len(tokens))
+	for i, token := range tokens {
+		forms[i] = baseForm(token)
+	}
+
+	var matches []Match
+	for i := range tokens {
+		for _, termIndex := range pm.termIndexesByHead[forms[i]] {
+			termForms := pm.termForms[termIndex]
+			if !hasPrefix(forms[i:], termForms) {
+				continue
+			}
+
+			matches = append(matches, Match{
+				Start:     tokens[i].Start,
+				End:       tokens[i+len(termForms)-1].End,
+				TermIndex: termIndex,
+			})
+		}
+	}
+
+	sortMatches(matches)
+
+	return matches
+}
+
+func hasPrefix(forms, prefix []string) bool {
+	if len(forms) < len(prefix) {
+		return false
+	}
+
+	for i := range prefix {
+		if forms[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortMatches(matches []Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].TermIndex < matches[j].TermIndex
+	})
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 直前の仮名と合わせて1モーラとなる小書きの仮名
+// 「っ」は1モーラとして数えるため含めない
+const smallKana = "ぁぃぅぇぉゃゅょゎァィゥェォャュョヮ"
+
+func IsKana(s string) bool {
+	// sがひらがな、カタカナ、長音符のみからなるかを判定
+	for _, r := range s {
+		if r == 'ー' {
+			continue
+		}
+		if !unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func CountMorae(reading string) int {
+	// 仮名の読みのモーラ数を数える
+	// 「きゃ」のような拗音は1モーラ、「っ」「ん」「ー」はそれぞれ1モーラとする
+	count := 0
+	for _, r := range reading {
+		if strings.ContainsRune(smallKana, r) {
+			continue
+		}
+		count++
+	}
+
+	return count
+}
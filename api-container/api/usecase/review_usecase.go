@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+const (
+	// limitが指定されなかった場合の取得件数
+	defaultDueReviewsLimit = 20
+	// 1回で取得できる最大件数
+	maxDueReviewsLimit = 100
+)
+
+type ReviewUsecase struct {
+	rvr repository.IReviewRepository
+	wr  repository.IWordRepository
+	swr repository.ISentencesWordsRepository
+	uow repository.IUnitOfWork
+	s   IScheduler
+	// 現在時刻を取得する関数
+	now func() time.Time
+}
+
+func NewReviewUsecase(
+	rvr repository.IReviewRepository,
+	wr repository.IWordRepository,
+	swr repository.ISentencesWordsRepository,
+	uow repository.IUnitOfWork,
+	s IScheduler,
+) *ReviewUsecase {
+	return &ReviewUsecase{rvr, wr, swr, uow, s, time.Now}
+}
+
+func (rvu *ReviewUsecase) GetDueReviews(loginUserId, limit uint64) ([]model.DueReview, error) {
+	// 今日中に復習期限を迎えるWordを、紐づくSentenceとともに取得
+	if limit == 0 {
+		limit = defaultDueReviewsLimit
+	}
+	if limit > maxDueReviewsLimit {
+		limit = maxDueReviewsLimit
+	}
+
+	// 今日の終わり（明日の0時）より前に期限を迎えるものを対象とする
+	now := rvu.now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	dueReviews, err := rvu.rvr.GetDueReviews(loginUserId, tomorrow, limit)
+	if err != nil {
+		return []model.DueReview{}, err
+	}
+
+	// 例文として、各Wordに紐づくSentenceを取得
+	// SentenceとWordの出現箇所の取得は、Wordの件数によらずそれぞれ1回のクエリで行う
+	wordIds := make([]uint64, len(dueReviews))
+	for i, dueReview := range dueReviews {
+		wordIds[i] = dueReview.Word.Id
+	}
+
+	associatedSentences, err := rvu.swr.GetUserAssociatedSentencesByWordIds(wordIds)
+	if err != nil {
+		return []model.DueReview{}, err
+	}
+
+	sentencesByWordId := map[uint64][]model.Sentence{}
+	sentenceIdSet := map[uint64]bool{}
+	var sentenceIds []uint64
+	for _, associatedSentence := range associatedSentences {
+		sentencesByWordId[associatedSentence.WordId] = append(sentencesByWordId[associatedSentence.WordId], associatedSentence.Sentence)
+		if !sentenceIdSet[associatedSentence.Sentence.Id] {
+			sentenceIdSet[associatedSentence.Sentence.Id] = true
+			sentenceIds = append(sentenceIds, associatedSentence.Sentence.Id)
+		}
+	}
+
+	// 紐づけの作成時に記録した出現箇所から、Wordを空欄にしたクローズ問題を作成
+	occurrences, err := rvu.swr.GetOccurrencesBySentenceIds(sentenceIds)
+	if err != nil {
+		return []model.DueReview{}, err
+	}
+
+	// 出現箇所は、WordとSentenceの組ごとに前から順に並べる
+	occurrencesBySentenceWord := map[model.SentenceWord][]model.SentenceWordOccurrence{}
+	for _, occurrence := range occurrences {
+		key := model.SentenceWord{SentenceId: occurrence.SentenceId, WordId: occurrence.WordId}
+		occurrencesBySentenceWord[key] = append(occurrencesBySentenceWord[key], occurrence)
+	}
+
+	for i, dueReview := range dueReviews {
+		reviewSentences := []model.ReviewSentence{}
+		for _, sentence := range sentencesByWordId[dueReview.Word.Id] {
+			key := model.SentenceWord{SentenceId: sentence.Id, WordId: dueReview.Word.Id}
+			reviewSentences = append(reviewSentences, model.ReviewSentence{
+				Sentence: sentence,
+				Cloze:    RenderCloze(sentence.Sentence, occurrencesBySentenceWord[key]),
+			})
+		}
+
+		dueReviews[i].Sentences = reviewSentences
+	}
+
+	return dueReviews, nil
+}
+
+// クローズ問題で、Wordの出現箇所を置き換える文字列
+const clozeBlank = "＿＿＿"
+
+func RenderCloze(sentence string, occurrences []model.SentenceWordOccurrence) string {
+	// occurrencesのうちリンクとして選ばれた箇所を、clozeBlankに置き換える
+	// occurrencesは出現箇所の前から順に並んでいることを前提とする
+	runes := []rune(sentence)
+
+	var b strings.Builder
+	pos := 0
+	for _, occurrence := range occurrences {
+		// リンクとして選ばれなかった、重なり合う、または範囲外の出現箇所は無視する
+		if occurrence.DiscardReason != "" || occurrence.Start < pos || occurrence.End > len(runes) || occurrence.Start >= occurrence.End {
+			continue
+		}
+
+		b.WriteString(string(runes[pos:occurrence.Start]))
+		b.WriteString(clozeBlank)
+		pos = occurrence.End
+	}
+	b.WriteString(string(runes[pos:]))
+
+	return b.String()
+}
+
+func (rvu *ReviewUsecase) ReviewWord(reviewCreation model.ReviewCreation) (model.Review, error) {
+	// WordIdのWordを、Gradeの評価で復習したものとして記録し、次の復習期限を決める
+
+	// WordIdの所有者がloginUserIdでない場合エラーを返す
+	isWordOwner, err := rvu.wr.IsWordOwner(reviewCreation.WordId, reviewCreation.LoginUserId)
+	if err != nil {
+		return model.Review{}, err
+	}
+	if !isWordOwner {
+		return model.Review{}, ErrWordNotFound
+	}
+
+	// 同じWordの復習が同時に記録された場合に、一方の結果が失われないよう、
+	// 現在の状態の取得から更新までを、Wordをロックしたトランザクション内で行う
+	var updatedReview model.Review
+	err = rvu.uow.Do(func(repos repository.Repositories) error {
+		updatedReview, err = rvu.reviewWord(repos.Review, reviewCreation)
+		return err
+	})
+	if err != nil {
+		return model.Review{}, err
+	}
+
+	return updatedReview, nil
+}
+
+func (rvu *ReviewUsecase) reviewWord(rvr repository.IReviewRepository, reviewCreation model.ReviewCreation) (model.Review, error) {
+	err := rvr.LockWordForReview(reviewCreation.WordId)
+	if err != nil {
+		return model.Review{}, err
+	}
+
+	now := rvu.now()
+
+	// 1度も復習していない場合は、初期状態から計算する
+	schedule := rvu.s.NewSchedule(now)
+	review, err := rvr.GetReviewByWordId(reviewCreation.WordId)
+	if err == nil {
+		schedule = model.ReviewSchedule{
+			EaseFactor:   review.EaseFactor,
+			IntervalDays: review.IntervalDays,
+			Repetitions:  review.Repetitions,
+			DueAt:        review.DueAt,
+		}
+	} else if err != sql.ErrNoRows {
+		return model.Review{}, err
+	}
+
+	nextSchedule, err := rvu.s.Schedule(schedule, reviewCreation.Grade, now)
+	if err != nil {
+		return model.Review{}, err
+	}
+
+	reviewUpsert := model.ReviewUpsert{
+		WordId:     reviewCreation.WordId,
+		Schedule:   nextSchedule,
+		Grade:      reviewCreation.Grade,
+		ReviewedAt: now,
+	}
+
+	return rvr.UpsertReview(reviewUpsert)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"api/model"
+	"math"
+	"time"
+)
+
+// 復習の評価の最大値
+// 0: 全く思い出せなかった ~ 5: 完璧に思い出せた
+const maxReviewGrade = 5
+
+var ErrInvalidGrade = NewValidationError("grade must be between 0 and 5", nil)
+
+// 復習の評価から、次の復習期限を決める方式
+// データベースに依存せず、渡された状態と時刻のみから次の状態を計算する
+type IScheduler interface {
+	// 1度も復習していないWordの状態を作成
+	NewSchedule(now time.Time) model.ReviewSchedule
+	// scheduleの状態のWordを、gradeの評価で復習した後の状態を計算
+	Schedule(schedule model.ReviewSchedule, grade uint64, now time.Time) (model.ReviewSchedule, error)
+}
+
+// SuperMemo 2 (SM-2) アルゴリズムによるIScheduler
+type SM2Scheduler struct{}
+
+const (
+	// 初期の易しさ係数
+	sm2InitialEaseFactor = 2.5
+	// 易しさ係数の下限
+	sm2MinEaseFactor = 1.3
+	// この評価未満の場合は思い出せなかったものとし、最初から復習しなおす
+	sm2PassingGrade = 3
+)
+
+func NewSM2Scheduler() IScheduler {
+	return &SM2Scheduler{}
+}
+
+func (s *SM2Scheduler) NewSchedule(now time.Time) model.ReviewSchedule {
+	// 1度も復習していないWordは、すぐに復習期限を迎える
+	return model.ReviewSchedule{
+		EaseFactor:   sm2InitialEaseFactor,
+		IntervalDays: 0,
+		Repetitions:  0,
+		DueAt:        now,
+	}
+}
+
+func (s *SM2Scheduler) Schedule(schedule model.ReviewSchedule, grade uint64, now time.Time) (model.ReviewSchedule, error) {
+	if grade > maxReviewGrade {
+		return model.ReviewSchedule{}, ErrInvalidGrade
+	}
+
+	next := schedule
+	if grade < sm2PassingGrade {
+		// 思い出せなかった場合、連続正解数をリセットし1日後に復習する
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		switch schedule.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = uint64(math.Round(float64(schedule.IntervalDays) * schedule.EaseFactor))
+		}
+		next.Repetitions = schedule.Repetitions + 1
+	}
+
+	// 評価に応じて易しさ係数を更新
+	// EF' = EF + (0.1 - (5 - q) * (0.08 + (5 - q) * 0.02))
+	q := float64(maxReviewGrade - grade)
+	next.EaseFactor = math.Max(
+		sm2MinEaseFactor,
+		schedule.EaseFactor+(0.1-q*(0.08+q*0.02)),
+	)
+
+	next.DueAt = now.AddDate(0, 0, int(next.IntervalDays))
+
+	return next, nil
+}
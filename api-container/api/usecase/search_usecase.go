@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// limitが指定されなかった場合の、WordとSentenceそれぞれの取得件数
+	defaultSearchLimit = 20
+	// 1回で取得できる最大件数
+	maxSearchLimit = 100
+)
+
+var ErrEmptySearchQuery = NewValidationError("q is required", nil)
+
+type SearchUsecase struct {
+	wr repository.IWordRepository
+	sr repository.ISentenceRepository
+	nr repository.INotationRepository
+	au *AssociationUsecase
+}
+
+func NewSearchUsecase(
+	wr repository.IWordRepository,
+	sr repository.ISentenceRepository,
+	nr repository.INotationRepository,
+	au *AssociationUsecase,
+) *SearchUsecase {
+	return &SearchUsecase{wr, sr, nr, au}
+}
+
+func (seu *SearchUsecase) Search(searchQuery model.SearchQuery) (model.SearchResult, error) {
+	// searchQuery.Queryに一致するWordとSentenceを、それぞれスコアの高い順に取得
+	query := strings.TrimSpace(searchQuery.Query)
+	if query == "" {
+		return model.SearchResult{}, ErrEmptySearchQuery
+	}
+
+	limit := searchQuery.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	loginUserId := searchQuery.LoginUserId
+
+	wordSearchHits, err := seu.wr.SearchWords(loginUserId, query, limit, searchQuery.Offset)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	// どのNotationに一致したかを返すため、queryを含むNotationを取得
+	// Notationの取得は、Wordの件数によらず1回のクエリで行う
+	wordIds := make([]uint64, len(wordSearchHits))
+	for i, wordSearchHit := range wordSearchHits {
+		wordIds[i] = wordSearchHit.Word.Id
+	}
+
+	notations, err := seu.nr.GetNotationsByWordIds(wordIds)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	matchedNotationsByWordId := map[uint64][]model.Notation{}
+	for _, notation := range notations {
+		if containsFold(notation.Notation, query) {
+			matchedNotationsByWordId[notation.WordId] = append(matchedNotationsByWordId[notation.WordId], notation)
+		}
+	}
+
+	for i, wordSearchHit := range wordSearchHits {
+		wordSearchHits[i].MatchedNotations = matchedNotationsByWordId[wordSearchHit.Word.Id]
+	}
+
+	wordsTotalCount, err := seu.wr.GetSearchWordsCount(loginUserId, query)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	sentenceSearchHits, err := seu.sr.SearchSentences(loginUserId, query, limit, searchQuery.Offset)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	// 一覧取得時と同じく、記録済みの出現箇所からSentence中のWordへのリンクとなる箇所を求める
+	var sentences []model.Sentence
+	for _, sentenceSearchHit := range sentenceSearchHits {
+		sentences = append(sentences, sentenceSearchHit.Sentence)
+	}
+
+	sentenceWithLinks, err := seu.au.toSentencesWithLink(sentences)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	for i := range sentenceSearchHits {
+		sentenceSearchHits[i].Annotations = sentenceWithLinks[i].Annotations
+	}
+
+	sentencesTotalCount, err := seu.sr.GetSearchSentencesCount(loginUserId, query)
+	if err != nil {
+		return model.SearchResult{}, err
+	}
+
+	searchResult := model.SearchResult{
+		Words:               wordSearchHits,
+		WordsTotalCount:     wordsTotalCount,
+		Sentences:           sentenceSearchHits,
+		SentencesTotalCount: sentencesTotalCount,
+	}
+
+	return searchResult, nil
+}
+
+func HighlightMatches(text, query string) string {
+	// text中のqueryと一致する箇所を、大文字小文字を区別せず<mark>で囲む
+	// 結果はHTMLとして扱われるため、<mark>以外の部分は全てエスケープする
+	// あいまい検索でのみ一致した場合など、一致する箇所が無い場合はエスケープしたtextを返す
+	if query == "" {
+		return html.EscapeString(text)
+	}
+
+	textRunes := []rune(text)
+	foldedText := foldRunes(textRunes)
+	foldedQuery := foldRunes([]rune(query))
+
+	var builder strings.Builder
+	// 一致しない部分は、まとめてエスケープして書き込む
+	unmatchedStart := 0
+	for i := 0; i < len(textRunes); {
+		if hasRunePrefix(foldedText[i:], foldedQuery) {
+			builder.WriteString(html.EscapeString(string(textRunes[unmatchedStart:i])))
+			builder.WriteString("<mark>")
+			builder.WriteString(html.EscapeString(string(textRunes[i : i+len(foldedQuery)])))
+			builder.WriteString("</mark>")
+			i += len(foldedQuery)
+			unmatchedStart = i
+			continue
+		}
+
+		i++
+	}
+	builder.WriteString(html.EscapeString(string(textRunes[unmatchedStart:])))
+
+	return builder.String()
+}
+
+func containsFold(text, query string) bool {
+	// 大文字小文字を区別せず、textがqueryを含むかを判定
+	return strings.Contains(string(foldRunes([]rune(text))), string(foldRunes([]rune(query))))
+}
+
+func foldRunes(runes []rune) []rune {
+	// 1文字ずつ小文字に変換する
+	// strings.ToLowerと異なり、変換前後で文字数が変わらない
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(r)
+	}
+
+	return folded
+}
+
+func hasRunePrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+
+	for i := range prefix {
+		if runes[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
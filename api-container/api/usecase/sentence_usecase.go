@@ -4,14 +4,22 @@ import (
 	"api/model"
 	"api/repository"
 	"database/sql"
-	"strings"
 )
 
+var ErrInvalidAssociationOverrideKind = NewValidationError("kind must be pin or exclude", nil)
+
 type SentenceUsecase struct {
 	sr  repository.ISentenceRepository
 	wr  repository.IWordRepository
 	swr repository.ISentencesWordsRepository
 	nr  repository.INotationRepository
+	dr  repository.IDeckRepository
+	jr  repository.IJobRepository
+	uow repository.IUnitOfWork
+	m   IMatcher
+	lr  *LinkResolver
+	// Wordとの紐づけの再構築を、リクエスト内で行うかジョブとして行うか
+	mode AssociationMode
 }
 
 func NewSentenceUsecase(
@@ -19,12 +27,35 @@ func NewSentenceUsecase(
 	wr repository.IWordRepository,
 	swr repository.ISentencesWordsRepository,
 	nr repository.INotationRepository,
+	dr repository.IDeckRepository,
+	jr repository.IJobRepository,
+	uow repository.IUnitOfWork,
+	m IMatcher,
+	lr *LinkResolver,
+	mode AssociationMode,
 ) *SentenceUsecase {
-	return &SentenceUsecase{sr, wr, swr, nr}
+	return &SentenceUsecase{sr, wr, swr, nr, dr, jr, uow, m, lr, mode}
+}
+
+func (su *SentenceUsecase) withRepositories(repos repository.Repositories) *SentenceUsecase {
+	// トランザクション内のreposを使うSentenceUsecaseを作成
+	// 作成したSentenceUsecase内でのUnitOfWork.Doは、同じトランザクション内で実行される
+	return NewSentenceUsecase(
+		repos.Sentence,
+		repos.Word,
+		repos.SentencesWords,
+		repos.Notation,
+		repos.Deck,
+		repos.Job,
+		repository.NewTransactionalUnitOfWork(repos),
+		su.m,
+		su.lr,
+		su.mode,
+	)
 }
 
-func (su *SentenceUsecase) GetAllSentences(loginUserId, limit, offset uint64) ([]model.Sentence, error) {
-	sentences, err := su.sr.GetAllSentencesWithLimit(loginUserId, limit, offset)
+func (su *SentenceUsecase) GetAllSentences(loginUserId uint64, filter model.ListFilter, limit, offset uint64) ([]model.Sentence, error) {
+	sentences, err := su.sr.GetAllSentencesWithLimit(loginUserId, filter, limit, offset)
 	if err != nil {
 		return []model.Sentence{}, err
 	}
@@ -37,17 +68,43 @@ func (su *SentenceUsecase) GetSentenceById(loginUserId uint64, sentenceId uint64
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// マッチするレコードが無い場合
-			// Sentenceのゼロ値を返す
-			return model.Sentence{}, nil
+			return model.Sentence{}, ErrSentenceNotFound
 		}
 
 		return model.Sentence{}, err
 	}
 
+	sentence.Translations, err = su.sr.GetTranslationsBySentenceIds([]uint64{sentence.Id})
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	if su.mode == AssociationModeAsync {
+		// 紐づけの再構築が完了していない場合、そのジョブのIdを返す
+		sentence.AssociationJobId, err = getUnfinishedAssociationJobId(su.jr, loginUserId, model.JobKindReassociateSentences, sentence.Id)
+		if err != nil {
+			return model.Sentence{}, err
+		}
+	}
+
 	return sentence, nil
 }
 
 func (su *SentenceUsecase) CreateSentence(sentenceCreation model.SentenceCreation) (model.Sentence, error) {
+	var createdSentence model.Sentence
+	err := su.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		createdSentence, err = su.withRepositories(repos).createSentence(sentenceCreation)
+		return err
+	})
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	return createdSentence, nil
+}
+
+func (su *SentenceUsecase) createSentence(sentenceCreation model.SentenceCreation) (model.Sentence, error) {
 	loginUserId := sentenceCreation.LoginUserId
 
 	createdSentence, err := su.sr.InsertSentence(sentenceCreation)
@@ -55,40 +112,156 @@ func (su *SentenceUsecase) CreateSentence(sentenceCreation model.SentenceCreatio
 		return model.Sentence{}, err
 	}
 
+	createdSentence.Translations, err = su.sr.ReplaceTranslations(createdSentence.Id, sentenceCreation.Translations)
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	if su.mode == AssociationModeAsync {
+		// sentences_wordsへの追加はジョブとして行う
+		createdSentence.AssociationJobId, err = enqueueAssociationJob(su.jr, loginUserId, model.JobKindReassociateSentences, []uint64{createdSentence.Id})
+		if err != nil {
+			return model.Sentence{}, err
+		}
+
+		return createdSentence, nil
+	}
+
 	// 追加されたSentenceに既存のWordが含まれればsentences_wordsに追加
-	su.AssociateSentenceWithAllWords(loginUserId, createdSentence.Id)
+	_, err = su.AssociateSentenceWithAllWords(loginUserId, createdSentence.Id)
+	if err != nil {
+		return model.Sentence{}, err
+	}
 
 	return createdSentence, nil
 }
 
 func (su *SentenceUsecase) CreateMultipleSentences(sentenceCreations []model.SentenceCreation) ([]model.Sentence, error) {
-	// TODO 1件でも失敗したらロールバックする実装に変更
+	// 各項目はリクエストの検証で確認済みとする
+	// 1件でも失敗した場合は全件ロールバックする
 	var createdSentences []model.Sentence
+	err := su.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		createdSentences, err = su.withRepositories(repos).createMultipleSentences(sentenceCreations)
+		return err
+	})
+	if err != nil {
+		return []model.Sentence{}, err
+	}
+	
+	return createdSentences, nil
+}
+
+func (su *SentenceUsecase) createMultipleSentences(sentenceCreations []model.SentenceCreation) ([]model.Sentence, error) {
+	var createdSentences []model.Sentence
+	sentencesByUserId := map[uint64][]model.Sentence{}
+	var userIds []uint64
 	for _, sentenceCreation := range sentenceCreations {
-		createdSentence, err := su.CreateSentence(sentenceCreation)
+		createdSentence, err := su.sr.InsertSentence(sentenceCreation)
+		if err != nil {
+			return []model.Sentence{}, err
+		}
+
+		createdSentence.Translations, err = su.sr.ReplaceTranslations(createdSentence.Id, sentenceCreation.Translations)
 		if err != nil {
 			return []model.Sentence{}, err
 		}
 
 		createdSentences = append(createdSentences, createdSentence)
+
+		userId := sentenceCreation.LoginUserId
+		if _, ok := sentencesByUserId[userId]; !ok {
+			userIds = append(userIds, userId)
+		}
+		sentencesByUserId[userId] = append(sentencesByUserId[userId], createdSentence)
 	}
-	
+
+	if su.mode == AssociationModeAsync {
+		// ユーザーごとに、追加した全Sentenceに対するジョブを1つ追加する
+		jobIdByUserId := map[uint64]uint64{}
+		for _, userId := range userIds {
+			var sentenceIds []uint64
+			for _, sentence := range sentencesByUserId[userId] {
+				sentenceIds = append(sentenceIds, sentence.Id)
+			}
+
+			jobId, err := enqueueAssociationJob(su.jr, userId, model.JobKindReassociateSentences, sentenceIds)
+			if err != nil {
+				return []model.Sentence{}, err
+			}
+			jobIdByUserId[userId] = jobId
+		}
+
+		for i := range createdSentences {
+			createdSentences[i].AssociationJobId = jobIdByUserId[createdSentences[i].UserId]
+		}
+
+		return createdSentences, nil
+	}
+
+	// 1件ずつではなく、追加した全Sentenceに対してまとめてsentences_wordsへの追加を行う
+	for _, userId := range userIds {
+		_, err := su.associateSentencesWithAllWords(userId, sentencesByUserId[userId])
+		if err != nil {
+			return []model.Sentence{}, err
+		}
+	}
+
 	return createdSentences, nil
 }
 
 func (su *SentenceUsecase) UpdateSentence(sentenceUpdate model.SentenceUpdate) (model.Sentence, error) {
+	// Sentence更新、sentences_wordsの再構築をトランザクション内で実行
+	var updatedSentence model.Sentence
+	err := su.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		updatedSentence, err = su.withRepositories(repos).updateSentence(sentenceUpdate)
+		return err
+	})
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	return updatedSentence, nil
+}
+
+func (su *SentenceUsecase) updateSentence(sentenceUpdate model.SentenceUpdate) (model.Sentence, error) {
 	updatedSentence, err := su.sr.UpdateSentence(sentenceUpdate)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが更新されなかった場合
-			// Sentenceのゼロ値を返す
-			return model.Sentence{}, nil
+			return model.Sentence{}, ErrSentenceNotFound
 		}
 
 		return model.Sentence{}, err
 	}
 
-	su.ReAssociateSentenceWithAllWords(sentenceUpdate.LoginUserId, sentenceUpdate.Id)
+	// 訳文は、リクエストに含まれるもので置き換える
+	updatedSentence.Translations, err = su.sr.ReplaceTranslations(updatedSentence.Id, sentenceUpdate.Translations)
+	if err != nil {
+		return model.Sentence{}, err
+	}
+
+	if su.mode == AssociationModeAsync {
+		// 更新前のSentenceにおける出現箇所は使えないため、紐づけは先に削除しておく
+		err = su.swr.DeleteAllAssociationBySentenceId(sentenceUpdate.Id)
+		if err != nil {
+			return model.Sentence{}, err
+		}
+
+		// sentences_wordsの再構築はジョブとして行う
+		updatedSentence.AssociationJobId, err = enqueueAssociationJob(su.jr, sentenceUpdate.LoginUserId, model.JobKindReassociateSentences, []uint64{sentenceUpdate.Id})
+		if err != nil {
+			return model.Sentence{}, err
+		}
+
+		return updatedSentence, nil
+	}
+
+	err = su.ReAssociateSentenceWithAllWords(sentenceUpdate.LoginUserId, sentenceUpdate.Id)
+	if err != nil {
+		return model.Sentence{}, err
+	}
 
 	return updatedSentence, nil
 }
@@ -98,8 +271,7 @@ func (su *SentenceUsecase) DeleteSentence(loginUserId uint64, sentenceId uint64)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが削除されなかった場合
-			// Sentenceのゼロ値を返す
-			return model.Sentence{}, nil
+			return model.Sentence{}, ErrSentenceNotFound
 		}
 
 		return model.Sentence{}, err
@@ -109,13 +281,13 @@ func (su *SentenceUsecase) DeleteSentence(loginUserId uint64, sentenceId uint64)
 }
 
 func (su *SentenceUsecase) GetAssociatedWordsBySentenceId(loginUserId uint64, sentenceId uint64) ([]model.Word, error) {
-	// sentenceIdの所有者がloginUserIdでない場合ゼロ値を返す
+	// sentenceIdの所有者がloginUserIdでない場合エラーを返す
 	isSentenceOwner, err := su.sr.IsSentenceOwner(sentenceId, loginUserId)
 	if err != nil {
 		return []model.Word{}, err
 	}
 	if !isSentenceOwner {
-		return []model.Word{}, nil
+		return []model.Word{}, ErrSentenceNotFound
 	}
 
 	words, err := su.swr.GetUserAssociatedWordsBySentenceId(sentenceId)
@@ -154,50 +326,63 @@ func (su *SentenceUsecase) AssociateSentenceWithAllWords(loginUserId , sentenceI
 		return []model.Word{}, nil
 	}
 
-	sentence, err := su.GetSentenceById(loginUserId, sentenceId)
+	sentence, err := su.sr.GetSentenceById(loginUserId, sentenceId)
 	if err != nil {
 		return []model.Word{}, err
 	}
 
-	userWords, err := su.wr.GetAllWords(loginUserId)
+	return su.associateSentencesWithAllWords(loginUserId, []model.Sentence{sentence})
+}
+
+func (su *SentenceUsecase) associateSentencesWithAllWords(loginUserId uint64, sentences []model.Sentence) ([]model.Word, error) {
+	// loginUserIdに紐づく全Wordに対し、
+	// sentencesの各Sentence中にWordまたはNotationが含まれればsentences_wordsにレコード追加
+	// sentencesはloginUserIdの所有するSentenceであることを前提とする
+	// Word、Notationの取得は、sentencesの件数によらず1回のみ行う
+
+	wf, err := newUserWordFinder(su.m, su.lr, su.wr, su.nr, su.dr, loginUserId)
+	if err != nil {
+		return []model.Word{}, err
+	}
+
+	// ユーザーが手動で指定した紐づけは、自動での紐づけより優先する
+	overridesBySentenceId, err := getOverridesBySentenceId(su.swr, sentences)
 	if err != nil {
 		return []model.Word{}, err
 	}
 
+	// 各Sentenceの探索は、Wordの件数によらず1回のみ行う
+	wordIdsBySentence := make([]map[uint64]bool, len(sentences))
+	var occurrences []model.SentenceWordOccurrence
+	for i, sentence := range sentences {
+		association := wf.associate(sentence, overridesBySentenceId[sentence.Id])
+		wordIdsBySentence[i] = association.wordIds
+		occurrences = append(occurrences, association.occurrences...)
+	}
+
 	var associatedWords []model.Word
-	for _, word := range userWords {
-		// Sentence中にWordが含まれる場合
-		// sentences_wordsにレコード追加
-		if strings.Contains(sentence.Sentence, word.Word) {
-			err = su.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
-			if err != nil {
-				return []model.Word{}, err
+	var sentencesWords []model.SentenceWord
+	for _, word := range wf.words {
+		for i, sentence := range sentences {
+			if !wordIdsBySentence[i][word.Id] {
+				continue
 			}
+
+			sentencesWords = append(sentencesWords, model.SentenceWord{SentenceId: sentence.Id, WordId: word.Id})
 			associatedWords = append(associatedWords, word)
-			// Sentenceの中にWordが含まれる場合、
-			// continueし、Notationが含まれるかの判定はしない
-			continue
 		}
+	}
 
-		// Sentence中にNotationが含まれる場合
-		// sentences_wordsにレコード追加
-		notations, err := su.nr.GetAllNotations(word.Id)
-		if err != nil {
-			return []model.Word{}, err
-		}
+	// 追加は紐づけの件数によらず1回のクエリで行う
+	err = su.swr.AssociateSentencesWithWords(sentencesWords)
+	if err != nil {
+		return []model.Word{}, err
+	}
 
-		for _, notation := range notations {
-			if strings.Contains(sentence.Sentence, notation.Notation) {
-				err = su.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
-				if err != nil {
-					return []model.Word{}, err
-				}
-				associatedWords = append(associatedWords, word)
-				// Sentenceの中にNotationが含まれる場合、
-				// sentences_wordsに2つ目のレコードが追加されないようbreak
-				break
-			}
-		}
+	// 出現箇所を記録し、リンクの作成時にSentenceを再度探索しなくて済むようにする
+	err = su.swr.InsertOccurrences(occurrences)
+	if err != nil {
+		return []model.Word{}, err
 	}
 
 	return associatedWords, nil
@@ -206,27 +391,187 @@ func (su *SentenceUsecase) AssociateSentenceWithAllWords(loginUserId , sentenceI
 func (su *SentenceUsecase) ReAssociateSentenceWithAllWords(loginUserId, sentenceId uint64) error {
 	// sentenceIdと全Wordのsentences_wordsを再構築
 	// sentences_wordsからsentenceIdのレコードを全削除し、もう一度追加しなおす
+	// 削除～再追加はトランザクション内で行う
+	return su.ReAssociateSentencesWithAllWords(loginUserId, []uint64{sentenceId})
+}
 
-	// sentenceIdの所有者がloginUserIdでない場合何もしない
+func (su *SentenceUsecase) ReAssociateSentencesWithAllWords(loginUserId uint64, sentenceIds []uint64) error {
+	// sentenceIdsで指定される各Sentenceと、全Wordのsentences_wordsを再構築
+	// 削除～再追加はトランザクション内で行う
+	return su.uow.Do(func(repos repository.Repositories) error {
+		return su.withRepositories(repos).reAssociateSentencesWithAllWords(loginUserId, sentenceIds)
+	})
+}
+
+func (su *SentenceUsecase) reAssociateSentencesWithAllWords(loginUserId uint64, sentenceIds []uint64) error {
+	// ジョブの実行時には既に削除されている場合があるため、存在しないSentenceは飛ばす
+	var sentences []model.Sentence
+	var existingSentenceIds []uint64
+	for _, sentenceId := range sentenceIds {
+		// sentenceIdの所有者がloginUserIdでない場合も、レコードが無いため飛ばす
+		sentence, err := su.sr.GetSentenceById(loginUserId, sentenceId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			return err
+		}
+
+		sentences = append(sentences, sentence)
+		existingSentenceIds = append(existingSentenceIds, sentence.Id)
+	}
+
+	if len(sentences) == 0 {
+		return nil
+	}
+
+	// sentences_wordsからsentenceIdsのレコードを全削除
+	err := su.swr.DeleteAllAssociationBySentenceIds(existingSentenceIds)
+	if err != nil {
+		return err
+	}
+
+	// sentences_wordsに再追加
+	_, err = su.associateSentencesWithAllWords(loginUserId, sentences)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (su *SentenceUsecase) reAssociateSentenceLater(loginUserId, sentenceId uint64) (uint64, error) {
+	// sentenceIdのsentences_wordsを再構築
+	// 非同期で行う場合はジョブを追加してそのIdを返し、リクエスト内で行う場合は0を返す
+	return su.reAssociateSentencesLater(loginUserId, []uint64{sentenceId})
+}
+
+func (su *SentenceUsecase) reAssociateSentencesLater(loginUserId uint64, sentenceIds []uint64) (uint64, error) {
+	// sentenceIdsの各Sentenceのsentences_wordsを、1つのジョブまたは1回の再構築でまとめて作りなおす
+	if su.mode == AssociationModeAsync {
+		return enqueueAssociationJob(su.jr, loginUserId, model.JobKindReassociateSentences, sentenceIds)
+	}
+
+	if len(sentenceIds) == 0 {
+		return 0, nil
+	}
+
+	return 0, su.ReAssociateSentencesWithAllWords(loginUserId, sentenceIds)
+}
+
+func (su *SentenceUsecase) checkSentenceAndWordOwner(loginUserId, sentenceId, wordId uint64) error {
+	// SentenceまたはWordの所有者がloginUserIdでない場合エラーを返す
 	isSentenceOwner, err := su.sr.IsSentenceOwner(sentenceId, loginUserId)
 	if err != nil {
 		return err
 	}
 	if !isSentenceOwner {
-		return nil
+		return ErrSentenceNotFound
 	}
 
-	// TODO: 削除～再追加はトランザクション内で行う
+	isWordOwner, err := su.wr.IsWordOwner(wordId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isWordOwner {
+		return ErrWordNotFound
+	}
 
-	// sentences_wordsからsentenceIdのレコードを全削除
-	err = su.swr.DeleteAllAssociationBySentenceId(sentenceId)
+	return nil
+}
 
-	// sentences_wordsに再追加
-	su.AssociateSentenceWithAllWords(loginUserId, sentenceId)
+func (su *SentenceUsecase) GetAssociationOverrides(loginUserId, sentenceId uint64) ([]model.AssociationOverride, error) {
+	// sentenceIdの所有者がloginUserIdでない場合エラーを返す
+	isSentenceOwner, err := su.sr.IsSentenceOwner(sentenceId, loginUserId)
+	if err != nil {
+		return []model.AssociationOverride{}, err
+	}
+	if !isSentenceOwner {
+		return []model.AssociationOverride{}, ErrSentenceNotFound
+	}
 
-	return nil
+	return su.swr.GetOverridesBySentenceIds([]uint64{sentenceId})
+}
+
+func (su *SentenceUsecase) SetAssociationOverride(overrideUpsert model.AssociationOverrideUpsert) (model.AssociationOverride, error) {
+	// 指定の保存と、Sentenceの紐づけの再構築をトランザクション内で実行
+	if overrideUpsert.Kind != model.AssociationOverrideKindPin && overrideUpsert.Kind != model.AssociationOverrideKindExclude {
+		return model.AssociationOverride{}, ErrInvalidAssociationOverrideKind
+	}
+
+	var override model.AssociationOverride
+	err := su.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		override, err = su.withRepositories(repos).setAssociationOverride(overrideUpsert)
+		return err
+	})
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
+}
+
+func (su *SentenceUsecase) setAssociationOverride(overrideUpsert model.AssociationOverrideUpsert) (model.AssociationOverride, error) {
+	err := su.checkSentenceAndWordOwner(overrideUpsert.LoginUserId, overrideUpsert.SentenceId, overrideUpsert.WordId)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	override, err := su.swr.UpsertOverride(overrideUpsert)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	override.AssociationJobId, err = su.reAssociateSentenceLater(overrideUpsert.LoginUserId, overrideUpsert.SentenceId)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
+}
+
+func (su *SentenceUsecase) DeleteAssociationOverride(loginUserId, sentenceId, wordId uint64) (model.AssociationOverride, error) {
+	// 指定の削除と、Sentenceの紐づけの再構築をトランザクション内で実行
+	var override model.AssociationOverride
+	err := su.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		override, err = su.withRepositories(repos).deleteAssociationOverride(loginUserId, sentenceId, wordId)
+		return err
+	})
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
+}
+
+func (su *SentenceUsecase) deleteAssociationOverride(loginUserId, sentenceId, wordId uint64) (model.AssociationOverride, error) {
+	err := su.checkSentenceAndWordOwner(loginUserId, sentenceId, wordId)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	override, err := su.swr.DeleteOverride(sentenceId, wordId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 指定が無かった場合
+			return model.AssociationOverride{}, ErrOverrideNotFound
+		}
+
+		return model.AssociationOverride{}, err
+	}
+
+	// 指定が無くなったため、自動での紐づけに戻す
+	override.AssociationJobId, err = su.reAssociateSentenceLater(loginUserId, sentenceId)
+	if err != nil {
+		return model.AssociationOverride{}, err
+	}
+
+	return override, nil
 }
 
-func (su *SentenceUsecase) GetSentencesCount(loginUserId uint64) (uint64, error) {
-	return su.sr.GetSentencesCount(loginUserId)
+func (su *SentenceUsecase) GetSentencesCount(loginUserId uint64, filter model.ListFilter) (uint64, error) {
+	return su.sr.GetSentencesCount(loginUserId, filter)
 }
\ No newline at end of file
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"database/sql"
+)
+
+type TagUsecase struct {
+	tr repository.ITagRepository
+	wr repository.IWordRepository
+	sr repository.ISentenceRepository
+}
+
+func NewTagUsecase(
+	tr repository.ITagRepository,
+	wr repository.IWordRepository,
+	sr repository.ISentenceRepository,
+) *TagUsecase {
+	return &TagUsecase{tr, wr, sr}
+}
+
+func (tu *TagUsecase) GetAllTags(loginUserId uint64) ([]model.Tag, error) {
+	return tu.tr.GetAllTags(loginUserId)
+}
+
+func (tu *TagUsecase) CreateTag(tagCreation model.TagCreation) (model.Tag, error) {
+	createdTag, err := tu.tr.InsertTag(tagCreation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 同じ名前のタグが既に存在する場合
+			return model.Tag{}, tu.newDuplicateTagError(tagCreation.LoginUserId, tagCreation.Name)
+		}
+
+		return model.Tag{}, err
+	}
+
+	return createdTag, nil
+}
+
+func (tu *TagUsecase) UpdateTag(tagUpdate model.TagUpdate) (model.Tag, error) {
+	// 同じUserの他のタグと同じ名前には更新できない
+	duplicateTag, err := tu.tr.GetTagByName(tagUpdate.LoginUserId, tagUpdate.Name)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Tag{}, err
+	}
+	if err == nil && duplicateTag.Id != tagUpdate.Id {
+		return model.Tag{}, NewDuplicateError("tag", model.DuplicateErrorDetails{ExistingId: duplicateTag.Id})
+	}
+
+	updatedTag, err := tu.tr.UpdateTag(tagUpdate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// レコードが更新されなかった場合
+			return model.Tag{}, ErrTagNotFound
+		}
+
+		return model.Tag{}, err
+	}
+
+	return updatedTag, nil
+}
+
+func (tu *TagUsecase) DeleteTag(loginUserId, tagId uint64) (model.Tag, error) {
+	// タグを付けていたWordとSentenceからは、タグのみ外れる
+	deletedTag, err := tu.tr.DeleteTagById(loginUserId, tagId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// レコードが削除されなかった場合
+			return model.Tag{}, ErrTagNotFound
+		}
+
+		return model.Tag{}, err
+	}
+
+	return deletedTag, nil
+}
+
+func (tu *TagUsecase) GetTagsByWordId(loginUserId, wordId uint64) ([]model.Tag, error) {
+	err := tu.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return []model.Tag{}, err
+	}
+
+	return tu.tr.GetTagsByWordId(wordId)
+}
+
+func (tu *TagUsecase) AddTagToWord(loginUserId, wordId, tagId uint64) error {
+	err := tu.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	err = tu.checkTagOwner(loginUserId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return tu.tr.AddTagToWord(wordId, tagId)
+}
+
+func (tu *TagUsecase) RemoveTagFromWord(loginUserId, wordId, tagId uint64) error {
+	err := tu.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return err
+	}
+
+	err = tu.checkTagOwner(loginUserId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return tu.tr.RemoveTagFromWord(wordId, tagId)
+}
+
+func (tu *TagUsecase) GetTagsBySentenceId(loginUserId, sentenceId uint64) ([]model.Tag, error) {
+	err := tu.checkSentenceOwner(loginUserId, sentenceId)
+	if err != nil {
+		return []model.Tag{}, err
+	}
+
+	return tu.tr.GetTagsBySentenceId(sentenceId)
+}
+
+func (tu *TagUsecase) AddTagToSentence(loginUserId, sentenceId, tagId uint64) error {
+	err := tu.checkSentenceOwner(loginUserId, sentenceId)
+	if err != nil {
+		return err
+	}
+
+	err = tu.checkTagOwner(loginUserId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return tu.tr.AddTagToSentence(sentenceId, tagId)
+}
+
+func (tu *TagUsecase) RemoveTagFromSentence(loginUserId, sentenceId, tagId uint64) error {
+	err := tu.checkSentenceOwner(loginUserId, sentenceId)
+	if err != nil {
+		return err
+	}
+
+	err = tu.checkTagOwner(loginUserId, tagId)
+	if err != nil {
+		return err
+	}
+
+	return tu.tr.RemoveTagFromSentence(sentenceId, tagId)
+}
+
+func (tu *TagUsecase) newDuplicateTagError(loginUserId uint64, name string) error {
+	existingTag, err := tu.tr.GetTagByName(loginUserId, name)
+	if err != nil {
+		return err
+	}
+
+	return NewDuplicateError("tag", model.DuplicateErrorDetails{ExistingId: existingTag.Id})
+}
+
+func (tu *TagUsecase) checkTagOwner(loginUserId, tagId uint64) error {
+	_, err := tu.tr.GetTagById(loginUserId, tagId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTagNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (tu *TagUsecase) checkWordOwner(loginUserId, wordId uint64) error {
+	isWordOwner, err := tu.wr.IsWordOwner(wordId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isWordOwner {
+		return ErrWordNotFound
+	}
+
+	return nil
+}
+
+func (tu *TagUsecase) checkSentenceOwner(loginUserId, sentenceId uint64) error {
+	isSentenceOwner, err := tu.sr.IsSentenceOwner(sentenceId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isSentenceOwner {
+		return ErrSentenceNotFound
+	}
+
+	return nil
+}
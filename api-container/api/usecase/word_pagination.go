@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"api/model"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// limitが指定されなかった場合の取得件数
+	defaultWordsLimit = 100
+	// 1回で取得できる最大件数
+	maxWordsLimit = 1000
+)
+
+var (
+	ErrInvalidWordSort = NewValidationError("sort must be word, created_at or updated_at", nil)
+	ErrInvalidOrder    = NewValidationError("order must be asc or desc", nil)
+	ErrInvalidCursor   = NewValidationError("cursor is invalid", nil)
+)
+
+func (wu *WordUsecase) GetWordsPage(wordListQuery model.WordListQuery) (model.WordPage, error) {
+	// wordListQueryで指定された1ページ分のWordと、次のページのカーソルを取得
+	sort := wordListQuery.Sort
+	if sort == "" {
+		sort = model.WordSortUpdatedAt
+	}
+	if sort != model.WordSortWord && sort != model.WordSortCreatedAt && sort != model.WordSortUpdatedAt {
+		return model.WordPage{}, ErrInvalidWordSort
+	}
+
+	order := wordListQuery.Order
+	if order == "" {
+		order = model.SortOrderDesc
+	}
+	if order != model.SortOrderAsc && order != model.SortOrderDesc {
+		return model.WordPage{}, ErrInvalidOrder
+	}
+
+	limit := wordListQuery.Limit
+	if limit == 0 {
+		limit = defaultWordsLimit
+	}
+	if limit > maxWordsLimit {
+		limit = maxWordsLimit
+	}
+
+	var cursor *model.WordCursor
+	if wordListQuery.Cursor != "" {
+		decodedCursor, err := decodeWordCursor(wordListQuery.Cursor)
+		if err != nil {
+			return model.WordPage{}, ErrInvalidCursor
+		}
+
+		// 前回と異なる並び順でカーソルを使用することはできない
+		if decodedCursor.Sort != sort || decodedCursor.Order != order {
+			return model.WordPage{}, ErrInvalidCursor
+		}
+
+		cursor = &decodedCursor
+	}
+
+	// 次のページが存在するかを判定するため、1件多く取得する
+	words, err := wu.wr.GetWordsAfterCursor(wordListQuery.LoginUserId, sort, order, cursor, wordListQuery.Filter, limit+1)
+	if err != nil {
+		return model.WordPage{}, err
+	}
+
+	nextCursor := ""
+	if uint64(len(words)) > limit {
+		words = words[:limit]
+
+		nextCursor, err = encodeWordCursor(sort, order, words[len(words)-1])
+		if err != nil {
+			return model.WordPage{}, err
+		}
+	}
+
+	// 各Wordの意味は、Wordの件数によらず1回のクエリで取得する
+	var wordIds []uint64
+	for _, word := range words {
+		wordIds = append(wordIds, word.Id)
+	}
+	senses, err := wu.wsr.GetSensesByWordIds(wordIds)
+	if err != nil {
+		return model.WordPage{}, err
+	}
+
+	sensesByWordId := map[uint64][]model.WordSense{}
+	for _, sense := range senses {
+		sensesByWordId[sense.WordId] = append(sensesByWordId[sense.WordId], sense)
+	}
+	for i := range words {
+		words[i].Senses = sensesByWordId[words[i].Id]
+	}
+
+	// 絞り込んだ場合は、絞り込んだ後の件数を返す
+	totalCount, err := wu.wr.GetWordsCount(wordListQuery.LoginUserId, wordListQuery.Filter)
+	if err != nil {
+		return model.WordPage{}, err
+	}
+
+	wordPage := model.WordPage{
+		Words:      words,
+		NextCursor: nextCursor,
+		TotalCount: totalCount,
+	}
+
+	return wordPage, nil
+}
+
+func encodeWordCursor(sort, order string, word model.Word) (string, error) {
+	// wordの位置を表すカーソルを、URLにそのまま含められる文字列に変換
+	var value string
+	switch sort {
+	case model.WordSortWord:
+		value = word.Word
+	case model.WordSortCreatedAt:
+		value = word.CreatedAt.Format(time.RFC3339Nano)
+	case model.WordSortUpdatedAt:
+		value = word.UpdatedAt.Format(time.RFC3339Nano)
+	}
+
+	cursor := model.WordCursor{
+		Sort:  sort,
+		Order: order,
+		Value: value,
+		Id:    word.Id,
+	}
+
+	cursorJson, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cursorJson), nil
+}
+
+func decodeWordCursor(encodedCursor string) (model.WordCursor, error) {
+	cursorJson, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return model.WordCursor{}, err
+	}
+
+	var cursor model.WordCursor
+	err = json.Unmarshal(cursorJson, &cursor)
+	if err != nil {
+		return model.WordCursor{}, err
+	}
+
+	// 日時の列の場合、値が日時として解釈できなければ不正なカーソルとする
+	if cursor.Sort != model.WordSortWord {
+		_, err = time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return model.WordCursor{}, err
+		}
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"database/sql"
+)
+
+var ErrWordRelationToItself = NewValidationError("related_word_id must be different from the word", nil)
+
+type WordRelationUsecase struct {
+	wrr repository.IWordRelationRepository
+	wr  repository.IWordRepository
+	nr  repository.INotationRepository
+	// compoundの提案に使用する
+	// WORD_MATCHERの設定によらず、部分文字列として含まれるかで判定する
+	m IMatcher
+}
+
+func NewWordRelationUsecase(
+	wrr repository.IWordRelationRepository,
+	wr repository.IWordRepository,
+	nr repository.INotationRepository,
+) *WordRelationUsecase {
+	return &WordRelationUsecase{wrr, wr, nr, NewAhoCorasickMatcher()}
+}
+
+func (wru *WordRelationUsecase) GetRelations(loginUserId, wordId uint64) ([]model.WordRelation, error) {
+	err := wru.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return []model.WordRelation{}, err
+	}
+
+	return wru.wrr.GetRelationsByWordId(wordId)
+}
+
+func (wru *WordRelationUsecase) CreateRelation(relationCreation model.WordRelationCreation) (model.WordRelation, error) {
+	if relationCreation.WordId == relationCreation.RelatedWordId {
+		return model.WordRelation{}, ErrWordRelationToItself
+	}
+
+	// 関係するWordも、loginUserIdのWordに限る
+	err := wru.checkWordOwner(relationCreation.LoginUserId, relationCreation.WordId)
+	if err != nil {
+		return model.WordRelation{}, err
+	}
+
+	err = wru.checkWordOwner(relationCreation.LoginUserId, relationCreation.RelatedWordId)
+	if err != nil {
+		return model.WordRelation{}, err
+	}
+
+	if relationCreation.Bidirectional == nil {
+		bidirectional := model.IsBidirectionalByDefault(relationCreation.Kind)
+		relationCreation.Bidirectional = &bidirectional
+	}
+
+	createdRelation, err := wru.wrr.InsertRelation(relationCreation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 2つのWordの間に、同じ種類の関係が既に存在する場合
+			return model.WordRelation{}, wru.newDuplicateRelationError(relationCreation.WordId, relationCreation.RelatedWordId, relationCreation.Kind)
+		}
+
+		return model.WordRelation{}, err
+	}
+
+	return createdRelation, nil
+}
+
+func (wru *WordRelationUsecase) UpdateRelation(relationUpdate model.WordRelationUpdate) (model.WordRelation, error) {
+	err := wru.checkWordOwner(relationUpdate.LoginUserId, relationUpdate.WordId)
+	if err != nil {
+		return model.WordRelation{}, err
+	}
+
+	relation, err := wru.wrr.GetRelationById(relationUpdate.WordId, relationUpdate.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 関係がWordのものでない場合
+			return model.WordRelation{}, ErrWordRelationNotFound
+		}
+
+		return model.WordRelation{}, err
+	}
+
+	// 同じ2つのWordの間の、他の関係と同じ種類には更新できない
+	duplicateRelation, err := wru.wrr.GetRelationBetween(relation.WordId, relation.RelatedWordId, relationUpdate.Kind)
+	if err != nil && err != sql.ErrNoRows {
+		return model.WordRelation{}, err
+	}
+	if err == nil && duplicateRelation.Id != relation.Id {
+		return model.WordRelation{}, NewDuplicateError("word relation", model.DuplicateErrorDetails{ExistingId: duplicateRelation.Id})
+	}
+
+	updatedRelation, err := wru.wrr.UpdateRelation(relationUpdate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.WordRelation{}, ErrWordRelationNotFound
+		}
+
+		return model.WordRelation{}, err
+	}
+
+	return updatedRelation, nil
+}
+
+func (wru *WordRelationUsecase) DeleteRelation(loginUserId, wordId, relationId uint64) (model.WordRelation, error) {
+	err := wru.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return model.WordRelation{}, err
+	}
+
+	deletedRelation, err := wru.wrr.DeleteRelationById(wordId, relationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.WordRelation{}, ErrWordRelationNotFound
+		}
+
+		return model.WordRelation{}, err
+	}
+
+	return deletedRelation, nil
+}
+
+func (wru *WordRelationUsecase) SuggestCompounds(loginUserId, wordId uint64) ([]model.WordRelationSuggestion, error) {
+	// wordIdのWordと、loginUserIdの他のWordとの間で、compoundの関係を提案する
+	// 他のWordまたはそのNotationがwordIdのWordに含まれる場合と、
+	// wordIdのWordまたはそのNotationが他のWordに含まれる場合を対象とする
+	// 既にcompoundの関係があるWordは提案しない
+	word, err := wru.wr.GetWordById(loginUserId, wordId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []model.WordRelationSuggestion{}, ErrWordNotFound
+		}
+
+		return []model.WordRelationSuggestion{}, err
+	}
+
+	relations, err := wru.wrr.GetRelationsByWordId(word.Id)
+	if err != nil {
+		return []model.WordRelationSuggestion{}, err
+	}
+
+	relatedWordIds := map[uint64]bool{word.Id: true}
+	for _, relation := range relations {
+		if relation.Kind != model.WordRelationKindCompound {
+			continue
+		}
+		relatedWordIds[relation.WordId] = true
+		relatedWordIds[relation.RelatedWordId] = true
+	}
+
+	// WordとNotationの取得は、Wordの件数によらずそれぞれ1回のクエリで行う
+	userWords, err := wru.wr.GetAllWords(loginUserId)
+	if err != nil {
+		return []model.WordRelationSuggestion{}, err
+	}
+
+	notations, err := wru.nr.GetAllNotationsByUserId(loginUserId)
+	if err != nil {
+		return []model.WordRelationSuggestion{}, err
+	}
+
+	notationsByWordId := map[uint64][]model.Notation{}
+	for _, notation := range notations {
+		notationsByWordId[notation.WordId] = append(notationsByWordId[notation.WordId], notation)
+	}
+
+	var otherWords []model.Word
+	for _, userWord := range userWords {
+		if relatedWordIds[userWord.Id] {
+			continue
+		}
+		otherWords = append(otherWords, userWord)
+	}
+
+	// 紐づけと同じくwordFinderで探索するが、出現箇所の重なりは解決せず全て提案する
+	suggestions := []model.WordRelationSuggestion{}
+
+	// wordIdのWordを複合語とし、含まれる他のWordを提案
+	componentFinder := newWordFinder(wru.m, nil, otherWords, notationsByWordId)
+	suggestions = append(suggestions, componentFinder.findCompoundSuggestions(word)...)
+
+	// wordIdのWordを含む他のWordを、複合語として提案
+	compoundFinder := newWordFinder(wru.m, nil, []model.Word{word}, notationsByWordId)
+	for _, otherWord := range otherWords {
+		suggestions = append(suggestions, compoundFinder.findCompoundSuggestions(otherWord)...)
+	}
+
+	return suggestions, nil
+}
+
+func (wf *wordFinder) findCompoundSuggestions(compound model.Word) []model.WordRelationSuggestion {
+	// compoundのWord中に出現するWordを、compoundのWordに含まれるWordとして提案する
+	// Word全体に一致する出現は、同じ語の別の表記とみなして提案しない
+	// 1つのWordにつき、最初の出現のみ提案する
+	runes := []rune(compound.Word)
+
+	var suggestions []model.WordRelationSuggestion
+	suggestedWordIds := map[uint64]bool{}
+	for _, match := range wf.pm.FindAll(compound.Word) {
+		if match.Start == 0 && match.End == len(runes) {
+			continue
+		}
+
+		component := wf.words[wf.wordIndexes[match.TermIndex]]
+		if component.Id == compound.Id || suggestedWordIds[component.Id] {
+			continue
+		}
+		suggestedWordIds[component.Id] = true
+
+		suggestions = append(suggestions, model.WordRelationSuggestion{
+			WordId:        compound.Id,
+			RelatedWordId: component.Id,
+			Kind:          model.WordRelationKindCompound,
+			MatchedText:   string(runes[match.Start:match.End]),
+			NotationId:    wf.notationIds[match.TermIndex],
+		})
+	}
+
+	return suggestions
+}
+
+func (wru *WordRelationUsecase) newDuplicateRelationError(wordId, relatedWordId uint64, kind string) error {
+	existingRelation, err := wru.wrr.GetRelationBetween(wordId, relatedWordId, kind)
+	if err != nil {
+		return err
+	}
+
+	return NewDuplicateError("word relation", model.DuplicateErrorDetails{ExistingId: existingRelation.Id})
+}
+
+func (wru *WordRelationUsecase) checkWordOwner(loginUserId, wordId uint64) error {
+	isWordOwner, err := wru.wr.IsWordOwner(wordId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isWordOwner {
+		return ErrWordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"api/model"
+	"api/repository"
+	"database/sql"
+)
+
+var ErrSentenceNotAssociatedWithWord = NewValidationError("sentence is not associated with the word", nil)
+
+type WordSenseUsecase struct {
+	wsr repository.IWordSenseRepository
+	wr  repository.IWordRepository
+	sr  repository.ISentenceRepository
+}
+
+func NewWordSenseUsecase(
+	wsr repository.IWordSenseRepository,
+	wr repository.IWordRepository,
+	sr repository.ISentenceRepository,
+) *WordSenseUsecase {
+	return &WordSenseUsecase{wsr, wr, sr}
+}
+
+func (wsu *WordSenseUsecase) GetSenses(loginUserId, wordId uint64) ([]model.WordSense, error) {
+	err := wsu.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return []model.WordSense{}, err
+	}
+
+	return wsu.wsr.GetSensesByWordId(wordId)
+}
+
+func (wsu *WordSenseUsecase) CreateSense(senseCreation model.WordSenseCreation) (model.WordSense, error) {
+	err := wsu.checkWordOwner(senseCreation.LoginUserId, senseCreation.WordId)
+	if err != nil {
+		return model.WordSense{}, err
+	}
+
+	return wsu.wsr.InsertSense(senseCreation)
+}
+
+func (wsu *WordSenseUsecase) UpdateSense(senseUpdate model.WordSenseUpdate) (model.WordSense, error) {
+	err := wsu.checkWordOwner(senseUpdate.LoginUserId, senseUpdate.WordId)
+	if err != nil {
+		return model.WordSense{}, err
+	}
+
+	updatedSense, err := wsu.wsr.UpdateSense(senseUpdate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 意味がWordのものでない場合
+			return model.WordSense{}, ErrWordSenseNotFound
+		}
+
+		return model.WordSense{}, err
+	}
+
+	return updatedSense, nil
+}
+
+func (wsu *WordSenseUsecase) DeleteSense(loginUserId, wordId, senseId uint64) (model.WordSense, error) {
+	// 意味を指定していたSentenceからは、指定のみ外れる
+	err := wsu.checkWordOwner(loginUserId, wordId)
+	if err != nil {
+		return model.WordSense{}, err
+	}
+
+	deletedSense, err := wsu.wsr.DeleteSenseById(wordId, senseId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.WordSense{}, ErrWordSenseNotFound
+		}
+
+		return model.WordSense{}, err
+	}
+
+	return deletedSense, nil
+}
+
+func (wsu *WordSenseUsecase) GetSentenceSenses(loginUserId, sentenceId uint64) ([]model.SentenceWordSense, error) {
+	isSentenceOwner, err := wsu.sr.IsSentenceOwner(sentenceId, loginUserId)
+	if err != nil {
+		return []model.SentenceWordSense{}, err
+	}
+	if !isSentenceOwner {
+		return []model.SentenceWordSense{}, ErrSentenceNotFound
+	}
+
+	return wsu.wsr.GetSentenceSensesBySentenceId(sentenceId)
+}
+
+func (wsu *WordSenseUsecase) SetSentenceSense(sentenceSenseUpsert model.SentenceWordSenseUpsert) (model.SentenceWordSense, error) {
+	// Sentenceと紐づいているWordについてのみ、Sentenceが例文となる意味を指定できる
+	err := wsu.checkSentenceAndWordOwner(sentenceSenseUpsert.LoginUserId, sentenceSenseUpsert.SentenceId, sentenceSenseUpsert.WordId)
+	if err != nil {
+		return model.SentenceWordSense{}, err
+	}
+
+	_, err = wsu.wsr.GetSenseById(sentenceSenseUpsert.WordId, sentenceSenseUpsert.SenseId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 他のWordの意味は指定できない
+			return model.SentenceWordSense{}, ErrWordSenseNotFound
+		}
+
+		return model.SentenceWordSense{}, err
+	}
+
+	sentenceSense, err := wsu.wsr.UpsertSentenceSense(sentenceSenseUpsert)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.SentenceWordSense{}, ErrSentenceNotAssociatedWithWord
+		}
+
+		return model.SentenceWordSense{}, err
+	}
+
+	return sentenceSense, nil
+}
+
+func (wsu *WordSenseUsecase) DeleteSentenceSense(loginUserId, sentenceId, wordId uint64) (model.SentenceWordSense, error) {
+	err := wsu.checkSentenceAndWordOwner(loginUserId, sentenceId, wordId)
+	if err != nil {
+		return model.SentenceWordSense{}, err
+	}
+
+	sentenceSense, err := wsu.wsr.DeleteSentenceSense(sentenceId, wordId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// 指定が無かった場合
+			return model.SentenceWordSense{}, ErrSentenceWordSenseNotFound
+		}
+
+		return model.SentenceWordSense{}, err
+	}
+
+	return sentenceSense, nil
+}
+
+func (wsu *WordSenseUsecase) checkWordOwner(loginUserId, wordId uint64) error {
+	isWordOwner, err := wsu.wr.IsWordOwner(wordId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isWordOwner {
+		return ErrWordNotFound
+	}
+
+	return nil
+}
+
+func (wsu *WordSenseUsecase) checkSentenceAndWordOwner(loginUserId, sentenceId, wordId uint64) error {
+	isSentenceOwner, err := wsu.sr.IsSentenceOwner(sentenceId, loginUserId)
+	if err != nil {
+		return err
+	}
+	if !isSentenceOwner {
+		return ErrSentenceNotFound
+	}
+
+	return wsu.checkWordOwner(loginUserId, wordId)
+}
@@ -4,7 +4,8 @@ import (
 	"api/model"
 	"api/repository"
 	"database/sql"
-	"strings"
+	"fmt"
+	"unicode/utf8"
 )
 
 type WordUsecase struct {
@@ -12,6 +13,16 @@ type WordUsecase struct {
 	sr  repository.ISentenceRepository
 	swr repository.ISentencesWordsRepository
 	nr  repository.INotationRepository
+	tr  repository.ITagRepository
+	dr  repository.IDeckRepository
+	wsr repository.IWordSenseRepository
+	wrr repository.IWordRelationRepository
+	jr  repository.IJobRepository
+	uow repository.IUnitOfWork
+	m   IMatcher
+	lr  *LinkResolver
+	// Sentenceとの紐づけの再構築を、リクエスト内で行うかジョブとして行うか
+	mode AssociationMode
 }
 
 func NewWordUsecase(
@@ -19,8 +30,37 @@ func NewWordUsecase(
 	sr repository.ISentenceRepository,
 	swr repository.ISentencesWordsRepository,
 	nr repository.INotationRepository,
+	tr repository.ITagRepository,
+	dr repository.IDeckRepository,
+	wsr repository.IWordSenseRepository,
+	wrr repository.IWordRelationRepository,
+	jr repository.IJobRepository,
+	uow repository.IUnitOfWork,
+	m IMatcher,
+	lr *LinkResolver,
+	mode AssociationMode,
 ) *WordUsecase {
-	return &WordUsecase{wr, sr, swr, nr}
+	return &WordUsecase{wr, sr, swr, nr, tr, dr, wsr, wrr, jr, uow, m, lr, mode}
+}
+
+func (wu *WordUsecase) withRepositories(repos repository.Repositories) *WordUsecase {
+	// トランザクション内のreposを使うWordUsecaseを作成
+	// 作成したWordUsecase内でのUnitOfWork.Doは、同じトランザクション内で実行される
+	return NewWordUsecase(
+		repos.Word,
+		repos.Sentence,
+		repos.SentencesWords,
+		repos.Notation,
+		repos.Tag,
+		repos.Deck,
+		repos.WordSense,
+		repos.WordRelation,
+		repos.Job,
+		repository.NewTransactionalUnitOfWork(repos),
+		wu.m,
+		wu.lr,
+		wu.mode,
+	)
 }
 
 func (wu *WordUsecase) GetAllWords(loginUserId uint64) ([]model.Word, error) {
@@ -37,56 +77,282 @@ func (wu *WordUsecase) GetWordById(loginUserId, wordId uint64) (model.Word, erro
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// マッチするレコードが無い場合
-			// Wordのゼロ値を返す
-			return model.Word{}, nil
+			return model.Word{}, ErrWordNotFound
 		}
 		return model.Word{}, err
 	}
 
+	word.Senses, err = wu.wsr.GetSensesByWordId(word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	if wu.mode == AssociationModeAsync {
+		// 紐づけの再構築が完了していない場合、そのジョブのIdを返す
+		word.AssociationJobId, err = getUnfinishedAssociationJobId(wu.jr, loginUserId, model.JobKindReassociateWords, word.Id)
+		if err != nil {
+			return model.Word{}, err
+		}
+	}
+
 	return word, nil
 }
 
 func (wu *WordUsecase) CreateWord(wordCreation model.WordCreation) (model.Word, error) {
+	var createdWord model.Word
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		createdWord, err = wu.withRepositories(repos).createWord(wordCreation)
+		return err
+	})
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	return createdWord, nil
+}
+
+func (wu *WordUsecase) createWord(wordCreation model.WordCreation) (model.Word, error) {
 	loginUserId := wordCreation.LoginUserId
 
 	createdWord, err := wu.wr.InsertWord(wordCreation)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			// 同じWordが既に存在し、追加されなかった場合
+			return wu.resolveDuplicateWord(wordCreation, "")
+		}
+
 		return model.Word{}, err
 	}
 
-	// 語幹をnotationに追加
-	wu.createRootNotation(loginUserId, createdWord)
+	// 活用形をnotationに追加
+	err = wu.createConjugationNotations(createdWord)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	if wu.mode == AssociationModeAsync {
+		// sentences_wordsへの追加はジョブとして行う
+		createdWord.AssociationJobId, err = enqueueAssociationJob(wu.jr, loginUserId, model.JobKindReassociateWords, []uint64{createdWord.Id})
+		if err != nil {
+			return model.Word{}, err
+		}
+
+		return createdWord, nil
+	}
 
 	// 既存のSentence中に追加したWordを含むものがあれば、sentences_wordsに追加
-	wu.AssociateWordWithAllSentences(loginUserId, createdWord.Id)
+	_, err = wu.AssociateWordWithAllSentences(loginUserId, createdWord.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
 
 	return createdWord, nil
 }
 
 func (wu *WordUsecase) CreateMultipleWords(wordCreations []model.WordCreation) ([]model.Word, error) {
-	// TODO 1件でも失敗したらロールバックする実装に変更
+	// 各項目はリクエストの検証で確認済みとする
+	// 1件でも失敗した場合は全件ロールバックする
+	var createdWords []model.Word
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		createdWords, err = wu.withRepositories(repos).createMultipleWords(wordCreations)
+		return err
+	})
+	if err != nil {
+		return []model.Word{}, err
+	}
+
+	return createdWords, nil
+}
+
+func (wu *WordUsecase) createMultipleWords(wordCreations []model.WordCreation) ([]model.Word, error) {
 	var createdWords []model.Word
-	for _, wordCreation := range wordCreations {
-		createdWord, err := wu.CreateWord(wordCreation)
+	wordsByUserId := map[uint64][]model.Word{}
+	var userIds []uint64
+	for i, wordCreation := range wordCreations {
+		createdWord, err := wu.wr.InsertWord(wordCreation)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return []model.Word{}, err
+			}
+
+			// 同じWordが既に存在し、追加されなかった場合
+			// 既存のWordはSentenceとの紐づけが済んでいるため、紐づけの対象としない
+			existingWord, err := wu.resolveDuplicateWord(wordCreation, fmt.Sprintf("words[%d].word", i))
+			if err != nil {
+				return []model.Word{}, err
+			}
+
+			createdWords = append(createdWords, existingWord)
+			continue
+		}
+
+		// 活用形をnotationに追加
+		err = wu.createConjugationNotations(createdWord)
 		if err != nil {
 			return []model.Word{}, err
 		}
 
 		createdWords = append(createdWords, createdWord)
+
+		userId := wordCreation.LoginUserId
+		if _, ok := wordsByUserId[userId]; !ok {
+			userIds = append(userIds, userId)
+		}
+		wordsByUserId[userId] = append(wordsByUserId[userId], createdWord)
+	}
+
+	if wu.mode == AssociationModeAsync {
+		// ユーザーごとに、追加した全Wordに対するジョブを1つ追加する
+		jobIdByUserId := map[uint64]uint64{}
+		for _, userId := range userIds {
+			var wordIds []uint64
+			for _, word := range wordsByUserId[userId] {
+				wordIds = append(wordIds, word.Id)
+			}
+
+			jobId, err := enqueueAssociationJob(wu.jr, userId, model.JobKindReassociateWords, wordIds)
+			if err != nil {
+				return []model.Word{}, err
+			}
+			jobIdByUserId[userId] = jobId
+		}
+
+		// 既存のWordはジョブの対象としないため、追加したWordにのみジョブのIdを設定する
+		for i := range createdWords {
+			if createdWords[i].AlreadyExisted {
+				continue
+			}
+			createdWords[i].AssociationJobId = jobIdByUserId[createdWords[i].UserId]
+		}
+
+		return createdWords, nil
+	}
+
+	// 1件ずつではなく、追加した全Wordに対してまとめてsentences_wordsへの追加を行う
+	for _, userId := range userIds {
+		_, err := wu.associateWordsWithAllSentences(userId, wordsByUserId[userId])
+		if err != nil {
+			return []model.Word{}, err
+		}
 	}
 
 	return createdWords, nil
 }
 
+func (wu *WordUsecase) resolveDuplicateWord(wordCreation model.WordCreation, field string) (model.Word, error) {
+	// 既に存在するWordを、wordCreation.OnDuplicateに従って扱う
+	// fieldは、複数件の追加で重複した項目をエラーで返すために使用する
+	existingWord, err := wu.wr.GetWordByWord(wordCreation.LoginUserId, wordCreation.Word)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	switch wordCreation.OnDuplicate {
+	case model.OnDuplicateSkip:
+	case model.OnDuplicateUpsert:
+		// 読み、品詞、アクセントは、リクエストで指定された場合のみ更新し、省略された場合は既存の値を残す
+		wordUpdate := model.WordUpdate{
+			Id:           existingWord.Id,
+			Word:         existingWord.Word,
+			Memo:         wordCreation.Memo,
+			Reading:      existingWord.Reading,
+			PartOfSpeech: existingWord.PartOfSpeech,
+			PitchAccent:  existingWord.PitchAccent,
+			LoginUserId:  wordCreation.LoginUserId,
+		}
+		if wordCreation.Reading != "" {
+			wordUpdate.Reading = wordCreation.Reading
+		}
+		if wordCreation.PartOfSpeech != "" {
+			wordUpdate.PartOfSpeech = wordCreation.PartOfSpeech
+		}
+		if wordCreation.PitchAccent != nil {
+			wordUpdate.PitchAccent = wordCreation.PitchAccent
+		}
+
+		if existingWord.PartOfSpeech != wordUpdate.PartOfSpeech {
+			// 品詞が変わると活用形のNotationとsentences_wordsも変わるため、Wordの更新と同様に作りなおす
+			existingWord, err = wu.updateWord(wordUpdate)
+		} else {
+			// 語と品詞が同じため、活用形のNotationとsentences_wordsは変わらない
+			existingWord, err = wu.wr.UpdateWord(wordUpdate)
+		}
+		if err != nil {
+			return model.Word{}, err
+		}
+	default:
+		return model.Word{}, NewDuplicateError("word", model.DuplicateErrorDetails{
+			ExistingId: existingWord.Id,
+			Field:      field,
+		})
+	}
+
+	existingWord.AlreadyExisted = true
+
+	return existingWord, nil
+}
+
+func (wu *WordUsecase) newDuplicateNotationError(wordId uint64, notation string) error {
+	existingNotation, err := wu.nr.GetNotationByNotation(wordId, notation)
+	if err != nil {
+		return err
+	}
+
+	return NewDuplicateError("notation", model.DuplicateErrorDetails{ExistingId: existingNotation.Id})
+}
+
 func (wu *WordUsecase) DeleteWord(loginUserId, wordId uint64) (model.Word, error) {
+	// Word削除と、削除したWordが紐づいていたSentenceのsentences_wordsの再構築をトランザクション内で実行
+	var deletedWord model.Word
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		deletedWord, err = wu.withRepositories(repos).deleteWord(loginUserId, wordId)
+		return err
+	})
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	return deletedWord, nil
+}
+
+func (wu *WordUsecase) deleteWord(loginUserId, wordId uint64) (model.Word, error) {
+	// 削除したWordと重なり合っていた他のWordがリンクとなる場合があるため、
+	// 削除前に紐づいていたSentenceを取得しておく
+	previousSentences, err := wu.swr.GetUserAssociatedSentencesByWordId(wordId)
+	if err != nil {
+		return model.Word{}, err
+	}
+
 	deletedWord, err := wu.wr.DeleteWordById(loginUserId, wordId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが削除されなかった場合
-			// Wordのゼロ値を返す
-			return model.Word{}, nil
+			return model.Word{}, ErrWordNotFound
+		}
+
+		return model.Word{}, err
+	}
+
+	if wu.mode == AssociationModeAsync {
+		// 紐づいていたSentenceのsentences_wordsの再構築はジョブとして行う
+		var previousSentenceIds []uint64
+		for _, sentence := range previousSentences {
+			previousSentenceIds = append(previousSentenceIds, sentence.Id)
+		}
+
+		_, err = enqueueAssociationJob(wu.jr, loginUserId, model.JobKindReassociateSentences, previousSentenceIds)
+		if err != nil {
+			return model.Word{}, err
 		}
 
+		return deletedWord, nil
+	}
+
+	_, err = wu.resolveSentencesWords(loginUserId, previousSentences, nil)
+	if err != nil {
 		return model.Word{}, err
 	}
 
@@ -94,15 +360,41 @@ func (wu *WordUsecase) DeleteWord(loginUserId, wordId uint64) (model.Word, error
 }
 
 func (wu *WordUsecase) UpdateWord(wordUpdate model.WordUpdate) (model.Word, error) {
-	// TODO: 語幹Notation削除、Word更新、語幹Notation追加、まではトランザクション内で実行
-	
-	// Word更新前に更新前のWordの語幹のNotationを削除
-	wordBeforeUpdate, err := wu.GetWordById(wordUpdate.LoginUserId, wordUpdate.Id)
+	// 自動生成したNotation削除、Word更新、活用形Notation追加、sentences_wordsの再構築までをトランザクション内で実行
+	var updatedWord model.Word
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		updatedWord, err = wu.withRepositories(repos).updateWord(wordUpdate)
+		return err
+	})
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	return updatedWord, nil
+}
+
+func (wu *WordUsecase) updateWord(wordUpdate model.WordUpdate) (model.Word, error) {
+	// Word更新前に、更新前のWordから自動生成したNotationを削除
+	// ユーザーが入力したNotationは残す
+	isWordOwner, err := wu.wr.IsWordOwner(wordUpdate.Id, wordUpdate.LoginUserId)
 	if err != nil {
 		return model.Word{}, err
 	}
+	if !isWordOwner {
+		return model.Word{}, ErrWordNotFound
+	}
 
-	err = wu.deleteRootNotation(wordUpdate.LoginUserId, wordBeforeUpdate)
+	// 同じUserの他のWordと同じ語には更新できない
+	duplicateWord, err := wu.wr.GetWordByWord(wordUpdate.LoginUserId, wordUpdate.Word)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Word{}, err
+	}
+	if err == nil && duplicateWord.Id != wordUpdate.Id {
+		return model.Word{}, NewDuplicateError("word", model.DuplicateErrorDetails{ExistingId: duplicateWord.Id})
+	}
+
+	err = wu.nr.DeleteGeneratedNotations(wordUpdate.Id)
 	if err != nil {
 		return model.Word{}, err
 	}
@@ -111,32 +403,185 @@ func (wu *WordUsecase) UpdateWord(wordUpdate model.WordUpdate) (model.Word, erro
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが更新されなかった場合
-			// Wordのゼロ値を返す
-			return model.Word{}, nil
+			return model.Word{}, ErrWordNotFound
 		}
 
 		return model.Word{}, err
 	}
 
-	// 更新後の語幹のNotationを追加
-	err = wu.createRootNotation(wordUpdate.LoginUserId, updatedWord)
+	// 更新後の活用形のNotationを追加
+	err = wu.createConjugationNotations(updatedWord)
 	if err != nil {
 		return model.Word{}, err
 	}
 
-	wu.ReAssociateWordWithAllSentences(wordUpdate.LoginUserId, wordUpdate.Id)
+	updatedWord.AssociationJobId, err = wu.reAssociateWordLater(wordUpdate.LoginUserId, wordUpdate.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
 
 	return updatedWord, nil
 }
 
+// DBのmemoのカラム長
+const maxWordMemoLength = 500
+
+func (wu *WordUsecase) MergeWords(wordMerge model.WordMerge) (model.Word, error) {
+	// Notationの移動、メモの統合、統合元のWord削除、sentences_wordsの再構築までをトランザクション内で実行
+	var mergedWord model.Word
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		mergedWord, err = wu.withRepositories(repos).mergeWords(wordMerge)
+		return err
+	})
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	return mergedWord, nil
+}
+
+func (wu *WordUsecase) mergeWords(wordMerge model.WordMerge) (model.Word, error) {
+	// 統合元のWordを、統合先のWordのNotationとして残す
+	// 統合元のWordの復習の状態は引き継がない
+	loginUserId := wordMerge.LoginUserId
+
+	if wordMerge.Id == wordMerge.FromWordId {
+		return model.Word{}, NewValidationError("from_word_id must be different from the word to merge into", nil)
+	}
+
+	// どちらかのWordの所有者がloginUserIdでない場合も、レコードが無いためエラーを返す
+	word, err := wu.wr.GetWordById(loginUserId, wordMerge.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Word{}, ErrWordNotFound
+		}
+
+		return model.Word{}, err
+	}
+
+	fromWord, err := wu.wr.GetWordById(loginUserId, wordMerge.FromWordId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Word{}, ErrWordNotFound
+		}
+
+		return model.Word{}, err
+	}
+
+	memo := mergeMemos(word.Memo, fromWord.Memo, wordMerge.MemoStrategy)
+	if utf8.RuneCountInString(memo) > maxWordMemoLength {
+		return model.Word{}, NewValidationError(fmt.Sprintf("merged memo must be at most %d characters", maxWordMemoLength), nil)
+	}
+
+	err = wu.nr.MoveNotations(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordにだけ含まれていたSentenceとの紐づけを保つため、統合元のWordをNotationとして追加
+	notationCreation := model.NotationCreation{
+		WordId:      word.Id,
+		Notation:    fromWord.Word,
+		Kind:        model.NotationKindManual,
+		LoginUserId: loginUserId,
+	}
+	_, err = wu.nr.InsertNotation(notationCreation)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordに、統合先のWordと同じNotationがあった場合は不要なため削除
+	_, err = wu.nr.DeleteNotationIfExists(word.Id, word.Word)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	err = wu.swr.MoveOverrides(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordの意味は、統合先のWordの意味の後ろに並べる
+	err = wu.wsr.MoveSenses(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordの関係は、統合先のWordの関係として残す
+	err = wu.wrr.MoveRelations(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordに付けられたTagと、統合元のWordを含むDeckは、統合先のWordに引き継ぐ
+	err = wu.tr.MoveTags(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	err = wu.dr.MoveDeckWords(fromWord.Id, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordのsentences_wordsなどは外部キーにより削除される
+	_, err = wu.wr.DeleteWordById(loginUserId, fromWord.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 読み、品詞、アクセントは統合先のWordのものを残す
+	wordUpdate := model.WordUpdate{
+		Id:           word.Id,
+		Word:         word.Word,
+		Memo:         memo,
+		Reading:      word.Reading,
+		PartOfSpeech: word.PartOfSpeech,
+		PitchAccent:  word.PitchAccent,
+		LoginUserId:  loginUserId,
+	}
+	mergedWord, err := wu.wr.UpdateWord(wordUpdate)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	// 統合元のWordに紐づいていたSentenceは、追加したNotationにより統合先のWordに紐づけなおされる
+	mergedWord.AssociationJobId, err = wu.reAssociateWordLater(loginUserId, word.Id)
+	if err != nil {
+		return model.Word{}, err
+	}
+
+	return mergedWord, nil
+}
+
+func mergeMemos(memo, fromMemo, strategy string) string {
+	switch strategy {
+	case model.MemoMergeKeep:
+		return memo
+	case model.MemoMergeSource:
+		return fromMemo
+	default:
+		// 空のメモは連結しない
+		if memo == "" {
+			return fromMemo
+		}
+		if fromMemo == "" {
+			return memo
+		}
+
+		return memo + "\n" + fromMemo
+	}
+}
+
 func (wu *WordUsecase) GetAllNotations(loginUserId, wordId uint64) ([]model.Notation, error) {
-	// wordIdの所有者がloginUserIdの場合ゼロ値を返す
+	// wordIdの所有者がloginUserIdでない場合エラーを返す
 	isWordOwner, err := wu.wr.IsWordOwner(wordId, loginUserId)
 	if err != nil {
 		return []model.Notation{}, err
 	}
 	if !isWordOwner {
-		return []model.Notation{}, nil
+		return []model.Notation{}, ErrWordNotFound
 	}
 
 	notations, err := wu.nr.GetAllNotations(wordId)
@@ -147,7 +592,39 @@ func (wu *WordUsecase) GetAllNotations(loginUserId, wordId uint64) ([]model.Nota
 	return notations, nil
 }
 
+func (wu *WordUsecase) GetNotationsByKind(loginUserId, wordId uint64, kind string) ([]model.Notation, error) {
+	// wordIdの所有者がloginUserIdでない場合エラーを返す
+	isWordOwner, err := wu.wr.IsWordOwner(wordId, loginUserId)
+	if err != nil {
+		return []model.Notation{}, err
+	}
+	if !isWordOwner {
+		return []model.Notation{}, ErrWordNotFound
+	}
+
+	notations, err := wu.nr.GetNotationsByKind(wordId, kind)
+	if err != nil {
+		return []model.Notation{}, err
+	}
+
+	return notations, nil
+}
+
 func (wu *WordUsecase) CreateNotation(notationCreation model.NotationCreation) (model.Notation, error) {
+	var createdNotation model.Notation
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		createdNotation, err = wu.withRepositories(repos).createNotation(notationCreation)
+		return err
+	})
+	if err != nil {
+		return model.Notation{}, err
+	}
+
+	return createdNotation, nil
+}
+
+func (wu *WordUsecase) createNotation(notationCreation model.NotationCreation) (model.Notation, error) {
 	loginUserId := notationCreation.LoginUserId
 
 	// 追加先のWordIdの所有者がloginUserIdでない場合何もしない
@@ -156,27 +633,48 @@ func (wu *WordUsecase) CreateNotation(notationCreation model.NotationCreation) (
 		return model.Notation{}, err
 	}
 	if !isWordOwner {
-		return model.Notation{}, nil
+		return model.Notation{}, ErrWordNotFound
 	}
 
 	createdNotation, err := wu.nr.InsertNotation(notationCreation)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			// 同じWordに同じNotationが既に存在し、追加されなかった場合
+			return model.Notation{}, wu.newDuplicateNotationError(notationCreation.WordId, notationCreation.Notation)
+		}
+
 		return model.Notation{}, err
 	}
 
 	// 既存のSentenceに追加されたWord含まれればsentences_wordsに追加
-	wu.AssociateWordWithAllSentences(loginUserId, createdNotation.WordId)
+	createdNotation.AssociationJobId, err = wu.reAssociateWordLater(loginUserId, createdNotation.WordId)
+	if err != nil {
+		return model.Notation{}, err
+	}
 
 	return createdNotation, nil
 }
 
 func (wu *WordUsecase) UpdateNotation(notationUpdate model.NotationUpdate) (model.Notation, error) {
+	var updatedNotation model.Notation
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		updatedNotation, err = wu.withRepositories(repos).updateNotation(notationUpdate)
+		return err
+	})
+	if err != nil {
+		return model.Notation{}, err
+	}
+
+	return updatedNotation, nil
+}
+
+func (wu *WordUsecase) updateNotation(notationUpdate model.NotationUpdate) (model.Notation, error) {
 	notation, err := wu.nr.GetNotationById(notationUpdate.Id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// 更新対象のNotationが存在しない場合
-			// Notationのゼロ値を返す
-			return model.Notation{}, nil
+			return model.Notation{}, ErrNotationNotFound
 		}
 
 		return model.Notation{}, err
@@ -188,32 +686,62 @@ func (wu *WordUsecase) UpdateNotation(notationUpdate model.NotationUpdate) (mode
 		return model.Notation{}, err
 	}
 	if !isWordOwner {
-		return model.Notation{}, nil
+		return model.Notation{}, ErrNotationNotFound
 	}
 
+	// 同じWordの他のNotationと同じ表記には更新できない
+	if notationUpdate.Notation != notation.Notation {
+		duplicateNotation, err := wu.nr.GetNotationByNotation(notation.WordId, notationUpdate.Notation)
+		if err != nil && err != sql.ErrNoRows {
+			return model.Notation{}, err
+		}
+		if err == nil {
+			return model.Notation{}, NewDuplicateError("notation", model.DuplicateErrorDetails{ExistingId: duplicateNotation.Id})
+		}
+	}
+
+	// 自動生成されたNotationも、編集した時点でユーザーが入力したものとして扱う
+	// Wordの更新時に削除・再生成されないようにするため
+	notationUpdate.Kind = model.NotationKindManual
+
 	updatedNotation, err := wu.nr.UpdateNotation(notationUpdate)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが更新されなかった場合
-			// Notationのゼロ値を返す
-			return model.Notation{}, nil
+			return model.Notation{}, ErrNotationNotFound
 		}
 
 		return model.Notation{}, err
 	}
 	
-	wu.ReAssociateWordWithAllSentences(notationUpdate.LoginUserId, notation.WordId)
+	updatedNotation.AssociationJobId, err = wu.reAssociateWordLater(notationUpdate.LoginUserId, notation.WordId)
+	if err != nil {
+		return model.Notation{}, err
+	}
 
 	return updatedNotation, nil
 }
 
 func (wu *WordUsecase) DeleteNotation(loginUserId, notationId uint64) (model.Notation, error) {
+	var deletedNotation model.Notation
+	err := wu.uow.Do(func(repos repository.Repositories) error {
+		var err error
+		deletedNotation, err = wu.withRepositories(repos).deleteNotation(loginUserId, notationId)
+		return err
+	})
+	if err != nil {
+		return model.Notation{}, err
+	}
+
+	return deletedNotation, nil
+}
+
+func (wu *WordUsecase) deleteNotation(loginUserId, notationId uint64) (model.Notation, error) {
 	notation, err := wu.nr.GetNotationById(notationId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// 削除対象のNotationが存在しない場合
-			// Notationのゼロ値を返す
-			return model.Notation{}, nil
+			return model.Notation{}, ErrNotationNotFound
 		}
 
 		return model.Notation{}, err
@@ -225,21 +753,23 @@ func (wu *WordUsecase) DeleteNotation(loginUserId, notationId uint64) (model.Not
 		return model.Notation{}, err
 	}
 	if !isWordOwner {
-		return model.Notation{}, nil
+		return model.Notation{}, ErrNotationNotFound
 	}
 
 	deletedNotation, err := wu.nr.DeleteNotationById(notationId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// レコードが削除されなかった場合
-			// Notationのゼロ値を返す
-			return model.Notation{}, nil
+			return model.Notation{}, ErrNotationNotFound
 		}
 
 		return model.Notation{}, err
 	}
 
-	wu.ReAssociateWordWithAllSentences(loginUserId, notation.WordId)
+	deletedNotation.AssociationJobId, err = wu.reAssociateWordLater(loginUserId, notation.WordId)
+	if err != nil {
+		return model.Notation{}, err
+	}
 
 	return deletedNotation, nil
 }
@@ -259,46 +789,40 @@ func (wu *WordUsecase)AssociateWordWithAllSentences(userId, wordId uint64) ([]mo
 		return []model.Sentence{}, nil
 	}
 
-	word, err := wu.GetWordById(userId, wordId)
+	word, err := wu.wr.GetWordById(userId, wordId)
 	if err != nil {
 		return []model.Sentence{}, err
 	}
 
+	return wu.associateWordsWithAllSentences(userId, []model.Word{word})
+}
+
+func (wu *WordUsecase) associateWordsWithAllSentences(userId uint64, words []model.Word) ([]model.Sentence, error) {
+	// userIdに紐づく全Sentenceに対し、
+	// Sentenceの中でwordsのいずれかのWordまたはNotationがリンクとして選ばれればsentences_wordsにレコード追加
+	// wordsはuserIdの所有するWordであることを前提とする
+	// Sentenceの取得は、wordsの件数によらず1回のみ行う
+
 	userSentences, err := wu.sr.GetAllSentences(userId)
 	if err != nil {
 		return []model.Sentence{}, err
 	}
 
-	var associatedSentences []model.Sentence
-	for _, sentence := range userSentences {
-		// Sentence中にWordが含まれるか判定
-		if strings.Contains(sentence.Sentence, word.Word) {
-			err = wu.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
-			if err != nil {
-				return []model.Sentence{}, err
-			}
-			associatedSentences = append(associatedSentences, sentence)
-			// Sentenceの中にWordが含まれる場合、
-			// continueし、Notationが含まれるかの判定はしない
-			continue
-		}
+	targetWordIds := map[uint64]bool{}
+	for _, word := range words {
+		targetWordIds[word.Id] = true
+	}
 
-		// Sentence中にNotationが含まれるか判定
-		notations, err := wu.nr.GetAllNotations(word.Id)
-		if err != nil {
-			return []model.Sentence{}, err
-		}
+	selectedWordIdsBySentence, err := wu.resolveSentencesWords(userId, userSentences, targetWordIds)
+	if err != nil {
+		return []model.Sentence{}, err
+	}
 
-		for _, notation := range notations {
-			if strings.Contains(sentence.Sentence, notation.Notation) {
-				err = wu.swr.AssociateSentenceWithWord(sentence.Id, word.Id)
-				if err != nil {
-					return []model.Sentence{}, err
-				}
+	var associatedSentences []model.Sentence
+	for _, word := range words {
+		for i, sentence := range userSentences {
+			if selectedWordIdsBySentence[i][word.Id] {
 				associatedSentences = append(associatedSentences, sentence)
-				// Sentenceの中にNotationが含まれる場合、
-				// sentences_wordsに2つ目のレコードが追加されないようbreak
-				break
 			}
 		}
 	}
@@ -306,84 +830,196 @@ func (wu *WordUsecase)AssociateWordWithAllSentences(userId, wordId uint64) ([]mo
 	return associatedSentences, nil
 }
 
-func (wu *WordUsecase)ReAssociateWordWithAllSentences(loginUserId, wordId uint64) error {
-	// wordIdで指定されるWordと、全Sentenceのsentences_wordsを再構築
-	// sentences_wordsからwordIdのレコードを全削除し、もう一度追加しなおす
+func (wu *WordUsecase) resolveSentencesWords(userId uint64, sentences []model.Sentence, targetWordIds map[uint64]bool) ([]map[uint64]bool, error) {
+	// userIdの全Wordから、sentencesの各Sentence中でリンクとなるWordを決め、sentences_wordsをその結果に合わせる
+	// 「日本語」中の「日本」のように、他のWordと重なり合うためリンクとならないWordは紐づけない
+	// targetWordIdsがnilでない場合、targetWordIdsのWordが出現するSentenceのsentences_wordsのみを作りなおす
+	// Sentenceごとに、リンクとなったWordのIdを返す
 
-	// sentenceIdの所有者がloginUserIdでない場合何もしない
-	isWordOwner, err := wu.wr.IsWordOwner(wordId, loginUserId)
+	wf, err := newUserWordFinder(wu.m, wu.lr, wu.wr, wu.nr, wu.dr, userId)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !isWordOwner {
-		return nil
+
+	// ユーザーが手動で指定した紐づけは、再構築時も優先する
+	overridesBySentenceId, err := getOverridesBySentenceId(wu.swr, sentences)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: 削除～再追加はトランザクション内で行う
+	// 各Sentenceの探索は、Wordの件数によらず1回のみ行う
+	selectedWordIdsBySentence := make([]map[uint64]bool, len(sentences))
+	var resolvedSentenceIds []uint64
+	var sentencesWords []model.SentenceWord
+	var occurrences []model.SentenceWordOccurrence
+	for i, sentence := range sentences {
+		association := wf.associate(sentence, overridesBySentenceId[sentence.Id])
+		selectedWordIdsBySentence[i] = association.wordIds
+
+		if targetWordIds != nil && !containsTargetWord(association, targetWordIds) {
+			// targetWordIdsのWordが出現せず、紐づけも指定されていないSentenceは、紐づけが変わらない
+			continue
+		}
 
-	// sentences_wordsからwordIdのレコードを全削除
-	err = wu.swr.DeleteAllAssociationByWordId(wordId)
+		resolvedSentenceIds = append(resolvedSentenceIds, sentence.Id)
+		for _, word := range wf.words {
+			if association.wordIds[word.Id] {
+				sentencesWords = append(sentencesWords, model.SentenceWord{SentenceId: sentence.Id, WordId: word.Id})
+			}
+		}
+		occurrences = append(occurrences, association.occurrences...)
+	}
 
-	// sentences_wordsに再追加
-	wu.AssociateWordWithAllSentences(loginUserId, wordId)
+	// 削除、追加はSentenceの件数によらずそれぞれ1回のクエリで行う
+	// 出現箇所は、紐づけの削除とともに削除される
+	err = wu.swr.DeleteAllAssociationBySentenceIds(resolvedSentenceIds)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	err = wu.swr.AssociateSentencesWithWords(sentencesWords)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wu.swr.InsertOccurrences(occurrences)
+	if err != nil {
+		return nil, err
+	}
+
+	return selectedWordIdsBySentence, nil
 }
 
-func (wu *WordUsecase) getIgnoringWordEnding() []string {
-	// 無視する語尾のリスト
-	// wordが以下の語尾で終わる場合、語尾を抜いた語をNotationに追加
+func containsTargetWord(association sentenceAssociation, targetWordIds map[uint64]bool) bool {
+	// リンクとして選ばれたか否かによらず、targetWordIdsのWordが出現した、または紐づけられるかを判定
+	for wordId := range association.wordIds {
+		if targetWordIds[wordId] {
+			return true
+		}
+	}
+
+	resolution := association.resolution
+	for _, candidate := range resolution.Selected {
+		if targetWordIds[candidate.WordId] {
+			return true
+		}
+	}
+	for _, discarded := range resolution.Discarded {
+		if targetWordIds[discarded.WordId] {
+			return true
+		}
+	}
 
-	// Word「買う」を追加するとき、「買わない」「買いたい」などにもマッチさせるため、
-	// 最初から「買」をNotationに追加させておく用途で使用。
-	
-	ignoringWordEnding := []string{
-		"う", // 買う -> 買
-		"く", // 聞く -> 聞
-		"す", // 直す -> 直
-		"つ", // 打つ -> 打
-		"む", // 霞む -> 霞
-		"る", // 走る -> 走
-		"い", // 暗い -> 暗
+	return false
+}
+
+func (wu *WordUsecase) reAssociateWordLater(loginUserId, wordId uint64) (uint64, error) {
+	// wordIdのsentences_wordsを再構築
+	// 非同期で行う場合はジョブを追加してそのIdを返し、リクエスト内で行う場合は0を返す
+	if wu.mode == AssociationModeAsync {
+		return enqueueAssociationJob(wu.jr, loginUserId, model.JobKindReassociateWords, []uint64{wordId})
 	}
 
-	return ignoringWordEnding
+	return 0, wu.ReAssociateWordWithAllSentences(loginUserId, wordId)
+}
+
+func (wu *WordUsecase)ReAssociateWordWithAllSentences(loginUserId, wordId uint64) error {
+	// wordIdで指定されるWordと、全Sentenceのsentences_wordsを再構築
+	// sentences_wordsからwordIdのレコードを全削除し、もう一度追加しなおす
+	// 削除～再追加はトランザクション内で行う
+	return wu.ReAssociateWordsWithAllSentences(loginUserId, []uint64{wordId})
 }
 
-func (wu *WordUsecase) createRootNotation(loginUserId uint64, word model.Word) error {
-	// wordの語幹をnotationに追加
+func (wu *WordUsecase) ReAssociateWordsWithAllSentences(loginUserId uint64, wordIds []uint64) error {
+	// wordIdsで指定される各Wordと、全Sentenceのsentences_wordsを再構築
+	// 削除～再追加はトランザクション内で行う
+	return wu.uow.Do(func(repos repository.Repositories) error {
+		return wu.withRepositories(repos).reAssociateWordsWithAllSentences(loginUserId, wordIds)
+	})
+}
 
-	for _, wordEnding := range wu.getIgnoringWordEnding() {
-		if strings.HasSuffix(word.Word, wordEnding) {
-			notationCreation := model.NotationCreation{
-				WordId: word.Id,
-				Notation: word.Word[:len(word.Word) - len(wordEnding)],
-				LoginUserId: word.UserId,
+func (wu *WordUsecase) reAssociateWordsWithAllSentences(loginUserId uint64, wordIds []uint64) error {
+	// ジョブの実行時には既に削除されている場合があるため、存在しないWordは飛ばす
+	var words []model.Word
+	var previousSentences []model.Sentence
+	previousSentenceIds := map[uint64]bool{}
+	for _, wordId := range wordIds {
+		// wordIdの所有者がloginUserIdでない場合も、レコードが無いため飛ばす
+		word, err := wu.wr.GetWordById(loginUserId, wordId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
 			}
-			
-			_, err := wu.CreateNotation(notationCreation)
-			if err != nil {
-				return err
+
+			return err
+		}
+		words = append(words, word)
+
+		// 更新前にwordIdが紐づいていたSentenceは、重なり合っていた他のWordがリンクとなる場合があるため、
+		// 削除前に取得しておき、後でsentences_wordsを作りなおす
+		sentences, err := wu.swr.GetUserAssociatedSentencesByWordId(wordId)
+		if err != nil {
+			return err
+		}
+		for _, sentence := range sentences {
+			if previousSentenceIds[sentence.Id] {
+				continue
 			}
+			previousSentenceIds[sentence.Id] = true
+			previousSentences = append(previousSentences, sentence)
 		}
+
+		// sentences_wordsからwordIdのレコードを全削除
+		err = wu.swr.DeleteAllAssociationByWordId(wordId)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	// sentences_wordsに再追加
+	_, err := wu.associateWordsWithAllSentences(loginUserId, words)
+	if err != nil {
+		return err
+	}
+
+	_, err = wu.resolveSentencesWords(loginUserId, previousSentences, nil)
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (wu *WordUsecase) deleteRootNotation(loginUserId uint64, word model.Word) error {
-	// wordの語幹をnotationから削除
-
-	for _, wordEnding := range wu.getIgnoringWordEnding() {
-		if strings.HasSuffix(word.Word, wordEnding) {
-			notation := word.Word[:len(word.Word) - len(wordEnding)]
+func (wu *WordUsecase) createConjugationNotations(word model.Word) error {
+	// wordの活用形をnotationに追加
+	// Word「買う」を追加するとき、「買わない」「買いたい」などにもマッチさせるため、
+	// 活用形をNotationに追加させておく用途で使用。
+	// 品詞が入力されている場合は品詞に従って活用させ、未入力の場合は語尾から推定する
+	// sentences_wordsへの追加は行わないため、呼び出し元で行う
+
+	for _, conjugation := range Conjugate(word.Word, WordClassOf(word.Word, word.PartOfSpeech)) {
+		notationCreation := model.NotationCreation{
+			WordId: word.Id,
+			Notation: conjugation.Text,
+			Kind: model.NotationKindConjugation,
+			LoginUserId: word.UserId,
+		}
 
-			_, err := wu.nr.DeleteNotationIfExists(word.Id, notation)
-			if err != nil {
-				return err
+		_, err := wu.nr.InsertNotation(notationCreation)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// 活用形と同じNotationが既に存在する場合
+				// ユーザーが入力したNotationと重複する場合は、ユーザーが入力したものを残す
+				continue
 			}
+
+			return err
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions (
+  id VARCHAR(64) PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TRIGGER refresh_sessions_updated_at
+  BEFORE UPDATE ON sessions FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_sessions_updated_at ON sessions;
+DROP TABLE sessions;
+-- +goose StatementEnd
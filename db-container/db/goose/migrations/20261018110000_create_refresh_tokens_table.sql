@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE refresh_token_id_seq;
+
+CREATE TABLE refresh_tokens (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  family_id VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+  replaced_by INTEGER,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (replaced_by) REFERENCES refresh_tokens(id)
+    ON DELETE SET NULL
+    ON UPDATE CASCADE
+);
+
+CREATE INDEX refresh_tokens_family_id_index ON refresh_tokens(family_id);
+
+CREATE TRIGGER refresh_refresh_tokens_updated_at
+  BEFORE UPDATE ON refresh_tokens FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_refresh_tokens_updated_at ON refresh_tokens;
+DROP TABLE refresh_tokens;
+DROP SEQUENCE refresh_token_id_seq;
+-- +goose StatementEnd
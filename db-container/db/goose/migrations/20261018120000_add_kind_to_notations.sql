@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- manual: ユーザーが入力したNotation
+-- stem: Wordの語幹から自動生成されたNotation
+-- conjugation: Wordの活用形から自動生成されたNotation
+-- import: 外部から取り込まれたNotation
+ALTER TABLE notations
+  ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'manual'
+  CHECK (kind IN ('manual', 'stem', 'conjugation', 'import'));
+
+-- 既存の語幹のNotationは、Wordから語尾1文字を除いたものとして自動生成されていたため、
+-- 同じ条件に当てはまるものをstemとする
+UPDATE notations
+SET kind = 'stem'
+FROM words
+WHERE notations.word_id = words.id
+  AND RIGHT(words.word, 1) IN ('う', 'く', 'す', 'つ', 'む', 'る', 'い')
+  AND notations.notation = LEFT(words.word, LENGTH(words.word) - 1);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notations DROP COLUMN kind;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Word一覧のカーソルページネーション用
+-- 並び替え可能な列ごとに、(user_id, 列, id) の順で索引を作成
+CREATE INDEX words_user_id_word_id_index ON words (user_id, word, id);
+CREATE INDEX words_user_id_created_at_id_index ON words (user_id, created_at, id);
+CREATE INDEX words_user_id_updated_at_id_index ON words (user_id, updated_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX words_user_id_updated_at_id_index;
+DROP INDEX words_user_id_created_at_id_index;
+DROP INDEX words_user_id_word_id_index;
+-- +goose StatementEnd
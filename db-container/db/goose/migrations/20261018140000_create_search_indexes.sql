@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 検索 (GET /search) の部分一致・あいまい検索用
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX words_word_trgm_index ON words USING GIN (word gin_trgm_ops);
+CREATE INDEX words_memo_trgm_index ON words USING GIN (memo gin_trgm_ops);
+CREATE INDEX notations_notation_trgm_index ON notations USING GIN (notation gin_trgm_ops);
+CREATE INDEX sentences_sentence_trgm_index ON sentences USING GIN (sentence gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX sentences_sentence_trgm_index;
+DROP INDEX notations_notation_trgm_index;
+DROP INDEX words_memo_trgm_index;
+DROP INDEX words_word_trgm_index;
+DROP EXTENSION IF EXISTS pg_trgm;
+-- +goose StatementEnd
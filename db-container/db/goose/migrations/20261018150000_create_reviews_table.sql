@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE review_id_seq;
+
+-- Wordごとの復習の状態
+-- reviewsにレコードが無いWordは、まだ1度も復習していないWordとして扱う
+CREATE TABLE reviews (
+  id INTEGER PRIMARY KEY,
+  word_id INTEGER NOT NULL UNIQUE,
+  ease_factor DOUBLE PRECISION NOT NULL,
+  interval_days INTEGER NOT NULL,
+  repetitions INTEGER NOT NULL,
+  due_at TIMESTAMPTZ NOT NULL,
+  last_grade INTEGER NOT NULL,
+  last_reviewed_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE INDEX reviews_due_at_index ON reviews(due_at);
+
+CREATE TRIGGER refresh_reviews_updated_at
+  BEFORE UPDATE ON reviews FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_reviews_updated_at ON reviews;
+DROP TABLE reviews;
+DROP SEQUENCE review_id_seq;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE job_id_seq;
+
+-- WordとSentenceの紐づけの再構築など、リクエストとは非同期に実行する処理のキュー
+-- APIプロセス内のワーカーが FOR UPDATE SKIP LOCKED で1件ずつ取り出して実行する
+CREATE TABLE jobs (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  kind TEXT NOT NULL,
+  -- kindに応じたWordまたはSentenceのId
+  target_ids INTEGER[] NOT NULL,
+  -- pending: 実行待ち, running: 実行中, succeeded: 成功, dead: 最大試行回数に達しても成功しなかった
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL,
+  last_error TEXT NOT NULL DEFAULT '',
+  -- この日時以降に実行する
+  run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_at TIMESTAMPTZ,
+  finished_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+-- 実行待ちのジョブの取り出しに使用
+CREATE INDEX jobs_status_run_at_index ON jobs(status, run_at);
+-- WordまたはSentenceの、未完了のジョブの検索に使用
+CREATE INDEX jobs_target_ids_index ON jobs USING GIN (target_ids);
+
+CREATE TRIGGER refresh_jobs_updated_at
+  BEFORE UPDATE ON jobs FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_jobs_updated_at ON jobs;
+DROP TABLE jobs;
+DROP SEQUENCE job_id_seq;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- sentences_wordsの紐づけごとの、Sentence中でのWordの出現箇所
+-- 紐づけの作成時に記録し、リンクやクローズ問題の作成時にSentenceを再度探索せずに使用する
+CREATE TABLE sentence_word_occurrences (
+  sentence_id INTEGER NOT NULL,
+  word_id INTEGER NOT NULL,
+  -- 一致したNotation
+  -- Word自体に一致した場合はNULL
+  notation_id INTEGER,
+  -- Sentence中の出現箇所（文字単位、end_offsetの文字は含まない）
+  start_offset INTEGER NOT NULL,
+  end_offset INTEGER NOT NULL,
+  -- 他のWordと重なり合うため、リンクとして選ばれなかった理由
+  -- リンクとして選ばれた場合は空文字列
+  discard_reason TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY(sentence_id, word_id, start_offset, end_offset),
+  -- 紐づけが削除された場合、出現箇所も削除する
+  FOREIGN KEY (sentence_id, word_id) REFERENCES sentences_words(sentence_id, word_id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (notation_id) REFERENCES notations(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+-- 既存の紐づけの出現箇所を記録するため、全Sentenceの紐づけを再構築するジョブを追加
+INSERT INTO jobs
+(id, user_id, kind, target_ids, max_attempts)
+SELECT nextval('job_id_seq'), user_id, 'reassociate_sentences', array_agg(id ORDER BY id), 5
+FROM sentences
+GROUP BY user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sentence_word_occurrences;
+-- +goose StatementEnd
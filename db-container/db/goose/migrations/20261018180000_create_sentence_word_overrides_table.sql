@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ユーザーが手動で指定した、SentenceとWordの紐づけ
+-- sentences_wordsは紐づけの再構築のたびに削除・再作成されるため、別のテーブルに保存する
+CREATE TABLE sentence_word_overrides (
+  sentence_id INTEGER NOT NULL,
+  word_id INTEGER NOT NULL,
+  -- pin: Sentence中に出現しなくても紐づける
+  -- exclude: Sentence中に出現しても紐づけない
+  kind TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(sentence_id, word_id),
+  FOREIGN KEY (sentence_id) REFERENCES sentences(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TRIGGER refresh_sentence_word_overrides_updated_at
+  BEFORE UPDATE ON sentence_word_overrides FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_sentence_word_overrides_updated_at ON sentence_word_overrides;
+DROP TABLE sentence_word_overrides;
+-- +goose StatementEnd
@@ -0,0 +1,108 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 制約を追加する前に、既存の重複を統合する
+-- 同じUserの同じWordは、最も小さいidのWordに統合する
+CREATE TEMPORARY TABLE duplicate_words ON COMMIT DROP AS
+SELECT id, MIN(id) OVER (PARTITION BY user_id, word) AS kept_id
+FROM words;
+
+DELETE FROM duplicate_words
+WHERE id = kept_id;
+
+-- 統合先のWordに存在しないNotationのみ移す
+-- 移さなかったNotationは、重複したWordとともに削除される
+UPDATE notations n
+SET word_id = d.kept_id
+FROM duplicate_words d
+WHERE n.word_id = d.id
+  AND NOT EXISTS(
+    SELECT 1
+    FROM notations k
+    WHERE k.word_id = d.kept_id
+      AND k.notation = n.notation
+  );
+
+-- 統合先のWordにメモが無い場合（NULLを含む）、重複したWordのメモを引き継ぐ
+UPDATE words w
+SET memo = m.memo
+FROM (
+  SELECT DISTINCT ON (d.kept_id) d.kept_id, dw.memo
+  FROM duplicate_words d
+  JOIN words dw ON dw.id = d.id
+  WHERE dw.memo <> ''
+  ORDER BY d.kept_id, d.id
+) m
+WHERE w.id = m.kept_id
+  AND COALESCE(w.memo, '') = '';
+
+-- 統合先のWordに無い紐づけの上書きと復習の状態を引き継ぐ
+-- 複数の重複したWordにある場合は、最も小さいidのWordのものを引き継ぐ
+UPDATE sentence_word_overrides o
+SET word_id = d.kept_id
+FROM duplicate_words d
+WHERE o.word_id = d.id
+  AND o.word_id = (
+    SELECT MIN(o2.word_id)
+    FROM sentence_word_overrides o2
+    JOIN duplicate_words d2 ON d2.id = o2.word_id
+    WHERE d2.kept_id = d.kept_id
+      AND o2.sentence_id = o.sentence_id
+  )
+  AND NOT EXISTS(
+    SELECT 1
+    FROM sentence_word_overrides k
+    WHERE k.word_id = d.kept_id
+      AND k.sentence_id = o.sentence_id
+  );
+
+UPDATE reviews r
+SET word_id = d.kept_id
+FROM duplicate_words d
+WHERE r.word_id = d.id
+  AND r.id = (
+    SELECT MIN(r2.id)
+    FROM reviews r2
+    JOIN duplicate_words d2 ON d2.id = r2.word_id
+    WHERE d2.kept_id = d.kept_id
+  )
+  AND NOT EXISTS(
+    SELECT 1
+    FROM reviews k
+    WHERE k.word_id = d.kept_id
+  );
+
+-- 移したNotationを含めて、統合先のWordとSentenceの紐づけを再構築する
+INSERT INTO jobs
+(id, user_id, kind, target_ids, max_attempts)
+SELECT nextval('job_id_seq'), w.user_id, 'reassociate_words', ARRAY_AGG(DISTINCT d.kept_id), 5
+FROM duplicate_words d
+JOIN words w ON w.id = d.kept_id
+GROUP BY w.user_id;
+
+-- sentences_words、sentence_word_occurrencesなどは外部キーにより削除される
+DELETE FROM words
+WHERE id IN (SELECT id FROM duplicate_words);
+
+-- 同じWordの同じNotationは、最も小さいidのNotationを残す
+DELETE FROM notations n
+USING notations k
+WHERE n.word_id = k.word_id
+  AND n.notation = k.notation
+  AND n.id > k.id;
+
+ALTER TABLE words
+  ADD CONSTRAINT words_user_id_word_key UNIQUE (user_id, word);
+
+ALTER TABLE notations
+  ADD CONSTRAINT notations_word_id_notation_key UNIQUE (word_id, notation);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- 統合したWordとNotationは元に戻さない
+ALTER TABLE notations
+  DROP CONSTRAINT notations_word_id_notation_key;
+
+ALTER TABLE words
+  DROP CONSTRAINT words_user_id_word_key;
+-- +goose StatementEnd
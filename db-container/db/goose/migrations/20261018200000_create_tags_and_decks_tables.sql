@@ -0,0 +1,123 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE tag_id_seq;
+
+-- WordとSentenceを分類するための、ユーザーが定義したタグ
+CREATE TABLE tags (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE(user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TABLE words_tags (
+  word_id INTEGER NOT NULL,
+  tag_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(word_id, tag_id),
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (tag_id) REFERENCES tags(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TABLE sentences_tags (
+  sentence_id INTEGER NOT NULL,
+  tag_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(sentence_id, tag_id),
+  FOREIGN KEY (sentence_id) REFERENCES sentences(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (tag_id) REFERENCES tags(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+-- タグでの絞り込みに使用
+CREATE INDEX words_tags_tag_id_index ON words_tags(tag_id);
+CREATE INDEX sentences_tags_tag_id_index ON sentences_tags(tag_id);
+
+CREATE TRIGGER refresh_tags_updated_at
+  BEFORE UPDATE ON tags FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+
+CREATE SEQUENCE deck_id_seq;
+
+-- WordとSentenceをまとめる、階層構造を持つデッキ
+-- 親のデッキは、子孫のデッキのWordとSentenceも含むものとして扱う
+CREATE TABLE decks (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  -- 最上位のデッキはNULL
+  parent_id INTEGER,
+  name VARCHAR(100) NOT NULL,
+  -- trueの場合、このデッキのSentenceは同じデッキのWordのみと紐づける
+  scope_association BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  -- 同じ親の下に同じ名前のデッキは作れない（最上位のデッキ同士も含む）
+  UNIQUE NULLS NOT DISTINCT (user_id, parent_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES decks(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TABLE decks_words (
+  deck_id INTEGER NOT NULL,
+  word_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(deck_id, word_id),
+  FOREIGN KEY (deck_id) REFERENCES decks(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TABLE decks_sentences (
+  deck_id INTEGER NOT NULL,
+  sentence_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(deck_id, sentence_id),
+  FOREIGN KEY (deck_id) REFERENCES decks(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (sentence_id) REFERENCES sentences(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+-- 子のデッキの取得に使用
+CREATE INDEX decks_parent_id_index ON decks(parent_id);
+
+CREATE TRIGGER refresh_decks_updated_at
+  BEFORE UPDATE ON decks FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_decks_updated_at ON decks;
+DROP TABLE decks_sentences;
+DROP TABLE decks_words;
+DROP TABLE decks;
+DROP SEQUENCE deck_id_seq;
+
+DROP TRIGGER refresh_tags_updated_at ON tags;
+DROP TABLE sentences_tags;
+DROP TABLE words_tags;
+DROP TABLE tags;
+DROP SEQUENCE tag_id_seq;
+-- +goose StatementEnd
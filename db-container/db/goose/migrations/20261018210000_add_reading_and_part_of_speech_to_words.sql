@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reading: Wordの読み（仮名）。未入力の場合は空文字列
+-- part_of_speech: Wordの品詞。未入力の場合は空文字列とし、活用形はWordの語尾から推定する
+-- pitch_accent: アクセント核の位置（0は平板型）。未入力の場合はNULL
+ALTER TABLE words
+  ADD COLUMN reading VARCHAR(100) NOT NULL DEFAULT '',
+  ADD COLUMN part_of_speech VARCHAR(20) NOT NULL DEFAULT ''
+  CHECK (part_of_speech IN (
+    '', 'noun', 'godan_verb', 'ichidan_verb', 'suru_verb', 'kuru_verb',
+    'i_adjective', 'na_adjective', 'adverb', 'other'
+  )),
+  ADD COLUMN pitch_accent SMALLINT
+  CHECK (pitch_accent >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE words
+  DROP COLUMN pitch_accent,
+  DROP COLUMN part_of_speech,
+  DROP COLUMN reading;
+-- +goose StatementEnd
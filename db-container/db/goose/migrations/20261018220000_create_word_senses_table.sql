@@ -0,0 +1,69 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE word_sense_id_seq;
+
+-- Wordの意味
+-- 複数の意味を持つWordについて、意味ごとに訳語や品詞を保存する
+CREATE TABLE word_senses (
+  id INTEGER PRIMARY KEY,
+  word_id INTEGER NOT NULL,
+  -- 意味の説明、訳語
+  gloss VARCHAR(500) NOT NULL,
+  -- glossの言語（BCP 47の言語タグ）
+  language VARCHAR(35) NOT NULL,
+  -- 未入力の場合は空文字列
+  part_of_speech VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (part_of_speech IN ('', 'noun', 'godan_verb', 'ichidan_verb', 'suru_verb', 'kuru_verb', 'i_adjective', 'na_adjective', 'adverb', 'other')),
+  -- 使用される場面、文体
+  -- 未入力の場合は空文字列
+  register VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (register IN ('', 'formal', 'informal', 'polite', 'honorific', 'humble', 'colloquial', 'slang', 'literary', 'archaic')),
+  -- Word内での表示順（昇順、同じ場合はid順）
+  position INTEGER NOT NULL CHECK (position >= 1),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  -- sentence_word_sensesから、Wordと意味の組で参照するため
+  UNIQUE(id, word_id),
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE INDEX word_senses_word_id_position_index ON word_senses(word_id, position);
+
+CREATE TRIGGER refresh_word_senses_updated_at
+  BEFORE UPDATE ON word_senses FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+
+-- SentenceがWordのどの意味の例文であるか
+-- sentences_wordsは紐づけの再構築のたびに削除・再作成されるため、別のテーブルに保存する
+CREATE TABLE sentence_word_senses (
+  sentence_id INTEGER NOT NULL,
+  word_id INTEGER NOT NULL,
+  sense_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(sentence_id, word_id),
+  FOREIGN KEY (sentence_id) REFERENCES sentences(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  -- 意味はword_idのWordのものに限る
+  -- 意味を他のWordに移した場合は、word_idも合わせて更新される
+  FOREIGN KEY (sense_id, word_id) REFERENCES word_senses(id, word_id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TRIGGER refresh_sentence_word_senses_updated_at
+  BEFORE UPDATE ON sentence_word_senses FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_sentence_word_senses_updated_at ON sentence_word_senses;
+DROP TABLE sentence_word_senses;
+DROP TRIGGER refresh_word_senses_updated_at ON word_senses;
+DROP TABLE word_senses;
+DROP SEQUENCE word_sense_id_seq;
+-- +goose StatementEnd
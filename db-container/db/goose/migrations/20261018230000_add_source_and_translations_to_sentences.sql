@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sentenceの出典とメモ
+-- 未入力の場合は空文字列、再生位置はNULLとする
+ALTER TABLE sentences
+  -- 書籍、番組などの名前
+  ADD COLUMN source_title VARCHAR(200) NOT NULL DEFAULT '',
+  ADD COLUMN source_url VARCHAR(2000) NOT NULL DEFAULT '',
+  -- 話数、章など
+  ADD COLUMN source_episode VARCHAR(100) NOT NULL DEFAULT '',
+  -- 動画、音声中の再生位置（秒）
+  ADD COLUMN source_timestamp_seconds INTEGER CHECK (source_timestamp_seconds >= 0),
+  ADD COLUMN notes VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- 出典での絞り込みに使用
+CREATE INDEX sentences_user_id_source_title_index ON sentences(user_id, source_title);
+CREATE INDEX sentences_user_id_source_url_index ON sentences(user_id, source_url);
+
+-- Sentenceの訳文
+-- 1つのSentenceに、言語ごとに1つの訳文を保存する
+CREATE TABLE sentence_translations (
+  sentence_id INTEGER NOT NULL,
+  -- 訳文の言語（BCP 47の言語タグ）
+  language VARCHAR(35) NOT NULL,
+  translation VARCHAR(1000) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(sentence_id, language),
+  FOREIGN KEY (sentence_id) REFERENCES sentences(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+CREATE TRIGGER refresh_sentence_translations_updated_at
+  BEFORE UPDATE ON sentence_translations FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_sentence_translations_updated_at ON sentence_translations;
+DROP TABLE sentence_translations;
+
+DROP INDEX sentences_user_id_source_url_index;
+DROP INDEX sentences_user_id_source_title_index;
+
+ALTER TABLE sentences
+  DROP COLUMN notes,
+  DROP COLUMN source_timestamp_seconds,
+  DROP COLUMN source_episode,
+  DROP COLUMN source_url,
+  DROP COLUMN source_title;
+-- +goose StatementEnd
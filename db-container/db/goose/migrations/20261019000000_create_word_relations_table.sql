@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE word_relation_id_seq;
+
+-- Word同士の関係
+-- compound、see_alsoはword_idのWordからrelated_word_idのWordへの関係とする
+-- compoundの場合、word_idのWordがrelated_word_idのWordを含む複合語となる
+CREATE TABLE word_relations (
+  id INTEGER PRIMARY KEY,
+  word_id INTEGER NOT NULL,
+  related_word_id INTEGER NOT NULL,
+  kind VARCHAR(20) NOT NULL
+    CHECK (kind IN ('synonym', 'antonym', 'compound', 'see_also')),
+  -- related_word_idのWordからword_idのWordへも同じ関係が成り立つか
+  bidirectional BOOLEAN NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (word_id <> related_word_id),
+  FOREIGN KEY (word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE,
+  FOREIGN KEY (related_word_id) REFERENCES words(id)
+    ON DELETE CASCADE
+    ON UPDATE CASCADE
+);
+
+-- 2つのWordの間には、向きによらず種類ごとに1つの関係のみ保存する
+CREATE UNIQUE INDEX word_relations_pair_kind_index
+  ON word_relations(LEAST(word_id, related_word_id), GREATEST(word_id, related_word_id), kind);
+
+CREATE INDEX word_relations_word_id_index ON word_relations(word_id);
+CREATE INDEX word_relations_related_word_id_index ON word_relations(related_word_id);
+
+CREATE TRIGGER refresh_word_relations_updated_at
+  BEFORE UPDATE ON word_relations FOR EACH ROW
+EXECUTE PROCEDURE refresh_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER refresh_word_relations_updated_at ON word_relations;
+DROP TABLE word_relations;
+DROP SEQUENCE word_relation_id_seq;
+-- +goose StatementEnd